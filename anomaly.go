@@ -0,0 +1,89 @@
+package trn
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Anomaly is an occurrence of the recurring range, which deviates from the
+// typical pattern.
+type Anomaly struct {
+	Range
+	// StartDeviation is the difference between the local time of day of
+	// the occurrence's start and the typical one.
+	StartDeviation time.Duration
+	// DurationDeviation is the difference between the occurrence's
+	// duration and the typical one.
+	DurationDeviation time.Duration
+}
+
+// DetectAnomalies looks for the occurrences of a daily recurring range,
+// e.g. shifts, whose start or duration deviate from the typical pattern
+// by more than the given tolerances. The typical pattern is the median
+// local time of day of the starts in the given location and the median
+// duration. Deviations of the start are measured to the nearest day, so
+// that 23:50 and 00:10 are 20 minutes apart.
+// Anomalies are returned in the order of the history.
+func DetectAnomalies(history []Range, loc *time.Location, startTol, durTol time.Duration) []Anomaly {
+	if len(history) == 0 {
+		return nil
+	}
+
+	clocks := make([]time.Duration, len(history))
+	for i, rng := range history {
+		clocks[i] = timeOfDay(rng.st.In(loc))
+	}
+
+	typicalStart, typicalDur := medianClock(clocks), Stats(history).Median
+
+	var res []Anomaly
+	for i, rng := range history {
+		a := Anomaly{
+			Range:             rng,
+			StartDeviation:    clockDiff(clocks[i], typicalStart),
+			DurationDeviation: rng.dur - typicalDur,
+		}
+		if absDuration(a.StartDeviation) > startTol || absDuration(a.DurationDeviation) > durTol {
+			res = append(res, a)
+		}
+	}
+
+	return res
+}
+
+// medianClock returns the median of the times of day, taking into account
+// that the times of day wrap at midnight: the deviations are measured from
+// the circular mean of the times and the median deviation is applied to it.
+func medianClock(clocks []time.Duration) time.Duration {
+	const day = 24 * time.Hour
+
+	var sin, cos float64
+	for _, c := range clocks {
+		angle := float64(c) / float64(day) * 2 * math.Pi
+		sin, cos = sin+math.Sin(angle), cos+math.Cos(angle)
+	}
+	mean := time.Duration(math.Atan2(sin, cos) / (2 * math.Pi) * float64(day))
+
+	diffs := make([]time.Duration, len(clocks))
+	for i, c := range clocks {
+		diffs[i] = clockDiff(c, mean)
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i] < diffs[j] })
+
+	return ((mean+percentile(diffs, 50))%day + day) % day
+}
+
+// timeOfDay returns the time elapsed since the local midnight of t.
+func timeOfDay(t time.Time) time.Duration {
+	y, m, d := t.Date()
+	return t.Sub(time.Date(y, m, d, 0, 0, 0, 0, t.Location()))
+}
+
+// clockDiff returns the difference between two times of day, wrapped to
+// the nearest day, within [-12h, 12h).
+func clockDiff(a, b time.Duration) time.Duration {
+	const day = 24 * time.Hour
+	d := ((a-b)%day + day + day/2) % day
+	return d - day/2
+}
@@ -0,0 +1,53 @@
+package trn
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectAnomalies(t *testing.T) {
+	history := []Range{
+		MustRange(Between(dhm(1, 9, 0), dhm(1, 17, 0))),
+		MustRange(Between(dhm(2, 9, 5), dhm(2, 17, 0))),
+		MustRange(Between(dhm(3, 11, 0), dhm(3, 19, 0))), // late start
+		MustRange(Between(dhm(4, 8, 55), dhm(4, 17, 0))),
+		MustRange(Between(dhm(5, 9, 0), dhm(5, 13, 0))), // short shift
+		MustRange(Between(dhm(6, 9, 0), dhm(6, 17, 10))),
+	}
+
+	got := DetectAnomalies(history, time.UTC, 30*time.Minute, 30*time.Minute)
+	assert.Equal(t, []Anomaly{
+		{Range: history[2], StartDeviation: 2 * time.Hour, DurationDeviation: 0},
+		{Range: history[4], StartDeviation: 0, DurationDeviation: -4 * time.Hour},
+	}, got)
+
+	assert.Nil(t, DetectAnomalies(nil, time.UTC, time.Minute, time.Minute))
+}
+
+func TestDetectAnomalies_Midnight(t *testing.T) {
+	history := []Range{
+		MustRange(Between(dhm(1, 23, 50), dhm(2, 6, 0))),
+		MustRange(Between(dhm(3, 0, 10), dhm(3, 6, 0))),
+		MustRange(Between(dhm(3, 23, 55), dhm(4, 6, 0))),
+		MustRange(Between(dhm(5, 3, 0), dhm(5, 6, 0))),
+	}
+
+	got := DetectAnomalies(history, time.UTC, 30*time.Minute, 4*time.Hour)
+	assert.Len(t, got, 1)
+	assert.Equal(t, history[3], got[0].Range)
+}
+
+func TestClockDiff(t *testing.T) {
+	assert.Equal(t, 20*time.Minute, clockDiff(10*time.Minute, 23*time.Hour+50*time.Minute))
+	assert.Equal(t, -20*time.Minute, clockDiff(23*time.Hour+50*time.Minute, 10*time.Minute))
+	assert.Equal(t, 2*time.Hour, clockDiff(11*time.Hour, 9*time.Hour))
+}
+
+func TestMedianClock(t *testing.T) {
+	assert.Equal(t, 9*time.Hour, medianClock([]time.Duration{8 * time.Hour, 9 * time.Hour, 12 * time.Hour}))
+	assert.Equal(t, 23*time.Hour+55*time.Minute, medianClock([]time.Duration{
+		23*time.Hour + 50*time.Minute, 10 * time.Minute, 23*time.Hour + 55*time.Minute,
+	}))
+}
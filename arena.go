@@ -0,0 +1,165 @@
+package trn
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// maxPooledBoundaries limits the size of the buffers kept in the pool, so
+// a single huge merge doesn't pin its memory forever.
+const maxPooledBoundaries = 1 << 16
+
+var arenaPool = sync.Pool{New: func() interface{} { return &Arena{} }}
+
+// Arena keeps the internal buffers of batch operations to reuse them across
+// calls, reducing allocations for services, which merge lots of range sets.
+// Arena is not safe for concurrent use, acquire one per goroutine.
+type Arena struct {
+	bounds boundaries
+}
+
+// AcquireArena returns the arena from the package pool, it should be
+// returned back with Release once it is no longer needed.
+func AcquireArena() *Arena { return arenaPool.Get().(*Arena) }
+
+// Release returns the arena to the package pool, the arena must not be used
+// after that.
+func (a *Arena) Release() {
+	if cap(a.bounds) > maxPooledBoundaries {
+		a.bounds = nil
+	}
+	a.bounds = a.bounds[:0]
+	arenaPool.Put(a)
+}
+
+// MergeOverlappingRanges merges the overlapping ranges the same way as the
+// package-level MergeOverlappingRanges does, but appends the result to dst,
+// so the caller may reuse its buffer as well.
+func (a *Arena) MergeOverlappingRanges(dst, ranges []Range, opts ...MergeOption) []Range {
+	o := newMergeOptions(opts)
+	n := len(dst)
+	dst = a.merge(dst, ranges, o.epsilon)
+	return append(dst[:n], dropShorter(dst[n:], o.minDuration)...)
+}
+
+// merge appends the merged ranges to dst, treating the gaps not longer than
+// eps as overlaps.
+func (a *Arena) merge(dst, ranges []Range, eps time.Duration) []Range {
+	a.bounds = a.bounds[:0]
+	for _, rng := range ranges {
+		a.bounds = append(a.bounds,
+			boundary{tm: rng.st, typ: boundaryStart},
+			boundary{tm: rng.End(), typ: boundaryEnd},
+		)
+	}
+	return a.sweep(dst, eps)
+}
+
+// sweep appends the ranges merged from the boundaries collected in the
+// arena to dst.
+func (a *Arena) sweep(dst []Range, eps time.Duration) []Range {
+	// sorting boundaries by time
+	sort.Sort(&a.bounds)
+	return a.sweepSorted(dst, eps)
+}
+
+// sortChunk is the number of boundaries sorted or merged between the
+// checks of the context.
+const sortChunk = 1 << 16
+
+// sortContext sorts the boundaries collected in the arena by time, like
+// sweep does, but in chunks, which are merged afterwards, checking the
+// context in between, so the huge inputs don't block the cancellation.
+func (a *Arena) sortContext(ctx context.Context) error {
+	b := a.bounds
+	for i := 0; i < len(b); i += sortChunk {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		chunk := b[i:boundedIdx(i+sortChunk, len(b))]
+		sort.Sort(&chunk)
+	}
+
+	if len(b) <= sortChunk {
+		return nil
+	}
+
+	buf := make(boundaries, len(b))
+	for width := sortChunk; width < len(b); width *= 2 {
+		for lo := 0; lo < len(b); lo += 2 * width {
+			mid, hi := boundedIdx(lo+width, len(b)), boundedIdx(lo+2*width, len(b))
+			for i, j, k := lo, mid, lo; k < hi; k++ {
+				if k%sortChunk == 0 {
+					if err := ctx.Err(); err != nil {
+						return err
+					}
+				}
+				if j == hi || i < mid && !b[j].tm.Before(b[i].tm) {
+					buf[k], i = b[i], i+1
+				} else {
+					buf[k], j = b[j], j+1
+				}
+			}
+		}
+		b, buf = buf, b
+	}
+	a.bounds = b
+	return nil
+}
+
+// boundedIdx returns i, but not more than n.
+func boundedIdx(i, n int) int {
+	if i > n {
+		return n
+	}
+	return i
+}
+
+// sweepSorted appends the ranges merged from the sorted boundaries
+// collected in the arena to dst.
+func (a *Arena) sweepSorted(dst []Range, eps time.Duration) []Range {
+	bounds := a.bounds
+	if len(bounds) == 0 {
+		return dst
+	}
+
+	// add first boundary
+	var rangeStartTm time.Time
+	unfinishedBoundariesCnt := 0
+
+	// skip last boundary to allow looking ahead
+	for i := 0; i < len(bounds)-1; i++ {
+		boundary := bounds[i]
+
+		if boundary.typ == boundaryStart {
+			if unfinishedBoundariesCnt == 0 {
+				rangeStartTm = boundary.tm
+			}
+			unfinishedBoundariesCnt++
+			continue
+		}
+
+		nextBoundary := bounds[i+1]
+		// if current and previous boundaries are equal - ignore them
+		if !nextBoundary.tm.After(boundary.tm.Add(eps)) && nextBoundary.typ == boundaryStart {
+			i++
+			continue
+		}
+
+		unfinishedBoundariesCnt--
+		// if this is an ending boundary and there is where the merged range ends...
+		if unfinishedBoundariesCnt == 0 {
+			dst = append(dst, Range{st: rangeStartTm, dur: boundary.tm.Sub(rangeStartTm)})
+		}
+	}
+
+	// process the last boundary, it must be the end boundary anyway
+	unfinishedBoundariesCnt--
+	if unfinishedBoundariesCnt == 0 {
+		dst = append(dst, Range{st: rangeStartTm, dur: bounds[len(bounds)-1].tm.Sub(rangeStartTm)})
+	}
+
+	return dst
+}
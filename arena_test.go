@@ -0,0 +1,70 @@
+package trn
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArena_MergeOverlappingRanges(t *testing.T) {
+	rngs := []Range{
+		MustRange(Between(tm(15, 0), tm(18, 0))),
+		MustRange(Between(tm(9, 0), tm(12, 0))),
+		MustRange(Between(tm(11, 0), tm(13, 0))),
+		MustRange(Between(tm(13, 0), tm(14, 0))),
+	}
+
+	a := AcquireArena()
+	defer a.Release()
+
+	dst := make([]Range, 0, 4)
+	dst = a.MergeOverlappingRanges(dst, rngs)
+	assert.Equal(t, MergeOverlappingRanges(rngs), dst)
+	assert.Equal(t, []Range{
+		MustRange(Between(tm(9, 0), tm(14, 0))),
+		MustRange(Between(tm(15, 0), tm(18, 0))),
+	}, dst)
+
+	assert.Empty(t, a.MergeOverlappingRanges(dst[:0], nil))
+
+	allocs := testing.AllocsPerRun(100, func() { dst = a.MergeOverlappingRanges(dst[:0], rngs) })
+	assert.Zero(t, allocs)
+}
+
+func TestArena_Release(t *testing.T) {
+	a := AcquireArena()
+	a.bounds = make(boundaries, maxPooledBoundaries+1)
+	a.Release()
+	assert.Nil(t, a.bounds)
+
+	a = AcquireArena()
+	a.bounds = make(boundaries, 10)
+	a.Release()
+	assert.Len(t, a.bounds, 0)
+	assert.Equal(t, 10, cap(a.bounds))
+}
+
+func BenchmarkMergeOverlappingRanges(b *testing.B) {
+	rngs := make([]Range, 1000)
+	for i := range rngs {
+		rngs[i] = New(tm(0, 0).Add(time.Duration(i*7919%1000)*time.Minute), 90*time.Second)
+	}
+
+	b.Run("package", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			MergeOverlappingRanges(rngs)
+		}
+	})
+
+	b.Run("arena", func(b *testing.B) {
+		b.ReportAllocs()
+		a := AcquireArena()
+		defer a.Release()
+		var dst []Range
+		for i := 0; i < b.N; i++ {
+			dst = a.MergeOverlappingRanges(dst[:0], rngs)
+		}
+	})
+}
@@ -0,0 +1,95 @@
+// Package bench provides the benchmark scenarios of trn operations on
+// large synthetic data sets, so the performance regressions are measurable
+// and users could compare their hardware before sizing services:
+//
+//	go test -bench . github.com/cappuccinotm/trn/bench
+package bench
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cappuccinotm/trn"
+	"github.com/cappuccinotm/trn/gen"
+)
+
+// Scenario is the benchmark scenario.
+type Scenario struct {
+	Name string
+	Run  func(b *testing.B)
+}
+
+// start is the start of the benchmark data sets.
+var start = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Scenarios returns the benchmark scenarios, the data sets are generated
+// once the scenario is run, the generation is not measured.
+func Scenarios() []Scenario {
+	return []Scenario{
+		{Name: "merge 1e6 ranges", Run: mergeRanges},
+		{Name: "flip dense set", Run: flipDense},
+		{Name: "stratify a year", Run: stratifyYear},
+		{Name: "stratify free set", Run: stratifyFreeSet},
+		{Name: "insert into merged set", Run: insertMerged},
+	}
+}
+
+func mergeRanges(b *testing.B) {
+	rngs := gen.Ranges(1, 1_000_000, trn.New(start, 365*24*time.Hour), 2*time.Hour)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		trn.MergeOverlappingRanges(rngs)
+	}
+}
+
+func flipDense(b *testing.B) {
+	period := trn.New(start, 30*24*time.Hour)
+	rngs := gen.Ranges(2, 100_000, period, 10*time.Second)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		period.Flip(rngs)
+	}
+}
+
+func stratifyYear(b *testing.B) {
+	year := trn.New(start, 365*24*time.Hour)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := year.Stratify(30*time.Minute, 15*time.Minute); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func stratifyFreeSet(b *testing.B) {
+	res := gen.Workload{Seed: 3, Period: trn.New(start, 365*24*time.Hour)}.Generate()
+	free := res[0].Free.Ranges()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := trn.StratifySet(free, 30*time.Minute, 15*time.Minute); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func insertMerged(b *testing.B) {
+	period := trn.New(start, 365*24*time.Hour)
+	merged := trn.MergeOverlappingRanges(gen.Ranges(4, 100_000, period, time.Hour))
+	inserts := gen.Ranges(5, 1024, period, time.Hour)
+	buf := make([]trn.Range, 0, len(merged)+1)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf = append(buf[:0], merged...)
+		trn.InsertMerged(buf, inserts[i%len(inserts)])
+	}
+}
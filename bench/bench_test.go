@@ -0,0 +1,23 @@
+package bench
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func BenchmarkScenarios(b *testing.B) {
+	for _, s := range Scenarios() {
+		b.Run(s.Name, s.Run)
+	}
+}
+
+func TestScenarios(t *testing.T) {
+	names := map[string]bool{}
+	for _, s := range Scenarios() {
+		assert.NotEmpty(t, s.Name)
+		assert.NotNil(t, s.Run)
+		assert.False(t, names[s.Name], "duplicate scenario %q", s.Name)
+		names[s.Name] = true
+	}
+}
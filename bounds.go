@@ -0,0 +1,73 @@
+package trn
+
+import (
+	"math"
+	"time"
+)
+
+// MaxTime and MinTime are the latest and the earliest instants, which are
+// safe to use as sentinels, e.g. for open-ended ranges. Note that a Range
+// can't last longer than about 292 years, see OpenEnded.
+var (
+	MaxTime = time.Unix(math.MaxInt64-62135596800, 999999999).UTC()
+	MinTime = time.Unix(math.MinInt64, 0).UTC()
+)
+
+// AddSaturating returns t+d clamped to [MinTime, MaxTime], while time.Add
+// might wrap around on overflow.
+func AddSaturating(t time.Time, d time.Duration) time.Time {
+	switch {
+	case d == math.MinInt64:
+		// -d overflows, make it in two steps
+		return AddSaturating(AddSaturating(t, d+1), -1)
+	case d > 0 && MaxTime.Sub(t) < d:
+		return MaxTime
+	case d < 0 && t.Sub(MinTime) < -d:
+		return MinTime
+	}
+	return t.Add(d)
+}
+
+// OpenEnded returns the longest range since the start, which could be
+// represented, i.e. the one ending at MaxTime or lasting the maximal
+// time.Duration, whichever is earlier.
+func OpenEnded(start time.Time) Range {
+	return Range{st: start, dur: maxDurationSince(start, math.MaxInt64)}
+}
+
+// ShiftSaturating returns the date range moved by d, like Shift does, but
+// the start is clamped to [MinTime, MaxTime] and the duration is shortened
+// if the end would go after MaxTime.
+func (r Range) ShiftSaturating(d time.Duration) Range {
+	st := AddSaturating(r.st, d)
+	return Range{st: st, dur: maxDurationSince(st, r.dur)}
+}
+
+// ExtendSaturating returns the date range with the end moved by d, which
+// might be negative. The duration is clamped to zero, to the maximal
+// time.Duration and to the end at MaxTime.
+func (r Range) ExtendSaturating(d time.Duration) Range {
+	dur := r.dur + d
+	switch {
+	case d > 0 && dur < r.dur:
+		dur = math.MaxInt64
+	case dur < 0:
+		dur = 0
+	}
+	return Range{st: r.st, dur: maxDurationSince(r.st, dur)}
+}
+
+// overflows returns true if the duration between start and end exceeds
+// the maximal time.Duration, so time.Sub saturates.
+func overflows(start, end time.Time) bool {
+	dur := end.Sub(start)
+	return dur == math.MaxInt64 && !start.Add(dur).Equal(end)
+}
+
+// maxDurationSince returns dur, shortened if the end would go after MaxTime.
+func maxDurationSince(st time.Time, dur time.Duration) time.Duration {
+	if left := MaxTime.Sub(st); left < dur {
+		return left
+	}
+	return dur
+}
@@ -0,0 +1,72 @@
+package trn
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddSaturating(t *testing.T) {
+	assert.Equal(t, tm(13, 0), AddSaturating(tm(12, 0), time.Hour))
+	assert.Equal(t, tm(11, 0), AddSaturating(tm(12, 0), -time.Hour))
+
+	assert.Equal(t, MaxTime, AddSaturating(MaxTime.Add(-time.Hour), 2*time.Hour))
+	assert.Equal(t, MaxTime, AddSaturating(MaxTime, math.MaxInt64))
+	assert.Equal(t, MinTime, AddSaturating(MinTime.Add(time.Hour), -2*time.Hour))
+	assert.Equal(t, MinTime, AddSaturating(MinTime, math.MinInt64))
+	assert.Equal(t, MaxTime.Add(-time.Hour), AddSaturating(MaxTime, -time.Hour))
+
+	assert.True(t, MinTime.Before(time.Time{}))
+	assert.True(t, MaxTime.After(time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestOpenEnded(t *testing.T) {
+	r := OpenEnded(tm(12, 0))
+	assert.Equal(t, time.Duration(math.MaxInt64), r.Duration())
+	assert.True(t, r.End().After(tm(12, 0)))
+
+	r = OpenEnded(MaxTime.Add(-time.Hour))
+	assert.Equal(t, time.Hour, r.Duration())
+	assert.Equal(t, MaxTime, r.End())
+}
+
+func TestRange_ShiftSaturating(t *testing.T) {
+	r := New(tm(12, 0), time.Hour)
+	assert.Equal(t, r.Shift(time.Hour), r.ShiftSaturating(time.Hour))
+
+	r = New(MaxTime.Add(-3*time.Hour), time.Hour)
+	assert.Equal(t, New(MaxTime.Add(-2*time.Hour), time.Hour), r.ShiftSaturating(time.Hour))
+	assert.Equal(t, New(MaxTime.Add(-30*time.Minute), 30*time.Minute), r.ShiftSaturating(150*time.Minute))
+	assert.Equal(t, New(MaxTime, 0), r.ShiftSaturating(math.MaxInt64))
+
+	r = New(MinTime.Add(time.Hour), time.Hour)
+	assert.Equal(t, New(MinTime, time.Hour), r.ShiftSaturating(-2*time.Hour))
+}
+
+func TestRange_ExtendSaturating(t *testing.T) {
+	r := New(tm(12, 0), time.Hour)
+	assert.Equal(t, New(tm(12, 0), 2*time.Hour), r.ExtendSaturating(time.Hour))
+	assert.Equal(t, New(tm(12, 0), 30*time.Minute), r.ExtendSaturating(-30*time.Minute))
+	assert.Equal(t, New(tm(12, 0), 0), r.ExtendSaturating(-2*time.Hour))
+	assert.Equal(t, New(tm(12, 0), 0), r.ExtendSaturating(math.MinInt64))
+	assert.Equal(t, OpenEnded(tm(12, 0)), r.ExtendSaturating(math.MaxInt64))
+
+	r = New(MaxTime.Add(-2*time.Hour), time.Hour)
+	assert.Equal(t, MaxTime, r.ExtendSaturating(2*time.Hour).End())
+}
+
+func TestBetween_Overflow(t *testing.T) {
+	_, err := Between(MinTime, MaxTime)
+	assert.ErrorIs(t, err, ErrDurationOverflow)
+
+	_, err = Between(tm(12, 0), MaxTime)
+	assert.ErrorIs(t, err, ErrDurationOverflow)
+
+	r, err := Between(MaxTime.Add(-time.Hour), MaxTime)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Hour, r.Duration())
+
+	assert.Equal(t, OpenEnded(tm(12, 0)), NewBetween(tm(12, 0), MaxTime))
+}
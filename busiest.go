@@ -0,0 +1,135 @@
+package trn
+
+import (
+	"sort"
+	"time"
+)
+
+// BusiestWindows returns up to k non-overlapping windows of the given
+// duration with the highest load, where the load of a window is the total
+// time of the ranges within it, i.e. the overlapping ranges count several
+// times. Windows without load are never returned.
+// The resulting windows are sorted by load, from the busiest one, ties are
+// resolved in favor of the earlier window.
+func BusiestWindows(ranges []Range, window time.Duration, k int) []Range {
+	if window <= 0 || k <= 0 {
+		return nil
+	}
+
+	hull, ok := hullOf(ranges)
+	if !ok {
+		return nil
+	}
+
+	ld := newCoverageIntegral(coverage(hull, ranges))
+
+	// the load of the sliding window changes its slope only when the
+	// window's start or end crosses the boundary of a coverage segment,
+	// so the maximums are reached at such positions
+	type candidate struct {
+		rng  Range
+		load float64
+	}
+	var candidates []candidate
+	seen := map[int64]bool{}
+	for _, b := range ld.boundaries() {
+		for _, st := range []time.Time{b, b.Add(-window)} {
+			if seen[st.UnixNano()] {
+				continue
+			}
+			seen[st.UnixNano()] = true
+
+			rng := Range{st: st, dur: window}
+			if l := ld.within(rng); l > 0 {
+				candidates = append(candidates, candidate{rng: rng, load: l})
+			}
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].load != candidates[j].load {
+			return candidates[i].load > candidates[j].load
+		}
+		return candidates[i].rng.st.Before(candidates[j].rng.st)
+	})
+
+	var res []Range
+	for _, c := range candidates {
+		if len(res) == k {
+			break
+		}
+		if !overlapsAny(c.rng, res) {
+			res = append(res, c.rng)
+		}
+	}
+
+	return res
+}
+
+// coverageIntegral is the prefix integral of the coverage, in nanoseconds.
+type coverageIntegral struct {
+	segs []coverageSegment
+	cum  []float64 // integral of the coverage before the i-th segment
+}
+
+func newCoverageIntegral(segs []coverageSegment) coverageIntegral {
+	cum := make([]float64, len(segs)+1)
+	for i, seg := range segs {
+		cum[i+1] = cum[i] + float64(seg.rng.dur)*float64(seg.cnt)
+	}
+	return coverageIntegral{segs: segs, cum: cum}
+}
+
+func (l coverageIntegral) boundaries() []time.Time {
+	res := make([]time.Time, 0, len(l.segs)+1)
+	for _, seg := range l.segs {
+		res = append(res, seg.rng.st)
+	}
+	return append(res, l.segs[len(l.segs)-1].rng.End())
+}
+
+// upTo returns the integral of the coverage up to the given time.
+func (l coverageIntegral) upTo(t time.Time) float64 {
+	idx := sort.Search(len(l.segs), func(i int) bool { return l.segs[i].rng.End().After(t) })
+	if idx == len(l.segs) {
+		return l.cum[idx]
+	}
+
+	seg := l.segs[idx]
+	if t.Before(seg.rng.st) {
+		return l.cum[idx]
+	}
+	return l.cum[idx] + float64(t.Sub(seg.rng.st))*float64(seg.cnt)
+}
+
+// within returns the integral of the coverage within the range.
+func (l coverageIntegral) within(r Range) float64 { return l.upTo(r.End()) - l.upTo(r.st) }
+
+// hullOf returns the smallest range, which contains all non-empty ranges.
+func hullOf(ranges []Range) (Range, bool) {
+	var st, end time.Time
+	found := false
+	for _, rng := range ranges {
+		if rng.dur <= 0 {
+			continue
+		}
+		if !found || rng.st.Before(st) {
+			st = rng.st
+		}
+		if !found || rng.End().After(end) {
+			end = rng.End()
+		}
+		found = true
+	}
+	return Range{st: st, dur: end.Sub(st)}, found
+}
+
+// overlapsAny returns true if r overlaps any of the ranges.
+func overlapsAny(r Range, ranges []Range) bool {
+	for _, other := range ranges {
+		if r.Overlaps(other) {
+			return true
+		}
+	}
+	return false
+}
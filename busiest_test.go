@@ -0,0 +1,51 @@
+package trn
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBusiestWindows(t *testing.T) {
+	ranges := []Range{
+		MustRange(Between(tm(9, 0), tm(10, 0))),
+		MustRange(Between(tm(9, 30), tm(10, 30))),
+		MustRange(Between(tm(9, 45), tm(10, 15))),
+		MustRange(Between(tm(14, 0), tm(14, 40))),
+		MustRange(Between(tm(16, 0), tm(16, 20))),
+	}
+
+	tests := []struct {
+		name   string
+		window time.Duration
+		k      int
+		want   []Range
+	}{
+		{
+			name: "top three hours", window: time.Hour, k: 3,
+			want: []Range{
+				MustRange(Between(tm(9, 15), tm(10, 15))),
+				MustRange(Between(tm(13, 40), tm(14, 40))),
+				MustRange(Between(tm(15, 20), tm(16, 20))),
+			},
+		},
+		{
+			name: "more than available", window: 4 * time.Hour, k: 5,
+			want: []Range{
+				MustRange(Between(tm(6, 30), tm(10, 30))), // the earliest one with the same load
+				MustRange(Between(tm(12, 20), tm(16, 20))),
+			},
+		},
+		{name: "zero window", window: 0, k: 3},
+		{name: "zero k", window: time.Hour, k: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BusiestWindows(ranges, tt.window, tt.k)
+			assert.Equal(t, formattedRanges(tt.want, "15:04"), formattedRanges(got, "15:04"))
+		})
+	}
+
+	assert.Nil(t, BusiestWindows(nil, time.Hour, 1))
+}
@@ -0,0 +1,110 @@
+package trn
+
+import "time"
+
+// Result holds the ranges produced by a chain of operations along with the
+// first error occurred, so the operations could be composed without
+// checking an error after each step, e.g.:
+//
+//	rngs, err := trn.Chain(trn.Between(start, end)).Truncate(bounds).Split(d, i).Ranges()
+//
+// Once an operation fails, the rest of the chain is skipped. Each operation
+// applies to every range of the result. Result is immutable, each operation
+// returns a new one.
+type Result struct {
+	rngs []Range
+	err  error
+}
+
+// Chain starts the chain of operations from the result of a function, that
+// returns a single range, e.g. Between.
+func Chain(r Range, err error) Result {
+	if err != nil {
+		return Result{err: err}
+	}
+	return Result{rngs: []Range{r}}
+}
+
+// ChainRanges starts the chain of operations from the result of a function,
+// that returns ranges, e.g. Range.Split. The ranges are copied.
+func ChainRanges(rngs []Range, err error) Result {
+	if err != nil {
+		return Result{err: err}
+	}
+	return Result{rngs: append([]Range(nil), rngs...)}
+}
+
+// Truncate truncates each range to the bounds, empty ranges are dropped.
+func (res Result) Truncate(bounds Range) Result {
+	return res.each(func(r Range) ([]Range, error) {
+		if r = r.Truncate(bounds); r.dur <= 0 {
+			return nil, nil
+		}
+		return []Range{r}, nil
+	})
+}
+
+// Split splits each range, see Range.Split for details.
+func (res Result) Split(duration, interval time.Duration, opts ...SplitOption) Result {
+	return res.each(func(r Range) ([]Range, error) { return r.Split(duration, interval, opts...) })
+}
+
+// Stratify stratifies each range, see Range.Stratify for details.
+func (res Result) Stratify(duration, interval time.Duration, opts ...SplitOption) Result {
+	return res.each(func(r Range) ([]Range, error) { return r.Stratify(duration, interval, opts...) })
+}
+
+// Flip flips the given ranges within each range, see Range.Flip for details.
+func (res Result) Flip(ranges []Range) Result {
+	return res.each(func(r Range) ([]Range, error) { return r.Flip(ranges), nil })
+}
+
+// In sets the location of each range.
+func (res Result) In(loc *time.Location) Result {
+	return res.each(func(r Range) ([]Range, error) { return []Range{r.In(loc)}, nil })
+}
+
+// Err returns the first error occurred in the chain.
+func (res Result) Err() error { return res.err }
+
+// Ranges returns the copy of the resulting ranges or the first error
+// occurred in the chain.
+func (res Result) Ranges() ([]Range, error) {
+	if res.err != nil {
+		return nil, res.err
+	}
+	return append([]Range(nil), res.rngs...), nil
+}
+
+// Range returns the single resulting range or the first error occurred in
+// the chain. Returns empty range if the chain produced nothing and
+// ErrMultipleRanges if it produced more than one range.
+func (res Result) Range() (Range, error) {
+	switch {
+	case res.err != nil:
+		return Range{}, res.err
+	case len(res.rngs) == 0:
+		return Range{}, nil
+	case len(res.rngs) > 1:
+		return Range{}, ErrMultipleRanges
+	default:
+		return res.rngs[0], nil
+	}
+}
+
+func (res Result) each(fn func(r Range) ([]Range, error)) Result {
+	if res.err != nil {
+		return res
+	}
+
+	var rngs []Range
+	for _, r := range res.rngs {
+		out, err := fn(r)
+		if err != nil {
+			return Result{err: err}
+		}
+		rngs = append(rngs, out...)
+	}
+
+	return Result{rngs: rngs}
+}
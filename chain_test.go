@@ -0,0 +1,76 @@
+package trn
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChain(t *testing.T) {
+	t.Run("successful chain", func(t *testing.T) {
+		rngs, err := Chain(Between(tm(9, 0), tm(18, 0))).
+			Truncate(MustRange(Between(tm(12, 0), tm(20, 0)))).
+			Split(time.Hour, 30*time.Minute).
+			Ranges()
+		assert.NoError(t, err)
+		assert.Equal(t, formattedRanges([]Range{
+			MustRange(Between(tm(12, 0), tm(13, 0))),
+			MustRange(Between(tm(13, 30), tm(14, 30))),
+			MustRange(Between(tm(15, 0), tm(16, 0))),
+			MustRange(Between(tm(16, 30), tm(17, 30))),
+		}, "15:04"), formattedRanges(rngs, "15:04"))
+	})
+
+	t.Run("error at the start", func(t *testing.T) {
+		res := Chain(Between(tm(18, 0), tm(9, 0))).Split(time.Hour, 0)
+		assert.ErrorIs(t, res.Err(), ErrStartAfterEnd)
+		rngs, err := res.Ranges()
+		assert.ErrorIs(t, err, ErrStartAfterEnd)
+		assert.Nil(t, rngs)
+	})
+
+	t.Run("error in the middle", func(t *testing.T) {
+		_, err := Chain(Between(tm(9, 0), tm(18, 0))).
+			Stratify(0, time.Hour).
+			Truncate(MustRange(Between(tm(12, 0), tm(20, 0)))).
+			Ranges()
+		assert.ErrorIs(t, err, ErrZeroDurationInterval)
+	})
+
+	t.Run("flip and single range", func(t *testing.T) {
+		rng, err := ChainRanges([]Range{MustRange(Between(tm(9, 0), tm(18, 0)))}, nil).
+			Flip([]Range{MustRange(Between(tm(9, 0), tm(12, 0)))}).
+			In(time.UTC).
+			Range()
+		assert.NoError(t, err)
+		assert.Equal(t, "[12:00, 18:00]", rng.Format("15:04"))
+	})
+
+	t.Run("multiple ranges", func(t *testing.T) {
+		_, err := Chain(Between(tm(9, 0), tm(18, 0))).Split(time.Hour, 0).Range()
+		assert.ErrorIs(t, err, ErrMultipleRanges)
+	})
+
+	t.Run("no ranges", func(t *testing.T) {
+		rng, err := Chain(Between(tm(9, 0), tm(10, 0))).
+			Truncate(MustRange(Between(tm(12, 0), tm(20, 0)))).
+			Range()
+		assert.NoError(t, err)
+		assert.True(t, rng.Empty())
+	})
+
+	t.Run("immutable", func(t *testing.T) {
+		src := []Range{MustRange(Between(tm(9, 0), tm(10, 0)))}
+		res := ChainRanges(src, nil)
+		src[0] = Range{}
+
+		rngs, err := res.Ranges()
+		assert.NoError(t, err)
+		rngs[0] = Range{}
+
+		rng, err := res.Range()
+		assert.NoError(t, err)
+		assert.Equal(t, "[09:00, 10:00]", rng.Format("15:04"))
+	})
+}
@@ -0,0 +1,134 @@
+package trn
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Change describes the change of the set of free ranges, which could be
+// streamed between services, e.g. via Kafka.
+type Change interface {
+	// Kind returns the stable name of the change used in its JSON encoding.
+	Kind() string
+	// Apply applies the change to the set.
+	Apply(s *Set)
+}
+
+// Kinds of changes.
+const (
+	KindRangeBooked          = "range_booked"
+	KindRangeReleased        = "range_released"
+	KindAvailabilityReplaced = "availability_replaced"
+)
+
+// RangeBooked reports that the range is no longer free.
+type RangeBooked struct {
+	Range Range `json:"range"`
+}
+
+// Kind returns KindRangeBooked.
+func (RangeBooked) Kind() string { return KindRangeBooked }
+
+// Apply removes the booked range from the set.
+func (c RangeBooked) Apply(s *Set) { s.Remove(c.Range) }
+
+// RangeReleased reports that the range is free again.
+type RangeReleased struct {
+	Range Range `json:"range"`
+}
+
+// Kind returns KindRangeReleased.
+func (RangeReleased) Kind() string { return KindRangeReleased }
+
+// Apply adds the released range to the set.
+func (c RangeReleased) Apply(s *Set) { s.Add(c.Range) }
+
+// AvailabilityReplaced reports that the whole set of free ranges is
+// replaced, e.g. after the resource's schedule is changed.
+type AvailabilityReplaced struct {
+	Ranges []Range `json:"ranges"`
+}
+
+// Kind returns KindAvailabilityReplaced.
+func (AvailabilityReplaced) Kind() string { return KindAvailabilityReplaced }
+
+// Apply replaces the contents of the set.
+func (c AvailabilityReplaced) Apply(s *Set) { s.Replace(c.Ranges...) }
+
+// Apply applies the changes to the set in order.
+func (s *Set) Apply(changes ...Change) {
+	for _, c := range changes {
+		c.Apply(s)
+	}
+}
+
+// MarshalChange encodes the change in JSON as an object with the change's
+// fields, its kind in the "kind" field and the CodecVersion in the "v"
+// field, e.g.
+// {"v":1,"kind":"range_booked","range":{"start":"...","end":"..."}}.
+func MarshalChange(c Change) ([]byte, error) {
+	switch c := c.(type) {
+	case RangeBooked:
+		return json.Marshal(struct {
+			V    int    `json:"v"`
+			Kind string `json:"kind"`
+			RangeBooked
+		}{V: CodecVersion, Kind: c.Kind(), RangeBooked: c})
+	case RangeReleased:
+		return json.Marshal(struct {
+			V    int    `json:"v"`
+			Kind string `json:"kind"`
+			RangeReleased
+		}{V: CodecVersion, Kind: c.Kind(), RangeReleased: c})
+	case AvailabilityReplaced:
+		return json.Marshal(struct {
+			V    int    `json:"v"`
+			Kind string `json:"kind"`
+			AvailabilityReplaced
+		}{V: CodecVersion, Kind: c.Kind(), AvailabilityReplaced: c})
+	default:
+		return nil, ErrUnknownChange
+	}
+}
+
+// UnmarshalChange decodes the change encoded by MarshalChange, unknown
+// fields are ignored, the change without version is treated as the first
+// version. Returns ErrUnknownChange if the kind of the change is not known
+// and ErrUnsupportedVersion if the version is newer than CodecVersion.
+func UnmarshalChange(b []byte) (Change, error) {
+	var head struct {
+		V    int    `json:"v"`
+		Kind string `json:"kind"`
+	}
+	if err := json.Unmarshal(b, &head); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedRange, err)
+	}
+	if err := checkVersion(head.V); err != nil {
+		return nil, err
+	}
+
+	switch head.Kind {
+	case KindRangeBooked:
+		return decodeChange[RangeBooked](b, "range")
+	case KindRangeReleased:
+		return decodeChange[RangeReleased](b, "range")
+	case KindAvailabilityReplaced:
+		return decodeChange[AvailabilityReplaced](b, "ranges")
+	default:
+		return nil, ErrUnknownChange
+	}
+}
+
+// decodeChange decodes the change with the given fields besides the
+// version and the kind.
+func decodeChange[T Change](b []byte, fields ...string) (Change, error) {
+	if err := checkFields(b, append(fields, "v", "kind")...); err != nil {
+		return nil, err
+	}
+
+	var c T
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
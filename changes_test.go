@@ -0,0 +1,84 @@
+package trn
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSet_Apply(t *testing.T) {
+	var s Set
+	s.Apply(
+		AvailabilityReplaced{Ranges: []Range{MustRange(Between(tm(9, 0), tm(18, 0)))}},
+		RangeBooked{Range: MustRange(Between(tm(12, 0), tm(13, 0)))},
+		RangeBooked{Range: MustRange(Between(tm(15, 0), tm(16, 0)))},
+		RangeReleased{Range: MustRange(Between(tm(15, 0), tm(15, 30)))},
+	)
+	assert.Equal(t, []string{"[09:00, 12:00]", "[13:00, 15:30]", "[16:00, 18:00]"}, formatSet(s))
+}
+
+func TestMarshalChange(t *testing.T) {
+	tests := []struct {
+		name   string
+		change Change
+		json   string
+	}{
+		{
+			name:   "booked",
+			change: RangeBooked{Range: MustRange(Between(tm(12, 0), tm(13, 0)))},
+			json:   `{"v":1,"kind":"range_booked","range":{"start":"2021-06-12T12:00:00Z","end":"2021-06-12T13:00:00Z"}}`,
+		},
+		{
+			name:   "released",
+			change: RangeReleased{Range: MustRange(Between(tm(12, 0), tm(13, 0)))},
+			json:   `{"v":1,"kind":"range_released","range":{"start":"2021-06-12T12:00:00Z","end":"2021-06-12T13:00:00Z"}}`,
+		},
+		{
+			name:   "replaced",
+			change: AvailabilityReplaced{Ranges: []Range{MustRange(Between(tm(9, 0), tm(10, 0)))}},
+			json:   `{"v":1,"kind":"availability_replaced","ranges":[{"start":"2021-06-12T09:00:00Z","end":"2021-06-12T10:00:00Z"}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := MarshalChange(tt.change)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.json, string(b))
+
+			got, err := UnmarshalChange(b)
+			require.NoError(t, err)
+			assert.Equal(t, tt.change, got)
+		})
+	}
+
+	_, err := UnmarshalChange([]byte(`{"kind":"range_moved"}`))
+	assert.ErrorIs(t, err, ErrUnknownChange)
+
+	_, err = UnmarshalChange([]byte(`{"kind":"range_booked","range":{"start":"2021"}}`))
+	assert.Error(t, err)
+
+	_, err = UnmarshalChange([]byte(`{"kind":`))
+	assert.ErrorIs(t, err, ErrMalformedRange)
+
+	_, err = MarshalChange(nil)
+	assert.ErrorIs(t, err, ErrUnknownChange)
+
+	t.Run("versions", func(t *testing.T) {
+		want := RangeBooked{Range: New(dhm(12, 12, 0), time.Hour)}
+
+		got, err := UnmarshalChange([]byte(`{"kind":"range_booked",` +
+			`"range":{"start":"2021-06-12T12:00:00Z","end":"2021-06-12T13:00:00Z"}}`))
+		require.NoError(t, err, "no version")
+		assert.Equal(t, want, got)
+
+		got, err = UnmarshalChange([]byte(`{"v":1,"kind":"range_booked","source":"calendar",` +
+			`"range":{"start":"2021-06-12T12:00:00Z","end":"2021-06-12T13:00:00Z","inclusive":true}}`))
+		require.NoError(t, err, "unknown fields")
+		assert.Equal(t, want, got)
+
+		_, err = UnmarshalChange([]byte(`{"v":2,"kind":"range_booked"}`))
+		assert.ErrorIs(t, err, ErrUnsupportedVersion)
+	})
+}
@@ -0,0 +1,487 @@
+package trn
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// CodecVersion is the version of the encoding of sets and changes, written
+// in the "v" field of JSON envelopes and in the first byte of the binary
+// encoding. Decoders accept the versions up to the current one and ignore
+// the unknown fields, so the stored blobs survive future format changes.
+const CodecVersion = 1
+
+// DecodeMode defines how strictly the ranges, sets and changes are decoded.
+type DecodeMode int32
+
+// Decode modes.
+const (
+	// DecodeDefault rejects the ranges, which end before the start, and
+	// ignores the unknown fields.
+	DecodeDefault DecodeMode = iota
+	// DecodeStrict rejects the ranges, which end before the start or have
+	// zero boundaries, and the unknown fields, e.g. for services validating
+	// user payloads.
+	DecodeStrict
+	// DecodeLenient clamps the end of the range, which ends before the
+	// start, to the start and ignores the unknown fields, e.g. for batch
+	// importers.
+	DecodeLenient
+)
+
+// decodeMode is the package-wide DecodeMode, accessed atomically.
+var decodeMode int32
+
+// SetDecodeMode sets the package-wide mode of decoding, DecodeDefault by
+// default.
+func SetDecodeMode(m DecodeMode) { atomic.StoreInt32(&decodeMode, int32(m)) }
+
+// CurrentDecodeMode returns the package-wide mode of decoding.
+func CurrentDecodeMode() DecodeMode { return DecodeMode(atomic.LoadInt32(&decodeMode)) }
+
+// decodeJSON unmarshals the JSON into v, rejecting the unknown fields in
+// the strict mode.
+func decodeJSON(b []byte, v interface{}) error {
+	if CurrentDecodeMode() != DecodeStrict {
+		return json.Unmarshal(b, v)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+// checkFields returns ErrMalformedRange if the JSON object has fields
+// other than the given ones in the strict mode.
+func checkFields(b []byte, fields ...string) error {
+	if CurrentDecodeMode() != DecodeStrict {
+		return nil
+	}
+
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformedRange, err)
+	}
+
+next:
+	for k := range obj {
+		for _, f := range fields {
+			if k == f {
+				continue next
+			}
+		}
+		return fmt.Errorf("%w: unknown field %q", ErrMalformedRange, k)
+	}
+	return nil
+}
+
+// between makes the range of the decoded boundaries in the current mode.
+func between(st, end time.Time) (Range, error) {
+	switch CurrentDecodeMode() {
+	case DecodeStrict:
+		if st.IsZero() || end.IsZero() {
+			return Range{}, fmt.Errorf("%w: zero boundary", ErrMalformedRange)
+		}
+		if err := checkHorizon(end); err != nil {
+			return Range{}, err
+		}
+	case DecodeLenient:
+		res := NewBetween(st, end).applyPrecision()
+		if limit, ok := horizonLimit(); ok {
+			res, _ = res.clampTo(limit)
+		}
+		return res, nil
+	}
+	return Between(st, end)
+}
+
+// FormatRFC9557 formats the time in RFC 3339 format with the nanosecond
+// precision, extended with the bracketed IANA time zone name, as defined by
+// RFC 9557, e.g. "2021-06-12T13:00:00+02:00[Europe/Berlin]".
+// The zone suffix is omitted for UTC, Local and unnamed fixed zones.
+func FormatRFC9557(t time.Time) string {
+	return string(appendRFC9557(nil, t))
+}
+
+func appendRFC9557(b []byte, t time.Time) []byte {
+	b = t.AppendFormat(b, time.RFC3339Nano)
+	if name := t.Location().String(); isIANAZone(name) {
+		b = append(b, '[')
+		b = append(b, name...)
+		b = append(b, ']')
+	}
+	return b
+}
+
+// ParseRFC9557 parses the time in RFC 3339 format, optionally followed by
+// the bracketed IANA time zone name, as defined by RFC 9557.
+// If the zone is present, the resulting time is set to this location,
+// loaded with the package-wide loader, see SetLocationLoader.
+// Non-critical suffix tags (e.g. "[u-ca=iso8601]") are ignored.
+// Returns ErrInconsistentZone if the UTC offset doesn't match the zone.
+func ParseRFC9557(s string) (time.Time, error) {
+	var zone string
+
+	for strings.HasSuffix(s, "]") {
+		idx := strings.LastIndexByte(s, '[')
+		if idx < 0 {
+			return time.Time{}, fmt.Errorf("%w: %q", ErrMalformedTimestamp, s)
+		}
+
+		tag := s[idx+1 : len(s)-1]
+		s = s[:idx]
+
+		critical := strings.HasPrefix(tag, "!")
+		tag = strings.TrimPrefix(tag, "!")
+
+		switch {
+		case !strings.Contains(tag, "="):
+			zone = tag
+		case critical:
+			return time.Time{}, fmt.Errorf("%w: unsupported critical tag %q", ErrMalformedTimestamp, tag)
+		}
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %v", ErrMalformedTimestamp, err)
+	}
+
+	if zone == "" {
+		return t, nil
+	}
+
+	loc, err := LoadLocation(zone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %v", ErrMalformedTimestamp, err)
+	}
+
+	res := t.In(loc)
+	if _, off := res.Zone(); !strings.HasSuffix(s, "Z") {
+		if _, want := t.Zone(); off != want {
+			return time.Time{}, fmt.Errorf("%w: %q", ErrInconsistentZone, s)
+		}
+	}
+
+	return res, nil
+}
+
+// MarshalText implements encoding.TextMarshaler. The range is represented
+// as the ISO 8601 time interval of RFC 9557 timestamps, separated by slash,
+// e.g. "2021-06-12T13:00:00+02:00[Europe/Berlin]/2021-06-12T14:00:00+02:00[Europe/Berlin]".
+func (r Range) MarshalText() ([]byte, error) {
+	r = r.applyPrecision()
+	b := appendRFC9557(nil, r.st)
+	b = append(b, '/')
+	return appendRFC9557(b, r.End()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler and parses the range
+// formatted by MarshalText. The range uses the location of the start.
+func (r *Range) UnmarshalText(b []byte) error {
+	// zone names contain slashes too, so look for the one outside brackets
+	depth := 0
+	for i, c := range b {
+		switch c {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case '/':
+			if depth == 0 {
+				return r.parse(string(b[:i]), string(b[i+1:]))
+			}
+		}
+	}
+	return fmt.Errorf("%w: %q", ErrMalformedRange, string(b))
+}
+
+type jsonRange struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// MarshalJSON implements json.Marshaler. The range is represented as an
+// object with "start" and "end" RFC 9557 timestamps.
+func (r Range) MarshalJSON() ([]byte, error) {
+	r = r.applyPrecision()
+	return json.Marshal(jsonRange{Start: FormatRFC9557(r.st), End: FormatRFC9557(r.End())})
+}
+
+// UnmarshalJSON implements json.Unmarshaler and parses the range formatted
+// by MarshalJSON. The range uses the location of the start.
+func (r *Range) UnmarshalJSON(b []byte) error {
+	var jr jsonRange
+	if err := decodeJSON(b, &jr); err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformedRange, err)
+	}
+	return r.parse(jr.Start, jr.End)
+}
+
+// canonicalFmt is the layout of timestamps in canonical JSON, with the
+// fixed nanosecond precision.
+const canonicalFmt = "2006-01-02T15:04:05.000000000Z07:00"
+
+// MarshalCanonical encodes the range in the canonical JSON: the object
+// with sorted keys, without spaces, with the boundaries in UTC and with
+// the fixed nanosecond precision, e.g.
+// {"end":"2021-06-12T14:00:00.000000000Z","start":"2021-06-12T13:00:00.000000000Z"}.
+// The equal ranges are encoded into the same bytes regardless of their
+// locations, e.g. for golden files and content hashes. The result is
+// decoded by UnmarshalJSON.
+func (r Range) MarshalCanonical() ([]byte, error) { return r.appendCanonical(nil), nil }
+
+func (r Range) appendCanonical(b []byte) []byte {
+	r = r.applyPrecision()
+	b = append(b, `{"end":"`...)
+	b = r.End().UTC().AppendFormat(b, canonicalFmt)
+	b = append(b, `","start":"`...)
+	b = r.st.UTC().AppendFormat(b, canonicalFmt)
+	return append(b, `"}`...)
+}
+
+// EpochMillis is the Range, which is marshaled to JSON as an object with
+// "start_ms" and "end_ms" Unix timestamps in milliseconds, e.g. for
+// JavaScript frontends and analytical databases:
+//
+//	json.Marshal(trn.EpochMillis(rng))
+//
+// Sub-millisecond parts are truncated, the location is not preserved,
+// unmarshaled ranges are in UTC. The missing boundaries are rejected,
+// except in DecodeLenient mode, where they are treated as the Unix epoch.
+type EpochMillis Range
+
+type jsonEpochMillis struct {
+	StartMs *int64 `json:"start_ms"`
+	EndMs   *int64 `json:"end_ms"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (r EpochMillis) MarshalJSON() ([]byte, error) {
+	rng := Range(r).applyPrecision()
+	st, end := rng.st.UnixMilli(), rng.End().UnixMilli()
+	return json.Marshal(jsonEpochMillis{StartMs: &st, EndMs: &end})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (r *EpochMillis) UnmarshalJSON(b []byte) error {
+	var jr jsonEpochMillis
+	if err := decodeJSON(b, &jr); err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformedRange, err)
+	}
+
+	if CurrentDecodeMode() != DecodeLenient && (jr.StartMs == nil || jr.EndMs == nil) {
+		return fmt.Errorf("%w: missing boundary", ErrMalformedRange)
+	}
+
+	var startMs, endMs int64
+	if jr.StartMs != nil {
+		startMs = *jr.StartMs
+	}
+	if jr.EndMs != nil {
+		endMs = *jr.EndMs
+	}
+
+	rng, err := between(time.UnixMilli(startMs).UTC(), time.UnixMilli(endMs).UTC())
+	if err != nil {
+		return err
+	}
+
+	*r = EpochMillis(rng)
+	return nil
+}
+
+type jsonSet struct {
+	V      int     `json:"v"`
+	Ranges []Range `json:"ranges"`
+}
+
+// MarshalJSON implements json.Marshaler. The set is represented as the
+// versioned envelope with the ranges encoded by Range.MarshalJSON, e.g.
+// {"v":1,"ranges":[{"start":"...","end":"..."}]}.
+func (s Set) MarshalJSON() ([]byte, error) {
+	rngs := s.rngs
+	if rngs == nil {
+		rngs = []Range{}
+	}
+	return json.Marshal(jsonSet{V: CodecVersion, Ranges: rngs})
+}
+
+// MarshalCanonical encodes the set in the canonical JSON: the versioned
+// envelope with sorted keys, without spaces and with the ranges encoded by
+// Range.MarshalCanonical, e.g. {"ranges":[...],"v":1}. The result is
+// decoded by UnmarshalJSON.
+func (s Set) MarshalCanonical() ([]byte, error) {
+	b := append([]byte(nil), `{"ranges":[`...)
+	for i, r := range s.rngs {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = r.appendCanonical(b)
+	}
+	b = append(b, `],"v":`...)
+	b = strconv.AppendInt(b, CodecVersion, 10)
+	return append(b, '}'), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler and parses the set formatted
+// by MarshalJSON. The envelope without version is treated as the first
+// version. Returns ErrUnsupportedVersion if the version is newer than
+// CodecVersion.
+func (s *Set) UnmarshalJSON(b []byte) error {
+	var js jsonSet
+	if err := decodeJSON(b, &js); err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformedRange, err)
+	}
+	if err := checkVersion(js.V); err != nil {
+		return err
+	}
+
+	s.Replace(js.Ranges...)
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The set is encoded as
+// the version byte followed by the number of ranges and the start (Unix
+// seconds and nanoseconds) and the duration of each range as varints.
+// The locations of the ranges are not preserved.
+func (s Set) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, 1+binary.MaxVarintLen64*(1+3*len(s.rngs)))
+	b = append(b, CodecVersion)
+	b = appendUvarint(b, uint64(len(s.rngs)))
+	for _, r := range s.rngs {
+		b = appendVarint(b, r.st.Unix())
+		b = appendUvarint(b, uint64(r.st.Nanosecond()))
+		b = appendVarint(b, int64(r.dur))
+	}
+	return b, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler and decodes the set
+// encoded by MarshalBinary, the ranges are in UTC. Each range is decoded
+// in the current DecodeMode, the same way as by UnmarshalJSON. Trailing
+// bytes, which might be added by the future versions, are ignored. Returns
+// ErrUnsupportedVersion if the version is newer than CodecVersion.
+func (s *Set) UnmarshalBinary(b []byte) error {
+	if len(b) == 0 {
+		return fmt.Errorf("%w: empty input", ErrMalformedRange)
+	}
+	if err := checkVersion(int(b[0])); err != nil {
+		return err
+	}
+
+	d := binaryDecoder{b: b[1:]}
+	n := d.uvarint()
+	if d.err == nil && n > uint64(len(d.b)) {
+		// each range takes at least three bytes, don't allocate too much
+		d.err = fmt.Errorf("%w: too many ranges", ErrMalformedRange)
+	}
+
+	rngs := make([]Range, 0, n)
+	for i := uint64(0); i < n && d.err == nil; i++ {
+		sec, nsec, dur := d.varint(), d.uvarint(), d.varint()
+		if d.err != nil {
+			break
+		}
+		if nsec >= uint64(time.Second) {
+			return fmt.Errorf("%w: range %d: nanoseconds %d out of range", ErrMalformedRange, i, nsec)
+		}
+
+		st := time.Unix(sec, int64(nsec)).UTC()
+		r, err := between(st, st.Add(time.Duration(dur)))
+		if err != nil {
+			return fmt.Errorf("range %d: %w", i, err)
+		}
+		rngs = append(rngs, r)
+	}
+	if d.err != nil {
+		return d.err
+	}
+
+	s.Replace(rngs...)
+	return nil
+}
+
+// checkVersion returns ErrUnsupportedVersion if the encoding version is
+// not supported, zero version is treated as the first one.
+func checkVersion(v int) error {
+	if v > CodecVersion || v < 0 {
+		return fmt.Errorf("%w: %d", ErrUnsupportedVersion, v)
+	}
+	return nil
+}
+
+func appendVarint(b []byte, v int64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutVarint(buf[:], v)]...)
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutUvarint(buf[:], v)]...)
+}
+
+// binaryDecoder reads varints, keeping the first error.
+type binaryDecoder struct {
+	b   []byte
+	err error
+}
+
+func (d *binaryDecoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.b)
+	if n <= 0 {
+		d.err = fmt.Errorf("%w: truncated input", ErrMalformedRange)
+		return 0
+	}
+	d.b = d.b[n:]
+	return v
+}
+
+func (d *binaryDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.b)
+	if n <= 0 {
+		d.err = fmt.Errorf("%w: truncated input", ErrMalformedRange)
+		return 0
+	}
+	d.b = d.b[n:]
+	return v
+}
+
+func (r *Range) parse(start, end string) error {
+	st, err := ParseRFC9557(start)
+	if err != nil {
+		return err
+	}
+
+	e, err := ParseRFC9557(end)
+	if err != nil {
+		return err
+	}
+
+	rng, err := between(st, e)
+	if err != nil {
+		return err
+	}
+
+	*r = rng
+	return nil
+}
+
+// isIANAZone returns true if the location name is resolved by the
+// package-wide loader, e.g. the names of the fixed zones like "MSK" are
+// not, so the parsers wouldn't restore them.
+func isIANAZone(name string) bool {
+	return name != "" && name != "UTC" && name != "Local" && IsLoadable(name)
+}
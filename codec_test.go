@@ -0,0 +1,337 @@
+package trn
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func berlin(t *testing.T) *time.Location {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("tzdata is not available")
+	}
+	return loc
+}
+
+func TestFormatRFC9557(t *testing.T) {
+	assert.Equal(t, "2021-06-12T13:00:00Z", FormatRFC9557(tm(13, 0)))
+	assert.Equal(t, "2021-06-12T15:00:00+02:00[Europe/Berlin]", FormatRFC9557(tm(13, 0).In(berlin(t))))
+	assert.Equal(t, "2021-06-12T16:00:00.5+03:00", FormatRFC9557(
+		tm(13, 0).Add(500*time.Millisecond).In(time.FixedZone("", 3*60*60)),
+	))
+
+	// named fixed zone, which couldn't be loaded
+	msk := tm(13, 0).In(time.FixedZone("MSK", 3*60*60))
+	assert.Equal(t, "2021-06-12T16:00:00+03:00", FormatRFC9557(msk))
+
+	got, err := ParseRFC9557(FormatRFC9557(msk))
+	require.NoError(t, err)
+	assert.True(t, msk.Equal(got))
+}
+
+func TestParseRFC9557(t *testing.T) {
+	_ = berlin(t) // skip if tzdata is not available
+
+	tests := []struct {
+		name    string
+		arg     string
+		want    time.Time
+		wantLoc string
+		wantErr error
+	}{
+		{name: "plain rfc3339", arg: "2021-06-12T13:00:00Z", want: tm(13, 0), wantLoc: "UTC"},
+		{
+			name: "with zone", arg: "2021-06-12T15:00:00+02:00[Europe/Berlin]",
+			want: tm(13, 0), wantLoc: "Europe/Berlin",
+		},
+		{
+			name: "critical zone with utc", arg: "2021-06-12T13:00:00Z[!Europe/Berlin]",
+			want: tm(13, 0), wantLoc: "Europe/Berlin",
+		},
+		{
+			name: "with extension tag", arg: "2021-06-12T15:00:00+02:00[Europe/Berlin][u-ca=iso8601]",
+			want: tm(13, 0), wantLoc: "Europe/Berlin",
+		},
+		{
+			name: "critical extension tag", arg: "2021-06-12T15:00:00+02:00[!u-ca=iso8601]",
+			wantErr: ErrMalformedTimestamp,
+		},
+		{
+			name: "inconsistent offset", arg: "2021-06-12T15:00:00+03:00[Europe/Berlin]",
+			wantErr: ErrInconsistentZone,
+		},
+		{name: "unknown zone", arg: "2021-06-12T15:00:00+02:00[Mars/Olympus]", wantErr: ErrMalformedTimestamp},
+		{name: "unclosed tag", arg: "2021-06-12T15:00:00+02:00Europe/Berlin]", wantErr: ErrMalformedTimestamp},
+		{name: "malformed time", arg: "2021-06-12 15:00", wantErr: ErrMalformedTimestamp},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRFC9557(tt.arg)
+			require.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErr != nil {
+				return
+			}
+			assert.True(t, tt.want.Equal(got), "got %s", got)
+			assert.Equal(t, tt.wantLoc, got.Location().String())
+		})
+	}
+}
+
+func TestRange_MarshalText(t *testing.T) {
+	rng := New(tm(13, 0).In(berlin(t)), time.Hour)
+
+	b, err := rng.MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "2021-06-12T15:00:00+02:00[Europe/Berlin]/2021-06-12T16:00:00+02:00[Europe/Berlin]", string(b))
+
+	var got Range
+	require.NoError(t, got.UnmarshalText(b))
+	assert.True(t, rng.Start().Equal(got.Start()))
+	assert.Equal(t, rng.Duration(), got.Duration())
+	assert.Equal(t, "Europe/Berlin", got.Start().Location().String())
+
+	assert.ErrorIs(t, got.UnmarshalText([]byte("2021-06-12T15:00:00Z")), ErrMalformedRange)
+	assert.ErrorIs(t, got.UnmarshalText([]byte("2021-06-12T15:00:00Z/2021-06-12T14:00:00Z")), ErrStartAfterEnd)
+}
+
+func TestRange_MarshalJSON(t *testing.T) {
+	rng := New(tm(13, 0).In(berlin(t)), time.Hour)
+
+	b, err := json.Marshal(rng)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"start": "2021-06-12T15:00:00+02:00[Europe/Berlin]",
+		"end": "2021-06-12T16:00:00+02:00[Europe/Berlin]"
+	}`, string(b))
+
+	var got Range
+	require.NoError(t, json.Unmarshal(b, &got))
+	assert.True(t, rng.Start().Equal(got.Start()))
+	assert.Equal(t, rng.Duration(), got.Duration())
+	assert.Equal(t, "Europe/Berlin", got.Start().Location().String())
+
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"start": 1}`), &got), ErrMalformedRange)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"start": "2021-06-12T15:00:00Z", "end": "blah"}`), &got),
+		ErrMalformedTimestamp)
+}
+
+func TestRange_MarshalCanonical(t *testing.T) {
+	r := New(dhm(12, 13, 0).Add(1500*time.Millisecond), time.Hour)
+
+	b, err := r.MarshalCanonical()
+	require.NoError(t, err)
+	assert.Equal(t, `{"end":"2021-06-12T14:00:01.500000000Z","start":"2021-06-12T13:00:01.500000000Z"}`, string(b))
+
+	inBerlin, err := r.In(berlin(t)).MarshalCanonical()
+	require.NoError(t, err)
+	assert.Equal(t, b, inBerlin)
+
+	var got Range
+	require.NoError(t, json.Unmarshal(b, &got))
+	assert.Equal(t, r, got)
+}
+
+func TestSet_MarshalCanonical(t *testing.T) {
+	s := NewSet(
+		New(dhm(12, 15, 0), time.Hour).In(berlin(t)),
+		New(dhm(12, 13, 0), time.Hour),
+	)
+
+	b, err := s.MarshalCanonical()
+	require.NoError(t, err)
+	assert.Equal(t, `{"ranges":[`+
+		`{"end":"2021-06-12T14:00:00.000000000Z","start":"2021-06-12T13:00:00.000000000Z"},`+
+		`{"end":"2021-06-12T16:00:00.000000000Z","start":"2021-06-12T15:00:00.000000000Z"}`+
+		`],"v":1}`, string(b))
+
+	var got Set
+	require.NoError(t, json.Unmarshal(b, &got))
+	assert.Equal(t, []Range{New(dhm(12, 13, 0), time.Hour), New(dhm(12, 15, 0), time.Hour)}, got.Ranges())
+
+	b, err = Set{}.MarshalCanonical()
+	require.NoError(t, err)
+	assert.Equal(t, `{"ranges":[],"v":1}`, string(b))
+}
+
+func TestEpochMillis(t *testing.T) {
+	rng := New(tm(13, 0).Add(1500*time.Microsecond).In(berlin(t)), time.Hour)
+
+	b, err := json.Marshal(EpochMillis(rng))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"start_ms": 1623502800001, "end_ms": 1623506400001}`, string(b))
+
+	var got EpochMillis
+	require.NoError(t, json.Unmarshal(b, &got))
+	assert.Equal(t, New(tm(13, 0).Add(time.Millisecond), time.Hour), Range(got))
+
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"start_ms": 2, "end_ms": 1}`), &got), ErrStartAfterEnd)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"start_ms": "1"}`), &got), ErrMalformedRange)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"start_ms": 1}`), &got), ErrMalformedRange)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"end_ms": 1}`), &got), ErrMalformedRange)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{}`), &got), ErrMalformedRange)
+}
+
+func TestSet_MarshalJSON(t *testing.T) {
+	s := NewSet(New(dhm(12, 9, 0), time.Hour), New(dhm(12, 13, 0), time.Hour))
+
+	b, err := json.Marshal(s)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"v":1,"ranges":[`+
+		`{"start":"2021-06-12T09:00:00Z","end":"2021-06-12T10:00:00Z"},`+
+		`{"start":"2021-06-12T13:00:00Z","end":"2021-06-12T14:00:00Z"}]}`, string(b))
+
+	var got Set
+	require.NoError(t, json.Unmarshal(b, &got))
+	assert.Equal(t, s, got)
+
+	b, err = json.Marshal(Set{})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"v":1,"ranges":[]}`, string(b))
+
+	t.Run("forward compatibility", func(t *testing.T) {
+		var got Set
+		require.NoError(t, json.Unmarshal([]byte(`{"v":1,"tz":"UTC","ranges":[`+
+			`{"start":"2021-06-12T09:00:00Z","end":"2021-06-12T10:00:00Z","inclusive":true}]}`), &got))
+		assert.Equal(t, NewSet(New(dhm(12, 9, 0), time.Hour)), got)
+
+		require.NoError(t, json.Unmarshal([]byte(`{"ranges":[]}`), &got), "no version")
+		assert.Zero(t, got.Len())
+
+		assert.ErrorIs(t, json.Unmarshal([]byte(`{"v":2,"ranges":[]}`), &got), ErrUnsupportedVersion)
+	})
+}
+
+func TestSet_MarshalBinary(t *testing.T) {
+	s := NewSet(New(dhm(12, 9, 0).Add(123), time.Hour), New(time.Date(1960, 1, 1, 0, 0, 0, 5, time.UTC), time.Minute))
+
+	b, err := s.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, byte(CodecVersion), b[0])
+
+	var got Set
+	require.NoError(t, got.UnmarshalBinary(b))
+	assert.Equal(t, s, got)
+
+	require.NoError(t, got.UnmarshalBinary(append(b, 1, 2, 3)), "trailing bytes of the future versions")
+	assert.Equal(t, s, got)
+
+	assert.ErrorIs(t, got.UnmarshalBinary([]byte{2, 0}), ErrUnsupportedVersion)
+	assert.ErrorIs(t, got.UnmarshalBinary(nil), ErrMalformedRange)
+	assert.ErrorIs(t, got.UnmarshalBinary(b[:len(b)-1]), ErrMalformedRange)
+	assert.ErrorIs(t, got.UnmarshalBinary([]byte{1, 0xff, 0xff, 0x03}), ErrMalformedRange)
+
+	b, err = Set{}.MarshalBinary()
+	require.NoError(t, err)
+	require.NoError(t, got.UnmarshalBinary(b))
+	assert.Zero(t, got.Len())
+
+	t.Run("decode modes", func(t *testing.T) {
+		defer SetDecodeMode(DecodeDefault)
+
+		st := dhm(12, 9, 0)
+		reversed := binaryRange(st.Unix(), 0, -2)
+		nsec := binaryRange(st.Unix(), uint64(time.Second), int64(time.Hour))
+
+		for _, mode := range []DecodeMode{DecodeDefault, DecodeStrict} {
+			SetDecodeMode(mode)
+			assert.ErrorIs(t, got.UnmarshalBinary(reversed), ErrStartAfterEnd, "mode %d", mode)
+			assert.ErrorIs(t, got.UnmarshalBinary(nsec), ErrMalformedRange, "mode %d", mode)
+		}
+
+		SetDecodeMode(DecodeLenient)
+		require.NoError(t, got.UnmarshalBinary(reversed))
+		assert.Zero(t, got.Len())
+		assert.ErrorIs(t, got.UnmarshalBinary(nsec), ErrMalformedRange)
+	})
+
+	t.Run("precision", func(t *testing.T) {
+		SetPrecision(time.Minute)
+		defer SetPrecision(0)
+
+		require.NoError(t, got.UnmarshalBinary(binaryRange(dhm(12, 9, 0).Unix()+20, 0, int64(time.Hour))))
+		assert.Equal(t, []Range{New(dhm(12, 9, 0), time.Hour)}, got.Ranges())
+	})
+}
+
+// binaryRange returns the binary encoding of the set of a single range.
+func binaryRange(sec int64, nsec uint64, dur int64) []byte {
+	buf := make([]byte, binary.MaxVarintLen64)
+	b := []byte{CodecVersion, 1}
+	b = append(b, buf[:binary.PutVarint(buf, sec)]...)
+	b = append(b, buf[:binary.PutUvarint(buf, nsec)]...)
+	return append(b, buf[:binary.PutVarint(buf, dur)]...)
+}
+
+func TestSetDecodeMode(t *testing.T) {
+	const (
+		reversed = `{"start":"2021-06-12T14:00:00Z","end":"2021-06-12T13:00:00Z"}`
+		zero     = `{"start":"0001-01-01T00:00:00Z","end":"0001-01-01T01:00:00Z"}`
+		overflow = `{"start":"0001-01-01T00:00:00Z","end":"2021-06-12T13:00:00Z"}`
+		unknown  = `{"start":"2021-06-12T13:00:00Z","end":"2021-06-12T14:00:00Z","inclusive":true}`
+	)
+
+	decode := func(s string) (Range, error) {
+		var r Range
+		err := json.Unmarshal([]byte(s), &r)
+		return r, err
+	}
+
+	t.Run("default", func(t *testing.T) {
+		assert.Equal(t, DecodeDefault, CurrentDecodeMode())
+
+		_, err := decode(reversed)
+		assert.ErrorIs(t, err, ErrStartAfterEnd)
+		_, err = decode(zero)
+		assert.NoError(t, err)
+		_, err = decode(overflow)
+		assert.ErrorIs(t, err, ErrDurationOverflow)
+		_, err = decode(unknown)
+		assert.NoError(t, err)
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		SetDecodeMode(DecodeStrict)
+		defer SetDecodeMode(DecodeDefault)
+
+		_, err := decode(reversed)
+		assert.ErrorIs(t, err, ErrStartAfterEnd)
+		_, err = decode(zero)
+		assert.ErrorIs(t, err, ErrMalformedRange)
+		_, err = decode(unknown)
+		assert.ErrorIs(t, err, ErrMalformedRange)
+
+		var em EpochMillis
+		assert.ErrorIs(t, json.Unmarshal([]byte(`{"start_ms":0}`), &em), ErrMalformedRange)
+
+		var s Set
+		assert.Error(t, json.Unmarshal([]byte(`{"v":1,"ranges":[],"tz":"UTC"}`), &s))
+
+		_, err = UnmarshalChange([]byte(`{"v":1,"kind":"range_booked","source":"calendar",` +
+			`"range":{"start":"2021-06-12T12:00:00Z","end":"2021-06-12T13:00:00Z"}}`))
+		assert.ErrorIs(t, err, ErrMalformedRange)
+
+		_, err = UnmarshalChange([]byte(`{"v":1,"kind":"range_booked",` +
+			`"range":{"start":"2021-06-12T12:00:00Z","end":"2021-06-12T13:00:00Z"}}`))
+		assert.NoError(t, err)
+	})
+
+	t.Run("lenient", func(t *testing.T) {
+		SetDecodeMode(DecodeLenient)
+		defer SetDecodeMode(DecodeDefault)
+
+		r, err := decode(reversed)
+		require.NoError(t, err)
+		assert.Equal(t, New(dhm(12, 14, 0), 0), r)
+
+		var em EpochMillis
+		require.NoError(t, json.Unmarshal([]byte(`{"start_ms":1623506400000,"end_ms":0}`), &em))
+		assert.Equal(t, time.Duration(0), Range(em).Duration())
+		require.NoError(t, json.Unmarshal([]byte(`{"end_ms":1623506400000}`), &em))
+		assert.Equal(t, New(time.UnixMilli(0).UTC(), 1623506400000*time.Millisecond), Range(em))
+	})
+}
@@ -0,0 +1,68 @@
+package converters
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/cappuccinotm/trn"
+)
+
+// avroMagic starts the Avro object container file.
+var avroMagic = []byte{'O', 'b', 'j', 1}
+
+// WriteAvro writes the ranges as the records into w in the Avro object
+// container file format with the AvroSchema, uncompressed, in a single
+// block, e.g. to upload the availability snapshot to a data lake. There is
+// no Parquet counterpart, the struct tags of Record are meant for the
+// Parquet writers of other libraries.
+func WriteAvro(w io.Writer, rngs []trn.Range) error {
+	var sync [16]byte
+	if _, err := rand.Read(sync[:]); err != nil {
+		return fmt.Errorf("make sync marker: %w", err)
+	}
+
+	b := append([]byte(nil), avroMagic...)
+
+	// file metadata is the map of bytes, written as a single block
+	b = appendAvroLong(b, 2)
+	b = appendAvroString(b, "avro.schema")
+	b = appendAvroString(b, AvroSchema)
+	b = appendAvroString(b, "avro.codec")
+	b = appendAvroString(b, "null")
+	b = appendAvroLong(b, 0)
+	b = append(b, sync[:]...)
+
+	if len(rngs) > 0 {
+		var data []byte
+		for _, r := range rngs {
+			rec := FromRange(r)
+			data = appendAvroLong(data, rec.Start)
+			data = appendAvroLong(data, rec.End)
+			data = appendAvroString(data, rec.TZ)
+		}
+
+		b = appendAvroLong(b, int64(len(rngs)))
+		b = appendAvroLong(b, int64(len(data)))
+		b = append(b, data...)
+		b = append(b, sync[:]...)
+	}
+
+	if _, err := w.Write(b); err != nil {
+		return fmt.Errorf("write avro: %w", err)
+	}
+	return nil
+}
+
+// appendAvroLong appends the zig-zag varint encoding of v, which is the
+// same in Avro and in encoding/binary.
+func appendAvroLong(b []byte, v int64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutVarint(buf[:], v)]...)
+}
+
+// appendAvroString appends the length of s followed by its bytes.
+func appendAvroString(b []byte, s string) []byte {
+	return append(appendAvroLong(b, int64(len(s))), s...)
+}
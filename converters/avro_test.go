@@ -0,0 +1,73 @@
+package converters
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/cappuccinotm/trn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteAvro(t *testing.T) {
+	rngs := []trn.Range{
+		trn.New(dt, time.Hour),
+		trn.New(dt.In(time.FixedZone("", -7*60*60)), 30*time.Minute),
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, WriteAvro(buf, rngs))
+
+	rd := bytes.NewReader(buf.Bytes())
+	readLong := func() int64 {
+		v, err := binary.ReadVarint(rd)
+		require.NoError(t, err)
+		return v
+	}
+	readString := func() string {
+		b := make([]byte, readLong())
+		_, err := io.ReadFull(rd, b)
+		require.NoError(t, err)
+		return string(b)
+	}
+	readSync := func() []byte {
+		b := make([]byte, 16)
+		_, err := io.ReadFull(rd, b)
+		require.NoError(t, err)
+		return b
+	}
+
+	magic := make([]byte, 4)
+	_, err := io.ReadFull(rd, magic)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("Obj\x01"), magic)
+
+	meta := map[string]string{}
+	for n := readLong(); n > 0; n = readLong() {
+		for i := int64(0); i < n; i++ {
+			meta[readString()] = readString()
+		}
+	}
+	assert.Equal(t, map[string]string{"avro.schema": AvroSchema, "avro.codec": "null"}, meta)
+	sync := readSync()
+
+	require.Equal(t, int64(len(rngs)), readLong())
+	readLong() // block size
+
+	var recs []Record
+	for range rngs {
+		recs = append(recs, Record{Start: readLong(), End: readLong(), TZ: readString()})
+	}
+	assert.Equal(t, FromRanges(rngs), recs)
+	assert.Equal(t, sync, readSync())
+	assert.Zero(t, rd.Len())
+
+	t.Run("no ranges", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, WriteAvro(buf, nil))
+		assert.True(t, bytes.HasPrefix(buf.Bytes(), []byte("Obj\x01")))
+	})
+}
@@ -0,0 +1,190 @@
+package converters
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cappuccinotm/trn"
+)
+
+// ErrMalformedISODuration is returned when the ISO 8601 duration can't be
+// parsed.
+const ErrMalformedISODuration = trn.Error("converters: malformed ISO 8601 duration")
+
+// FromSpan converts the time span of other libraries, e.g. timespan.TimeSpan
+// of github.com/rickb777/date, into the range.
+func FromSpan(s trn.Interval) (trn.Range, error) { return trn.Between(s.Start(), s.End()) }
+
+// FromSpans converts the time spans into the ranges.
+func FromSpans[T trn.Interval](spans []T) ([]trn.Range, error) {
+	res := make([]trn.Range, len(spans))
+	for i, s := range spans {
+		var err error
+		if res[i], err = FromSpan(s); err != nil {
+			return nil, fmt.Errorf("span %d: %w", i, err)
+		}
+	}
+	return res, nil
+}
+
+// StartAndDuration returns the start and the duration of the range, which
+// fit the constructors of other libraries, e.g.
+//
+//	timespan.TimeSpanOf(converters.StartAndDuration(rng))
+func StartAndDuration(r trn.Range) (time.Time, time.Duration) { return r.Start(), r.Duration() }
+
+// FromPair converts the pair of the start and the end into the range.
+func FromPair(p [2]time.Time) (trn.Range, error) { return trn.Between(p[0], p[1]) }
+
+// ToPair converts the range into the pair of its start and end.
+func ToPair(r trn.Range) [2]time.Time { return [2]time.Time{r.Start(), r.End()} }
+
+// FromPairs converts the pairs into the ranges.
+func FromPairs(pairs [][2]time.Time) ([]trn.Range, error) {
+	res := make([]trn.Range, len(pairs))
+	for i, p := range pairs {
+		var err error
+		if res[i], err = FromPair(p); err != nil {
+			return nil, fmt.Errorf("pair %d: %w", i, err)
+		}
+	}
+	return res, nil
+}
+
+// ToPairs converts the ranges into the pairs.
+func ToPairs(rngs []trn.Range) [][2]time.Time {
+	res := make([][2]time.Time, len(rngs))
+	for i, r := range rngs {
+		res[i] = ToPair(r)
+	}
+	return res
+}
+
+// FromISODuration returns the range of the ISO 8601 duration, e.g. "P1DT2H"
+// or "PT1.5S", as stored by ISO 8601 duration libraries, since the start.
+// Years, months, weeks and days are added as calendar units in the
+// location of the start, so "P1D" might last 23 or 25 hours on DST
+// transitions. Only the seconds may have the fraction, negative durations
+// are not supported. Returns ErrMalformedISODuration if the duration can't
+// be parsed and trn.ErrMalformedRange if its hours, minutes and seconds
+// overflow time.Duration.
+func FromISODuration(start time.Time, iso string) (trn.Range, error) {
+	years, months, days, exact, err := parseISODuration(iso)
+	if err != nil {
+		return trn.Range{}, err
+	}
+	return trn.Between(start, start.AddDate(years, months, days).Add(exact))
+}
+
+// ISODuration formats the duration of the range as the ISO 8601 duration
+// of hours, minutes and seconds, e.g. "PT36H30M" or "PT0.5S". Calendar
+// units are not used, as their duration depends on the date.
+func ISODuration(r trn.Range) string {
+	d := r.Duration()
+	if d == 0 {
+		return "PT0S"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("PT")
+	if h := d / time.Hour; h > 0 {
+		sb.WriteString(strconv.FormatInt(int64(h), 10) + "H")
+	}
+	if m := d % time.Hour / time.Minute; m > 0 {
+		sb.WriteString(strconv.FormatInt(int64(m), 10) + "M")
+	}
+	if s := d % time.Minute; s > 0 {
+		sec := strconv.FormatInt(int64(s/time.Second), 10)
+		if ns := s % time.Second; ns > 0 {
+			sec += strings.TrimRight(fmt.Sprintf(".%09d", ns), "0")
+		}
+		sb.WriteString(sec + "S")
+	}
+	return sb.String()
+}
+
+// parseISODuration parses the ISO 8601 duration into the calendar units
+// and the exact duration.
+func parseISODuration(s string) (years, months, days int, exact time.Duration, err error) {
+	malformed := func() (int, int, int, time.Duration, error) {
+		return 0, 0, 0, 0, fmt.Errorf("%w: %q", ErrMalformedISODuration, s)
+	}
+	overflow := func() (int, int, int, time.Duration, error) {
+		return 0, 0, 0, 0, fmt.Errorf("%w: duration %q overflows", trn.ErrMalformedRange, s)
+	}
+
+	if len(s) < 3 || s[0] != 'P' {
+		return malformed()
+	}
+
+	order, rest, inTime := "YMWD", s[1:], false
+	for rest != "" {
+		if rest[0] == 'T' {
+			if inTime || len(rest) == 1 {
+				return malformed()
+			}
+			order, rest, inTime = "HMS", rest[1:], true
+			continue
+		}
+
+		i := strings.IndexFunc(rest, func(c rune) bool { return (c < '0' || c > '9') && c != '.' && c != ',' })
+		if i <= 0 {
+			return malformed()
+		}
+
+		// designators must follow in order and at most once
+		num, unit := strings.ReplaceAll(rest[:i], ",", "."), rest[i]
+		pos := strings.IndexByte(order, unit)
+		if pos < 0 {
+			return malformed()
+		}
+		order, rest = order[pos+1:], rest[i+1:]
+
+		if unit == 'S' {
+			d, err := time.ParseDuration(num + "s")
+			switch {
+			case err != nil && (num == "." || strings.Count(num, ".") > 1):
+				return malformed()
+			case err != nil, exact > math.MaxInt64-d:
+				// the number itself is well-formed, so it's too large
+				return overflow()
+			}
+			exact += d
+			continue
+		}
+
+		n, err := strconv.Atoi(num)
+		switch {
+		case errors.Is(err, strconv.ErrRange) && inTime:
+			return overflow()
+		case err != nil:
+			return malformed()
+		}
+
+		switch {
+		case unit == 'Y':
+			years = n
+		case unit == 'M' && !inTime:
+			months = n
+		case unit == 'W':
+			days += 7 * n
+		case unit == 'D':
+			days += n
+		case unit == 'H', unit == 'M':
+			d := time.Hour
+			if unit == 'M' {
+				d = time.Minute
+			}
+			if time.Duration(n) > (math.MaxInt64-exact)/d {
+				return overflow()
+			}
+			exact += time.Duration(n) * d
+		}
+	}
+
+	return years, months, days, exact, nil
+}
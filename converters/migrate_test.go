@@ -0,0 +1,119 @@
+package converters
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/cappuccinotm/trn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// timeSpan mimics timespan.TimeSpan of github.com/rickb777/date
+type timeSpan struct {
+	mark     time.Time
+	duration time.Duration
+}
+
+func timeSpanOf(start time.Time, d time.Duration) timeSpan { return timeSpan{mark: start, duration: d} }
+
+func (ts timeSpan) Start() time.Time { return ts.mark }
+func (ts timeSpan) End() time.Time   { return ts.mark.Add(ts.duration) }
+
+func TestFromSpans(t *testing.T) {
+	st := time.Date(2021, time.June, 12, 9, 0, 0, 0, time.UTC)
+
+	rngs, err := FromSpans([]timeSpan{timeSpanOf(st, time.Hour), timeSpanOf(st.Add(2*time.Hour), time.Minute)})
+	require.NoError(t, err)
+	assert.Equal(t, []trn.Range{trn.New(st, time.Hour), trn.New(st.Add(2*time.Hour), time.Minute)}, rngs)
+
+	_, err = FromSpans([]timeSpan{timeSpanOf(st, time.Hour), timeSpanOf(st, -time.Hour)})
+	assert.ErrorIs(t, err, trn.ErrStartAfterEnd)
+	assert.Contains(t, err.Error(), "span 1")
+
+	assert.Equal(t, timeSpanOf(st, time.Hour), timeSpanOf(StartAndDuration(trn.New(st, time.Hour))))
+}
+
+func TestFromPairs(t *testing.T) {
+	st := time.Date(2021, time.June, 12, 9, 0, 0, 0, time.UTC)
+	pairs := [][2]time.Time{{st, st.Add(time.Hour)}, {st.Add(2 * time.Hour), st.Add(3 * time.Hour)}}
+
+	rngs, err := FromPairs(pairs)
+	require.NoError(t, err)
+	assert.Equal(t, []trn.Range{trn.New(st, time.Hour), trn.New(st.Add(2*time.Hour), time.Hour)}, rngs)
+	assert.Equal(t, pairs, ToPairs(rngs))
+
+	_, err = FromPairs([][2]time.Time{{st.Add(time.Hour), st}})
+	assert.ErrorIs(t, err, trn.ErrStartAfterEnd)
+	assert.Contains(t, err.Error(), "pair 0")
+}
+
+func TestFromISODuration(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	st := time.Date(2021, time.March, 27, 12, 0, 0, 0, berlin) // the day before DST starts
+
+	tests := []struct {
+		iso     string
+		want    time.Time
+		wantErr bool
+	}{
+		{iso: "PT1H30M", want: st.Add(90 * time.Minute)},
+		{iso: "P1D", want: time.Date(2021, time.March, 28, 12, 0, 0, 0, berlin)},
+		{iso: "PT24H", want: time.Date(2021, time.March, 28, 13, 0, 0, 0, berlin)},
+		{iso: "P1Y2M1W", want: time.Date(2022, time.June, 3, 12, 0, 0, 0, berlin)},
+		{iso: "P1MT1M", want: time.Date(2021, time.April, 27, 12, 1, 0, 0, berlin)},
+		{iso: "PT1,5S", want: st.Add(1500 * time.Millisecond)},
+		{iso: "PT0S", want: st},
+		{iso: "P", wantErr: true},
+		{iso: "PT", wantErr: true},
+		{iso: "P1DT", wantErr: true},
+		{iso: "1D", wantErr: true},
+		{iso: "P1H", wantErr: true},
+		{iso: "PT1D", wantErr: true},
+		{iso: "P1D1Y", wantErr: true},
+		{iso: "PT1.5H", wantErr: true},
+		{iso: "P-1D", wantErr: true},
+		{iso: "PT1HT1M", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.iso, func(t *testing.T) {
+			got, err := FromISODuration(st, tt.iso)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrMalformedISODuration)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, st, got.Start())
+			assert.True(t, tt.want.Equal(got.End()), "got %s", got.End())
+		})
+	}
+
+	t.Run("overflow", func(t *testing.T) {
+		for _, iso := range []string{
+			"PT3000000H", "PT200000000M", "PT10000000000S", "PT99999999999999999999H",
+			"PT2562047H60M", "PT2562047H47M17S", "PT9223372036.9S",
+		} {
+			_, err := FromISODuration(st, iso)
+			assert.ErrorIs(t, err, trn.ErrMalformedRange, iso)
+		}
+
+		got, err := FromISODuration(st, "PT2562047H47M16S")
+		require.NoError(t, err)
+		assert.Equal(t, time.Duration(math.MaxInt64)/time.Second*time.Second, got.Duration())
+	})
+}
+
+func TestISODuration(t *testing.T) {
+	st := time.Date(2021, time.June, 12, 9, 0, 0, 0, time.UTC)
+	assert.Equal(t, "PT0S", ISODuration(trn.New(st, 0)))
+	assert.Equal(t, "PT36H30M", ISODuration(trn.New(st, 36*time.Hour+30*time.Minute)))
+	assert.Equal(t, "PT1M0.25S", ISODuration(trn.New(st, time.Minute+250*time.Millisecond)))
+	assert.Equal(t, "PT0.000000001S", ISODuration(trn.New(st, 1)))
+
+	rng := trn.New(st, 25*time.Hour+time.Second)
+	got, err := FromISODuration(st, ISODuration(rng))
+	require.NoError(t, err)
+	assert.Equal(t, rng, got)
+}
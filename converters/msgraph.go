@@ -0,0 +1,130 @@
+package converters
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cappuccinotm/trn"
+)
+
+// ErrMalformedAvailabilityView is returned when the availability view of
+// the Microsoft Graph schedule contains unknown statuses.
+const ErrMalformedAvailabilityView = trn.Error("converters: malformed availability view")
+
+// GraphSchedule is the scheduleInformation item of the Microsoft Graph
+// getSchedule response.
+type GraphSchedule struct {
+	ScheduleID       string              `json:"scheduleId"`
+	AvailabilityView string              `json:"availabilityView"`
+	ScheduleItems    []GraphScheduleItem `json:"scheduleItems"`
+}
+
+// GraphScheduleItem is the item of the schedule, e.g. the meeting.
+type GraphScheduleItem struct {
+	Status string        `json:"status"`
+	Start  GraphDateTime `json:"start"`
+	End    GraphDateTime `json:"end"`
+}
+
+// GraphDateTime is the local date and time in the named time zone.
+// Only IANA time zone names are supported, so the request should be made
+// with `Prefer: outlook.timezone="UTC"` header or an IANA zone name.
+type GraphDateTime struct {
+	DateTime string `json:"dateTime"`
+	TimeZone string `json:"timeZone"`
+}
+
+const graphDateTimeFmt = "2006-01-02T15:04:05.9999999"
+
+// Time returns the time in the location of the time zone.
+func (d GraphDateTime) Time() (time.Time, error) {
+	loc, err := trn.LoadLocation(d.TimeZone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.ParseInLocation(graphDateTimeFmt, d.DateTime, loc)
+}
+
+// graphStatuses maps the Microsoft Graph free-busy statuses to trn ones.
+// Out of office is considered busy, working elsewhere is considered free.
+var graphStatuses = map[string]trn.Status{
+	"free":             trn.StatusFree,
+	"workingElsewhere": trn.StatusFree,
+	"tentative":        trn.StatusTentative,
+	"busy":             trn.StatusBusy,
+	"oof":              trn.StatusBusy,
+}
+
+// availability view digits, in the same order as the statuses above
+var graphViewStatuses = map[rune]trn.Status{
+	'0': trn.StatusFree,
+	'4': trn.StatusFree,
+	'1': trn.StatusTentative,
+	'2': trn.StatusBusy,
+	'3': trn.StatusBusy,
+}
+
+// FromGraphScheduleItems converts the schedule items into the status-aware
+// set. Items with unknown status are skipped.
+func FromGraphScheduleItems(items []GraphScheduleItem) (trn.StatusSet, error) {
+	var res trn.StatusSet
+	for i, item := range items {
+		status, ok := graphStatuses[item.Status]
+		if !ok {
+			continue
+		}
+
+		st, err := item.Start.Time()
+		if err != nil {
+			return trn.StatusSet{}, fmt.Errorf("item %d: start: %w", i, err)
+		}
+
+		end, err := item.End.Time()
+		if err != nil {
+			return trn.StatusSet{}, fmt.Errorf("item %d: end: %w", i, err)
+		}
+
+		rng, err := trn.Between(st, end)
+		if err != nil {
+			return trn.StatusSet{}, fmt.Errorf("item %d: %w", i, err)
+		}
+
+		res.Add(rng, status)
+	}
+	return res, nil
+}
+
+// FromGraphAvailabilityView converts the availability view, where each
+// digit is the status of the slot of the given interval, into the
+// status-aware set. The start and the interval must be the ones of the
+// getSchedule request, the interval is 30 minutes by default. Slots with
+// unknown status ('9') are skipped.
+// Returns ErrMalformedAvailabilityView if the view contains other
+// characters.
+func FromGraphAvailabilityView(view string, start time.Time, interval time.Duration) (trn.StatusSet, error) {
+	var res trn.StatusSet
+	for i, c := range []rune(view) {
+		if c == '9' {
+			continue
+		}
+
+		status, ok := graphViewStatuses[c]
+		if !ok {
+			return trn.StatusSet{}, fmt.Errorf("%w: %q at %d", ErrMalformedAvailabilityView, c, i)
+		}
+
+		res.Add(trn.New(start.Add(time.Duration(i)*interval), interval), status)
+	}
+	return res, nil
+}
+
+// StatusSet converts the schedule into the status-aware set, using the
+// schedule items if there are any, as they are more precise, or the
+// availability view otherwise. The start and the interval must be the
+// ones of the getSchedule request.
+func (s GraphSchedule) StatusSet(start time.Time, interval time.Duration) (trn.StatusSet, error) {
+	if len(s.ScheduleItems) > 0 {
+		return FromGraphScheduleItems(s.ScheduleItems)
+	}
+	return FromGraphAvailabilityView(s.AvailabilityView, start, interval)
+}
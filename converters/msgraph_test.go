@@ -0,0 +1,96 @@
+package converters
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/cappuccinotm/trn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const graphResponse = `{
+  "value": [
+    {
+      "scheduleId": "adelev@contoso.onmicrosoft.com",
+      "availabilityView": "000220000",
+      "scheduleItems": [
+        {
+          "isPrivate": false,
+          "status": "busy",
+          "subject": "Let's go for lunch",
+          "start": {"dateTime": "2021-06-12T14:00:00.0000000", "timeZone": "UTC"},
+          "end": {"dateTime": "2021-06-12T15:00:00.0000000", "timeZone": "UTC"}
+        },
+        {
+          "status": "tentative",
+          "start": {"dateTime": "2021-06-12T14:30:00.0000000", "timeZone": "UTC"},
+          "end": {"dateTime": "2021-06-12T16:00:00.0000000", "timeZone": "UTC"}
+        },
+        {
+          "status": "unknown",
+          "start": {"dateTime": "2021-06-12T17:00:00.0000000", "timeZone": "UTC"},
+          "end": {"dateTime": "2021-06-12T18:00:00.0000000", "timeZone": "UTC"}
+        }
+      ]
+    }
+  ]
+}`
+
+func TestGraphSchedule_StatusSet(t *testing.T) {
+	var resp struct {
+		Value []GraphSchedule `json:"value"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(graphResponse), &resp))
+	require.Len(t, resp.Value, 1)
+
+	start := time.Date(2021, 6, 12, 13, 0, 0, 0, time.UTC)
+
+	set, err := resp.Value[0].StatusSet(start, 30*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, []trn.Range{trn.MustRange(trn.Between(dt.Add(time.Hour), dt.Add(2*time.Hour)))},
+		set.Ranges(trn.StatusBusy))
+	assert.Equal(t, []trn.Range{trn.MustRange(trn.Between(dt.Add(2*time.Hour), dt.Add(3*time.Hour)))},
+		set.Ranges(trn.StatusTentative))
+
+	resp.Value[0].ScheduleItems = nil
+	set, err = resp.Value[0].StatusSet(start, 30*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, []trn.Range{trn.MustRange(trn.Between(dt.Add(90*time.Minute), dt.Add(150*time.Minute)))},
+		set.Ranges(trn.StatusBusy))
+	assert.Equal(t, trn.StatusFree, set.StatusAt(start))
+}
+
+func TestFromGraphAvailabilityView(t *testing.T) {
+	set, err := FromGraphAvailabilityView("0139", dt, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, trn.StatusTentative, set.StatusAt(dt.Add(time.Hour)))
+	assert.Equal(t, trn.StatusBusy, set.StatusAt(dt.Add(2*time.Hour)), "out of office")
+	assert.Len(t, set.Segments(), 3, "unknown slot is skipped")
+
+	_, err = FromGraphAvailabilityView("01x", dt, time.Hour)
+	assert.ErrorIs(t, err, ErrMalformedAvailabilityView)
+}
+
+func TestFromGraphScheduleItems(t *testing.T) {
+	_, err := FromGraphScheduleItems([]GraphScheduleItem{{
+		Status: "busy",
+		Start:  GraphDateTime{DateTime: "2021-06-12T14:00:00", TimeZone: "Pacific Standard Time"},
+	}})
+	assert.Error(t, err, "windows time zones are not supported")
+
+	_, err = FromGraphScheduleItems([]GraphScheduleItem{{
+		Status: "busy",
+		Start:  GraphDateTime{DateTime: "2021-06-12T14:00:00", TimeZone: "UTC"},
+		End:    GraphDateTime{DateTime: "2021-06-12T13:00:00", TimeZone: "UTC"},
+	}})
+	assert.ErrorIs(t, err, trn.ErrStartAfterEnd)
+
+	_, err = FromGraphScheduleItems([]GraphScheduleItem{{
+		Status: "busy",
+		Start:  GraphDateTime{DateTime: "2021-06-12T14:00:00", TimeZone: "UTC"},
+		End:    GraphDateTime{DateTime: "blah", TimeZone: "UTC"},
+	}})
+	assert.Error(t, err)
+}
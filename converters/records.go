@@ -0,0 +1,92 @@
+// Package converters provides flat representations of ranges for
+// analytical exports, e.g. to Avro or Parquet files in data lakes, and
+// conversions from the shapes of other libraries and external services.
+package converters
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cappuccinotm/trn"
+)
+
+// AvroSchema is the Avro schema of the Record.
+const AvroSchema = `{
+  "type": "record",
+  "name": "Range",
+  "namespace": "com.github.cappuccinotm.trn",
+  "fields": [
+    {"name": "start", "type": {"type": "long", "logicalType": "timestamp-micros"}},
+    {"name": "end", "type": {"type": "long", "logicalType": "timestamp-micros"}},
+    {"name": "tz", "type": "string"}
+  ]
+}`
+
+// Record is the flat representation of a range. WriteAvro writes the
+// records into Avro files, while the struct tags make them usable directly
+// with the popular Avro (hamba/avro) and Parquet (xitongsys/parquet-go)
+// writers, there is no Parquet writer in this package.
+type Record struct {
+	// Start is the Unix timestamp of the start in microseconds.
+	Start int64 `json:"start" avro:"start" parquet:"name=start, type=INT64, convertedtype=TIMESTAMP_MICROS"`
+	// End is the Unix timestamp of the end in microseconds.
+	End int64 `json:"end" avro:"end" parquet:"name=end, type=INT64, convertedtype=TIMESTAMP_MICROS"`
+	// TZ is the name of the range location, or its UTC offset in format
+	// "-07:00" for the zones, which couldn't be loaded by name, e.g. the
+	// fixed ones.
+	TZ string `json:"tz" avro:"tz" parquet:"name=tz, type=BYTE_ARRAY, convertedtype=UTF8"`
+}
+
+// FromRange converts the range into the record, sub-microsecond parts are
+// truncated.
+func FromRange(r trn.Range) Record {
+	tz := r.Start().Location().String()
+	if tz == "" || !trn.IsLoadable(tz) {
+		tz = r.Start().Format("-07:00")
+	}
+	return Record{Start: r.Start().UnixMicro(), End: r.End().UnixMicro(), TZ: tz}
+}
+
+// FromRanges converts the ranges into the records.
+func FromRanges(rngs []trn.Range) []Record {
+	res := make([]Record, len(rngs))
+	for i, r := range rngs {
+		res[i] = FromRange(r)
+	}
+	return res
+}
+
+// ToRange converts the record back into the range in its location.
+// Empty TZ is treated as UTC.
+func ToRange(rec Record) (trn.Range, error) {
+	loc, err := location(rec.TZ)
+	if err != nil {
+		return trn.Range{}, err
+	}
+	return trn.Between(time.UnixMicro(rec.Start).In(loc), time.UnixMicro(rec.End).In(loc))
+}
+
+// ToRanges converts the records back into the ranges.
+func ToRanges(recs []Record) ([]trn.Range, error) {
+	res := make([]trn.Range, len(recs))
+	for i, rec := range recs {
+		var err error
+		if res[i], err = ToRange(rec); err != nil {
+			return nil, fmt.Errorf("record %d: %w", i, err)
+		}
+	}
+	return res, nil
+}
+
+func location(tz string) (*time.Location, error) {
+	if tz == "" {
+		return time.UTC, nil
+	}
+
+	if t, err := time.Parse("-07:00", tz); err == nil {
+		_, offset := t.Zone()
+		return time.FixedZone("", offset), nil
+	}
+
+	return trn.LoadLocation(tz)
+}
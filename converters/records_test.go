@@ -0,0 +1,57 @@
+package converters
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/cappuccinotm/trn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var dt = time.Date(2021, 6, 12, 13, 0, 0, 0, time.UTC)
+
+func TestRecords(t *testing.T) {
+	rngs := []trn.Range{
+		trn.New(dt, time.Hour),
+		trn.New(dt.In(time.FixedZone("", -7*60*60)), 30*time.Minute),
+		trn.New(dt.In(time.FixedZone("MSK", 3*60*60)), 15*time.Minute),
+	}
+	if loc, err := time.LoadLocation("Europe/Berlin"); err == nil {
+		rngs = append(rngs, trn.New(dt.In(loc), time.Minute))
+	}
+
+	recs := FromRanges(rngs)
+	assert.Equal(t, Record{Start: 1623502800000000, End: 1623506400000000, TZ: "UTC"}, recs[0])
+	assert.Equal(t, "-07:00", recs[1].TZ)
+	assert.Equal(t, "+03:00", recs[2].TZ)
+
+	got, err := ToRanges(recs)
+	require.NoError(t, err)
+	require.Len(t, got, len(rngs))
+	for i := range rngs {
+		assert.True(t, rngs[i].Start().Equal(got[i].Start()))
+		assert.Equal(t, rngs[i].Duration(), got[i].Duration())
+		assert.Equal(t, rngs[i].Start().Format(time.RFC3339), got[i].Start().Format(time.RFC3339))
+	}
+
+	_, err = ToRanges([]Record{{Start: 1, End: 2, TZ: "Mars/Olympus"}})
+	assert.Error(t, err)
+
+	_, err = ToRange(Record{Start: 2, End: 1})
+	assert.ErrorIs(t, err, trn.ErrStartAfterEnd)
+}
+
+func TestAvroSchema(t *testing.T) {
+	var schema struct {
+		Fields []struct {
+			Name string `json:"name"`
+		} `json:"fields"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(AvroSchema), &schema))
+	require.Len(t, schema.Fields, 3)
+	assert.Equal(t, "start", schema.Fields[0].Name)
+	assert.Equal(t, "end", schema.Fields[1].Name)
+	assert.Equal(t, "tz", schema.Fields[2].Name)
+}
@@ -0,0 +1,107 @@
+package converters
+
+import (
+	"strings"
+	"time"
+
+	"github.com/cappuccinotm/trn"
+)
+
+const icalTimeFmt = "20060102T150405Z"
+
+// FreeBusyReply is the reply to the CalDAV or iTIP free-busy query.
+type FreeBusyReply struct {
+	// UID is the unique identifier of the reply.
+	UID string
+	// Organizer and Attendee are the calendar addresses, e.g.
+	// "mailto:jane@example.com", they are omitted if empty.
+	Organizer string
+	Attendee  string
+	// Stamp is the time, when the reply is made.
+	Stamp time.Time
+	// Window is the requested time window.
+	Window trn.Range
+	// Busy and Tentative are the busy and tentatively busy ranges, they are
+	// truncated to the window and merged.
+	Busy      []trn.Range
+	Tentative []trn.Range
+}
+
+// VFreeBusy returns the VFREEBUSY component of the reply, as defined by
+// RFC 5545, with each busy period in a separate FREEBUSY property.
+func (f FreeBusyReply) VFreeBusy() string {
+	sb := &strings.Builder{}
+	f.writeVFreeBusy(sb)
+	return sb.String()
+}
+
+// ICalendar returns the iCalendar object with METHOD:REPLY, containing the
+// VFREEBUSY component of the reply.
+func (f FreeBusyReply) ICalendar() string {
+	sb := &strings.Builder{}
+	writeLine(sb, "BEGIN:VCALENDAR")
+	writeLine(sb, "VERSION:2.0")
+	writeLine(sb, "PRODID:-//cappuccinotm//trn//EN")
+	writeLine(sb, "METHOD:REPLY")
+	f.writeVFreeBusy(sb)
+	writeLine(sb, "END:VCALENDAR")
+	return sb.String()
+}
+
+func (f FreeBusyReply) writeVFreeBusy(sb *strings.Builder) {
+	writeLine(sb, "BEGIN:VFREEBUSY")
+	if f.UID != "" {
+		writeLine(sb, "UID:"+escapeText(f.UID))
+	}
+	writeLine(sb, "DTSTAMP:"+f.Stamp.UTC().Format(icalTimeFmt))
+	if f.Organizer != "" {
+		writeLine(sb, "ORGANIZER:"+f.Organizer)
+	}
+	if f.Attendee != "" {
+		writeLine(sb, "ATTENDEE:"+f.Attendee)
+	}
+	writeLine(sb, "DTSTART:"+f.Window.Start().UTC().Format(icalTimeFmt))
+	writeLine(sb, "DTEND:"+f.Window.End().UTC().Format(icalTimeFmt))
+	for _, r := range f.Window.TruncateAll(f.Busy) {
+		writeLine(sb, "FREEBUSY;FBTYPE=BUSY:"+formatPeriod(r))
+	}
+	for _, r := range f.Window.TruncateAll(f.Tentative) {
+		writeLine(sb, "FREEBUSY;FBTYPE=BUSY-TENTATIVE:"+formatPeriod(r))
+	}
+	writeLine(sb, "END:VFREEBUSY")
+}
+
+func formatPeriod(r trn.Range) string {
+	return r.Start().UTC().Format(icalTimeFmt) + "/" + r.End().UTC().Format(icalTimeFmt)
+}
+
+// writeLine writes the content line, folded to the lines of at most 75
+// octets, as RFC 5545 requires.
+func writeLine(sb *strings.Builder, line string) {
+	const limit = 75
+	for first := true; ; first = false {
+		n := limit
+		if !first {
+			sb.WriteByte(' ')
+			n-- // the leading space counts too
+		}
+
+		if len(line) <= n {
+			sb.WriteString(line)
+			sb.WriteString("\r\n")
+			return
+		}
+
+		// don't split multi-byte characters
+		for n > 0 && line[n]&0xC0 == 0x80 {
+			n--
+		}
+		sb.WriteString(line[:n])
+		sb.WriteString("\r\n")
+		line = line[n:]
+	}
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+func escapeText(s string) string { return textEscaper.Replace(s) }
@@ -0,0 +1,66 @@
+package converters
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cappuccinotm/trn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFreeBusyReply(t *testing.T) {
+	loc := time.FixedZone("", 2*60*60)
+	f := FreeBusyReply{
+		UID:       "reply;1",
+		Organizer: "mailto:jane@example.com",
+		Attendee:  "mailto:john@example.com",
+		Stamp:     dt.Add(-time.Hour),
+		Window:    trn.MustRange(trn.Between(dt, dt.Add(8*time.Hour))),
+		Busy: []trn.Range{
+			trn.MustRange(trn.Between(dt.Add(-time.Hour), dt.Add(time.Hour))),
+			trn.MustRange(trn.Between(dt.Add(3*time.Hour), dt.Add(4*time.Hour))).In(loc),
+			trn.MustRange(trn.Between(dt.Add(3*time.Hour+30*time.Minute), dt.Add(5*time.Hour))),
+			trn.MustRange(trn.Between(dt.Add(9*time.Hour), dt.Add(10*time.Hour))),
+		},
+		Tentative: []trn.Range{trn.MustRange(trn.Between(dt.Add(6*time.Hour), dt.Add(7*time.Hour)))},
+	}
+
+	assert.Equal(t, strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//cappuccinotm//trn//EN",
+		"METHOD:REPLY",
+		"BEGIN:VFREEBUSY",
+		`UID:reply\;1`,
+		"DTSTAMP:20210612T120000Z",
+		"ORGANIZER:mailto:jane@example.com",
+		"ATTENDEE:mailto:john@example.com",
+		"DTSTART:20210612T130000Z",
+		"DTEND:20210612T210000Z",
+		"FREEBUSY;FBTYPE=BUSY:20210612T130000Z/20210612T140000Z",
+		"FREEBUSY;FBTYPE=BUSY:20210612T160000Z/20210612T180000Z",
+		"FREEBUSY;FBTYPE=BUSY-TENTATIVE:20210612T190000Z/20210612T200000Z",
+		"END:VFREEBUSY",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n"), f.ICalendar())
+
+	assert.True(t, strings.HasPrefix(f.VFreeBusy(), "BEGIN:VFREEBUSY\r\n"))
+}
+
+func TestWriteLine(t *testing.T) {
+	sb := &strings.Builder{}
+	writeLine(sb, "ATTENDEE:mailto:"+strings.Repeat("a", 70)+"ü"+strings.Repeat("b", 80))
+	lines := strings.Split(strings.TrimSuffix(sb.String(), "\r\n"), "\r\n")
+	assert.Len(t, lines, 3)
+	for _, l := range lines {
+		assert.LessOrEqual(t, len(l), 75)
+	}
+	for _, l := range lines[1:] {
+		assert.True(t, strings.HasPrefix(l, " "))
+	}
+
+	unfolded := strings.ReplaceAll(sb.String(), "\r\n ", "")
+	assert.Equal(t, "ATTENDEE:mailto:"+strings.Repeat("a", 70)+"ü"+strings.Repeat("b", 80)+"\r\n", unfolded)
+}
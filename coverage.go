@@ -0,0 +1,102 @@
+package trn
+
+import (
+	"sort"
+	"time"
+)
+
+// Deficit describes the range, where the coverage is lower than required.
+type Deficit struct {
+	Range
+	// Missing is the number of ranges lacking to satisfy the requirement.
+	Missing int
+}
+
+// UnderCovered returns the ranges within the period, where less than
+// required members are available at the same time, along with the number
+// of missing members.
+// Each element of available is the availability set of a single member,
+// overlapping ranges of the same member are counted once.
+// Adjacent ranges with the same deficit are merged into one.
+func UnderCovered(period Range, required int, available [][]Range) []Deficit {
+	var ranges []Range
+	for _, member := range available {
+		ranges = append(ranges, MergeOverlappingRanges(member)...)
+	}
+
+	var res []Deficit
+	for _, seg := range coverage(period, ranges) {
+		if seg.cnt >= required {
+			continue
+		}
+
+		// merge with the previous deficit, if they're adjacent and equal
+		if len(res) > 0 && res[len(res)-1].Missing == required-seg.cnt &&
+			res[len(res)-1].End().Equal(seg.rng.st) {
+			res[len(res)-1].dur += seg.rng.dur
+			continue
+		}
+
+		res = append(res, Deficit{Range: seg.rng, Missing: required - seg.cnt})
+	}
+
+	return res
+}
+
+// coverageSegment is a part of the period with the number of ranges,
+// that overlap it.
+type coverageSegment struct {
+	rng Range
+	cnt int
+}
+
+type coverageEvent struct {
+	tm    time.Time
+	delta int
+}
+
+// coverage splits the period into consecutive segments, each one with the
+// constant number of ranges overlapping it.
+func coverage(period Range, ranges []Range) []coverageSegment {
+	if period.dur <= 0 {
+		return nil
+	}
+
+	events := make([]coverageEvent, 0, len(ranges)*2)
+	for _, rng := range ranges {
+		rng = rng.Truncate(period)
+		if rng.dur <= 0 {
+			continue
+		}
+		events = append(events,
+			coverageEvent{tm: rng.st, delta: 1},
+			coverageEvent{tm: rng.End(), delta: -1},
+		)
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].tm.Before(events[j].tm) })
+
+	var res []coverageSegment
+	add := func(st, end time.Time, cnt int) {
+		if !end.After(st) {
+			return
+		}
+		if len(res) > 0 && res[len(res)-1].cnt == cnt {
+			res[len(res)-1].rng.dur += end.Sub(st)
+			return
+		}
+		res = append(res, coverageSegment{rng: Range{st: st, dur: end.Sub(st)}, cnt: cnt})
+	}
+
+	cur, cnt := period.st, 0
+	for _, evt := range events {
+		add(cur, evt.tm, cnt)
+		if evt.tm.After(cur) {
+			cur = evt.tm
+		}
+		cnt += evt.delta
+	}
+	add(cur, period.End(), cnt)
+
+	return res
+}
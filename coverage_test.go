@@ -0,0 +1,67 @@
+package trn
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnderCovered(t *testing.T) {
+	period := MustRange(Between(tm(9, 0), tm(18, 0)))
+
+	tests := []struct {
+		name      string
+		required  int
+		available [][]Range
+		want      []Deficit
+	}{
+		{
+			name:     "nobody available",
+			required: 2,
+			want:     []Deficit{{Range: period, Missing: 2}},
+		},
+		{
+			name:     "fully covered",
+			required: 2,
+			available: [][]Range{
+				{MustRange(Between(tm(8, 0), tm(18, 0)))},
+				{MustRange(Between(tm(9, 0), tm(13, 0))), MustRange(Between(tm(13, 0), tm(19, 0)))},
+			},
+		},
+		{
+			name:     "gaps and overlaps of a single member",
+			required: 2,
+			available: [][]Range{
+				{MustRange(Between(tm(9, 0), tm(14, 0)))},
+				{
+					MustRange(Between(tm(10, 0), tm(12, 0))),
+					MustRange(Between(tm(11, 0), tm(13, 0))), // overlaps the previous one
+					MustRange(Between(tm(15, 0), tm(18, 0))),
+				},
+				{MustRange(Between(tm(16, 0), tm(17, 0)))},
+			},
+			want: []Deficit{
+				{Range: MustRange(Between(tm(9, 0), tm(10, 0))), Missing: 1},
+				{Range: MustRange(Between(tm(13, 0), tm(14, 0))), Missing: 1},
+				{Range: MustRange(Between(tm(14, 0), tm(15, 0))), Missing: 2},
+				{Range: MustRange(Between(tm(15, 0), tm(16, 0))), Missing: 1},
+				{Range: MustRange(Between(tm(17, 0), tm(18, 0))), Missing: 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := UnderCovered(period, tt.required, tt.available)
+			assert.Equal(t, formattedDeficits(tt.want), formattedDeficits(got))
+		})
+	}
+}
+
+func formattedDeficits(deficits []Deficit) []string {
+	res := make([]string, len(deficits))
+	for i, d := range deficits {
+		res[i] = d.Format("15:04") + " " + strconv.Itoa(d.Missing)
+	}
+	return res
+}
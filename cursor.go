@@ -0,0 +1,88 @@
+package trn
+
+import (
+	"sort"
+	"time"
+)
+
+// Cursor navigates over the timeline of ranges, e.g. to find the next
+// available day in UI. The ranges are merged beforehand, so each one is
+// followed by the gap. The cursor starts before the first range.
+type Cursor struct {
+	rngs []Range
+	idx  int
+}
+
+// NewCursor makes a new cursor over the ranges, which may be unsorted and
+// overlapping.
+func NewCursor(ranges []Range) *Cursor { return &Cursor{rngs: normalize(ranges), idx: -1} }
+
+// Current returns the range under the cursor, false if the cursor is
+// before the first or after the last range.
+func (c *Cursor) Current() (Range, bool) {
+	if c.idx < 0 || c.idx >= len(c.rngs) {
+		return Range{}, false
+	}
+	return c.rngs[c.idx], true
+}
+
+// Next moves the cursor to the next range, false if there is none.
+func (c *Cursor) Next() bool {
+	if c.idx < len(c.rngs) {
+		c.idx++
+	}
+	return c.idx < len(c.rngs)
+}
+
+// Prev moves the cursor to the previous range, false if there is none.
+func (c *Cursor) Prev() bool {
+	if c.idx >= 0 {
+		c.idx--
+	}
+	return c.idx >= 0
+}
+
+// Seek moves the cursor to the first range, which ends after t, i.e. the
+// one containing t or the next one. Returns false and moves the cursor
+// after the last range if there is none.
+func (c *Cursor) Seek(t time.Time) bool {
+	c.idx = sort.Search(len(c.rngs), func(i int) bool { return c.rngs[i].End().After(t) })
+	return c.idx < len(c.rngs)
+}
+
+// NextTransition returns the closest boundary of the ranges after t, where
+// the timeline switches from the gap to the range or vice versa, and moves
+// the cursor to its range. Returns false if there are no boundaries after t.
+func (c *Cursor) NextTransition(t time.Time) (time.Time, bool) {
+	if !c.Seek(t) {
+		return time.Time{}, false
+	}
+
+	rng := c.rngs[c.idx]
+	if rng.st.After(t) {
+		return rng.st, true
+	}
+	return rng.End(), true
+}
+
+// NextFree returns the earliest time not before t, which is not covered by
+// the ranges, and moves the cursor to the range covering t, if any.
+func (c *Cursor) NextFree(t time.Time) time.Time {
+	if c.Seek(t) && !c.rngs[c.idx].st.After(t) {
+		return c.rngs[c.idx].End()
+	}
+	return t
+}
+
+// NextBusy returns the earliest time not before t, which is covered by the
+// ranges, and moves the cursor to its range. Returns false if there is
+// none.
+func (c *Cursor) NextBusy(t time.Time) (time.Time, bool) {
+	if !c.Seek(t) {
+		return time.Time{}, false
+	}
+	if rng := c.rngs[c.idx]; rng.st.After(t) {
+		return rng.st, true
+	}
+	return t, true
+}
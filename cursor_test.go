@@ -0,0 +1,85 @@
+package trn
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor_Navigation(t *testing.T) {
+	c := NewCursor([]Range{
+		MustRange(Between(tm(15, 0), tm(16, 0))),
+		MustRange(Between(tm(9, 0), tm(10, 0))),
+		MustRange(Between(tm(9, 30), tm(11, 0))),
+	})
+
+	_, ok := c.Current()
+	assert.False(t, ok, "starts before the first range")
+	assert.False(t, c.Prev())
+
+	var got []Range
+	for c.Next() {
+		rng, ok := c.Current()
+		assert.True(t, ok)
+		got = append(got, rng)
+	}
+	assert.Equal(t, []Range{
+		MustRange(Between(tm(9, 0), tm(11, 0))),
+		MustRange(Between(tm(15, 0), tm(16, 0))),
+	}, got)
+	assert.False(t, c.Next())
+
+	assert.True(t, c.Prev())
+	rng, _ := c.Current()
+	assert.Equal(t, MustRange(Between(tm(15, 0), tm(16, 0))), rng)
+
+	assert.True(t, c.Seek(tm(10, 0)))
+	rng, _ = c.Current()
+	assert.Equal(t, MustRange(Between(tm(9, 0), tm(11, 0))), rng)
+
+	assert.True(t, c.Seek(tm(11, 0)))
+	rng, _ = c.Current()
+	assert.Equal(t, MustRange(Between(tm(15, 0), tm(16, 0))), rng)
+
+	assert.False(t, c.Seek(tm(16, 0)))
+	_, ok = c.Current()
+	assert.False(t, ok)
+}
+
+func TestCursor_Transitions(t *testing.T) {
+	c := NewCursor([]Range{
+		MustRange(Between(tm(9, 0), tm(11, 0))),
+		MustRange(Between(tm(15, 0), tm(16, 0))),
+	})
+
+	tests := []struct {
+		name       string
+		t          time.Time
+		transition time.Time
+		free       time.Time
+		busy       time.Time
+		ok         bool
+	}{
+		{name: "before all", t: tm(8, 0), transition: tm(9, 0), free: tm(8, 0), busy: tm(9, 0), ok: true},
+		{name: "at start", t: tm(9, 0), transition: tm(11, 0), free: tm(11, 0), busy: tm(9, 0), ok: true},
+		{name: "within", t: tm(10, 0), transition: tm(11, 0), free: tm(11, 0), busy: tm(10, 0), ok: true},
+		{name: "at end", t: tm(11, 0), transition: tm(15, 0), free: tm(11, 0), busy: tm(15, 0), ok: true},
+		{name: "after all", t: tm(16, 0), free: tm(16, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transition, ok := c.NextTransition(tt.t)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.transition, transition)
+
+			assert.Equal(t, tt.free, c.NextFree(tt.t))
+
+			busy, ok := c.NextBusy(tt.t)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.busy, busy)
+		})
+	}
+
+	assert.Equal(t, tm(9, 0), NewCursor(nil).NextFree(tm(9, 0)))
+}
@@ -0,0 +1,89 @@
+package trn
+
+import "time"
+
+// Strategy picks the resource to assign the slot to, given the slots
+// already assigned to each resource.
+type Strategy func(slot Range, resources []string, assigned map[string][]Range) string
+
+// Distribute assigns the slots to the resources in the order of slots,
+// using the given strategy to pick the resource for each slot.
+// Returns nil if there are no resources.
+func Distribute(slots []Range, resources []string, strategy Strategy) map[string][]Range {
+	if len(resources) == 0 {
+		return nil
+	}
+
+	res := make(map[string][]Range, len(resources))
+	for _, slot := range slots {
+		resource := strategy(slot, resources, res)
+		res[resource] = append(res[resource], slot)
+	}
+
+	return res
+}
+
+// RoundRobin returns the strategy, that assigns the slots to the resources
+// in turn. The strategy is stateful, if it is reused, the next call
+// continues from the resource, where the previous one stopped.
+func RoundRobin() Strategy {
+	next := 0
+	return func(_ Range, resources []string, _ map[string][]Range) string {
+		res := resources[next%len(resources)]
+		next++
+		return res
+	}
+}
+
+// LeastLoaded returns the strategy, that assigns the slot to the resource
+// with the least total duration of the already assigned slots. Ties are
+// resolved in favor of the resource, which comes first.
+func LeastLoaded() Strategy {
+	return func(_ Range, resources []string, assigned map[string][]Range) string {
+		res, minLoad := resources[0], load(assigned[resources[0]])
+		for _, resource := range resources[1:] {
+			if l := load(assigned[resource]); l < minLoad {
+				res, minLoad = resource, l
+			}
+		}
+		return res
+	}
+}
+
+// Weighted returns the strategy, that assigns the slots to the resources
+// in proportion to their weights, interleaving the resources smoothly,
+// e.g. for weights {a: 2, b: 1} the order is a, b, a, a, b, a...
+// Resources without weight or with non-positive weight get no slots,
+// unless no resource has a positive weight. The strategy is stateful.
+func Weighted(weights map[string]int) Strategy {
+	current := map[string]int{}
+	return func(_ Range, resources []string, _ map[string][]Range) string {
+		total, best := 0, ""
+		for _, resource := range resources {
+			w := weights[resource]
+			if w <= 0 {
+				continue
+			}
+			total += w
+			current[resource] += w
+			if best == "" || current[resource] > current[best] {
+				best = resource
+			}
+		}
+
+		if best == "" {
+			return resources[0]
+		}
+
+		current[best] -= total
+		return best
+	}
+}
+
+func load(rngs []Range) time.Duration {
+	var res time.Duration
+	for _, rng := range rngs {
+		res += rng.dur
+	}
+	return res
+}
@@ -0,0 +1,49 @@
+package trn
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistribute(t *testing.T) {
+	slots := MustRanges(MustRange(Between(tm(9, 0), tm(15, 0))).Split(time.Hour, 0))
+	resources := []string{"alice", "bob", "carol"}
+
+	t.Run("round robin", func(t *testing.T) {
+		got := Distribute(slots, resources, RoundRobin())
+		assert.Equal(t, map[string][]Range{
+			"alice": {slots[0], slots[3]},
+			"bob":   {slots[1], slots[4]},
+			"carol": {slots[2], slots[5]},
+		}, got)
+	})
+
+	t.Run("least loaded", func(t *testing.T) {
+		long := New(tm(8, 0), 4*time.Hour)
+		got := Distribute(append([]Range{long}, slots...), resources, LeastLoaded())
+		assert.Equal(t, map[string][]Range{
+			"alice": {long},
+			"bob":   {slots[0], slots[2], slots[4]},
+			"carol": {slots[1], slots[3], slots[5]},
+		}, got)
+	})
+
+	t.Run("weighted", func(t *testing.T) {
+		got := Distribute(slots, resources, Weighted(map[string]int{"alice": 2, "bob": 1}))
+		assert.Equal(t, map[string][]Range{
+			"alice": {slots[0], slots[2], slots[3], slots[5]},
+			"bob":   {slots[1], slots[4]},
+		}, got)
+	})
+
+	t.Run("weighted without weights", func(t *testing.T) {
+		got := Distribute(slots[:2], resources, Weighted(nil))
+		assert.Equal(t, map[string][]Range{"alice": slots[:2]}, got)
+	})
+
+	t.Run("no resources", func(t *testing.T) {
+		assert.Nil(t, Distribute(slots, nil, RoundRobin()))
+	})
+}
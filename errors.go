@@ -0,0 +1,93 @@
+package trn
+
+import (
+	"fmt"
+	"time"
+)
+
+// InvalidIntervalError is returned when the duration or the interval of
+// the split is not positive, it matches ErrZeroDurationInterval with
+// errors.Is.
+type InvalidIntervalError struct {
+	Duration time.Duration
+	Interval time.Duration
+}
+
+// Error returns string representation of the error.
+func (e InvalidIntervalError) Error() string {
+	return fmt.Sprintf("%s: duration %s, interval %s", ErrZeroDurationInterval, e.Duration, e.Interval)
+}
+
+// Is returns true if the target is ErrZeroDurationInterval.
+func (e InvalidIntervalError) Is(target error) bool { return target == ErrZeroDurationInterval }
+
+// StartAfterEndError is returned when the start of the range is later than
+// its end, it matches ErrStartAfterEnd with errors.Is.
+type StartAfterEndError struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Error returns string representation of the error.
+func (e StartAfterEndError) Error() string {
+	return fmt.Sprintf("%s: start %s, end %s",
+		ErrStartAfterEnd, e.Start.Format(time.RFC3339Nano), e.End.Format(time.RFC3339Nano))
+}
+
+// Is returns true if the target is ErrStartAfterEnd.
+func (e StartAfterEndError) Is(target error) bool { return target == ErrStartAfterEnd }
+
+// TooManySlotsError is returned when the split would produce more ranges
+// than allowed, it matches ErrTooManySlots with errors.Is. With the
+// forbidden windows Slots is the number of ranges made before the limit
+// was hit, as the total isn't known beforehand.
+type TooManySlotsError struct {
+	Slots int64
+	Max   int
+}
+
+// Error returns string representation of the error.
+func (e TooManySlotsError) Error() string {
+	return fmt.Sprintf("%s: %d slots, at most %d allowed", ErrTooManySlots, e.Slots, e.Max)
+}
+
+// Is returns true if the target is ErrTooManySlots.
+func (e TooManySlotsError) Is(target error) bool { return target == ErrTooManySlots }
+
+// ConflictError is returned when the ranges overlap, while they must not,
+// it matches ErrConflict with errors.Is.
+type ConflictError struct {
+	First  Range
+	Second Range
+}
+
+// Error returns string representation of the error.
+func (e ConflictError) Error() string {
+	return fmt.Sprintf("%s: %s and %s", ErrConflict, e.First.Format(time.RFC3339Nano), e.Second.Format(time.RFC3339Nano))
+}
+
+// Is returns true if the target is ErrConflict.
+func (e ConflictError) Is(target error) bool { return target == ErrConflict }
+
+// BookingError describes why the candidate booking is invalid, it matches
+// the error of the failed check, e.g. ErrNotAvailable, with errors.Is.
+type BookingError struct {
+	// Index is the index of the candidate.
+	Index int
+	Range Range
+	Err   error
+	// Other is the index of the other candidate for ErrConflict and
+	// ErrTooClose, -1 otherwise.
+	Other int
+}
+
+// Error returns string representation of the error.
+func (e BookingError) Error() string {
+	if e.Other >= 0 {
+		return fmt.Sprintf("candidate %d %s: %s with candidate %d", e.Index, e.Range.Format(time.RFC3339Nano), e.Err, e.Other)
+	}
+	return fmt.Sprintf("candidate %d %s: %s", e.Index, e.Range.Format(time.RFC3339Nano), e.Err)
+}
+
+// Unwrap returns the error of the failed check.
+func (e BookingError) Unwrap() error { return e.Err }
@@ -0,0 +1,35 @@
+package trn
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStructuredErrors(t *testing.T) {
+	_, err := Between(tm(14, 0), tm(13, 0))
+	assert.ErrorIs(t, err, ErrStartAfterEnd)
+	var saeErr StartAfterEndError
+	assert.True(t, errors.As(err, &saeErr))
+	assert.Equal(t, StartAfterEndError{Start: tm(14, 0), End: tm(13, 0)}, saeErr)
+	assert.EqualError(t, err,
+		"trn: start time is later than the end: start 2021-06-12T14:00:00Z, end 2021-06-12T13:00:00Z")
+
+	_, err = New(tm(9, 0), time.Hour).Split(10*time.Minute, -10*time.Minute)
+	assert.ErrorIs(t, err, ErrZeroDurationInterval)
+	var iiErr InvalidIntervalError
+	assert.True(t, errors.As(err, &iiErr))
+	assert.Equal(t, InvalidIntervalError{Duration: 10 * time.Minute, Interval: -10 * time.Minute}, iiErr)
+	assert.EqualError(t, err, "trn: cannot split with zero duration or interval: duration 10m0s, interval -10m0s")
+
+	_, err = New(tm(9, 0), time.Hour).Stratify(time.Minute, time.Minute, MaxSlots(10))
+	assert.ErrorIs(t, err, ErrTooManySlots)
+	var tmsErr TooManySlotsError
+	assert.True(t, errors.As(err, &tmsErr))
+	assert.Equal(t, TooManySlotsError{Slots: 60, Max: 10}, tmsErr)
+	assert.EqualError(t, err, "trn: too many slots in the result: 60 slots, at most 10 allowed")
+
+	assert.NotErrorIs(t, err, ErrStartAfterEnd)
+}
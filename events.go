@@ -0,0 +1,75 @@
+package trn
+
+import (
+	"sort"
+	"time"
+)
+
+// Sessionize groups the point events into sessions, where the gap between
+// the consecutive events does not exceed maxGap, e.g. to derive user
+// sessions from clicks or device uptime from heartbeats.
+// Each session starts at its first event and ends at its last one, so the
+// session of a single event has zero duration.
+// Events may be unsorted. The resulting ranges are sorted.
+func Sessionize(events []time.Time, maxGap time.Duration) []Range {
+	if len(events) == 0 {
+		return nil
+	}
+
+	sorted := make([]time.Time, len(events))
+	copy(sorted, events)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+
+	var res []Range
+	st, last := sorted[0], sorted[0]
+	for _, evt := range sorted[1:] {
+		if evt.Sub(last) > maxGap {
+			res = append(res, Range{st: st, dur: last.Sub(st)})
+			st = evt
+		}
+		last = evt
+	}
+
+	return append(res, Range{st: st, dur: last.Sub(st)})
+}
+
+// Transition is a change of some state at the given time.
+type Transition struct {
+	At time.Time
+	On bool
+}
+
+// FromTransitions converts the log of state changes into the ranges, during
+// which the state was on, e.g. to compute uptime from the status changes.
+// Transitions may be unsorted, the ones at the same time are applied in
+// the order of the log. Duplicate transitions, i.e. turning on the state,
+// which is already on, are ignored.
+// The state, which is on at the end of the log, is not returned, append
+// the closing transition at the desired time to get it.
+// The resulting ranges are merged and sorted.
+func FromTransitions(transitions []Transition) []Range {
+	sorted := make([]Transition, len(transitions))
+	copy(sorted, transitions)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].At.Before(sorted[j].At) })
+
+	var (
+		res []Range
+		on  bool
+		st  time.Time
+	)
+	for _, tr := range sorted {
+		switch {
+		case tr.On && !on:
+			st = tr.At
+		case !tr.On && on && tr.At.After(st):
+			res = append(res, Range{st: st, dur: tr.At.Sub(st)})
+		}
+		on = tr.On
+	}
+
+	if len(res) == 0 {
+		return nil
+	}
+
+	return MergeOverlappingRanges(res)
+}
@@ -0,0 +1,98 @@
+package trn
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionize(t *testing.T) {
+	tests := []struct {
+		name   string
+		events []time.Time
+		maxGap time.Duration
+		want   []Range
+	}{
+		{name: "no events", maxGap: time.Minute},
+		{
+			name:   "single event",
+			events: []time.Time{tm(13, 0)},
+			maxGap: time.Minute,
+			want:   []Range{New(tm(13, 0), 0)},
+		},
+		{
+			name: "unsorted events",
+			events: []time.Time{
+				tm(13, 10), tm(13, 0), tm(13, 5), // first session
+				tm(14, 0),                       // standalone
+				tm(15, 0), tm(15, 5), tm(15, 5), // the gap is exactly maxGap
+			},
+			maxGap: 5 * time.Minute,
+			want: []Range{
+				MustRange(Between(tm(13, 0), tm(13, 10))),
+				New(tm(14, 0), 0),
+				MustRange(Between(tm(15, 0), tm(15, 5))),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Sessionize(tt.events, tt.maxGap)
+			assert.Equal(t, formattedRanges(tt.want, "15:04"), formattedRanges(got, "15:04"))
+		})
+	}
+}
+
+func TestFromTransitions(t *testing.T) {
+	tests := []struct {
+		name string
+		args []Transition
+		want []Range
+	}{
+		{name: "empty log"},
+		{
+			name: "unordered with duplicates",
+			args: []Transition{
+				{At: tm(15, 0), On: false},
+				{At: tm(13, 0), On: true},
+				{At: tm(13, 30), On: true}, // duplicate
+				{At: tm(14, 0), On: false},
+				{At: tm(14, 30), On: false}, // duplicate
+				{At: tm(14, 45), On: true},
+			},
+			want: []Range{
+				MustRange(Between(tm(13, 0), tm(14, 0))),
+				MustRange(Between(tm(14, 45), tm(15, 0))),
+			},
+		},
+		{
+			name: "leading off and trailing on",
+			args: []Transition{
+				{At: tm(12, 0), On: false},
+				{At: tm(13, 0), On: true},
+				{At: tm(14, 0), On: false},
+				{At: tm(16, 0), On: true},
+			},
+			want: []Range{MustRange(Between(tm(13, 0), tm(14, 0)))},
+		},
+		{
+			name: "adjacent and instant ranges",
+			args: []Transition{
+				{At: tm(13, 0), On: true},
+				{At: tm(14, 0), On: false},
+				{At: tm(14, 0), On: true},
+				{At: tm(15, 0), On: false},
+				{At: tm(16, 0), On: true},
+				{At: tm(16, 0), On: false},
+			},
+			want: []Range{MustRange(Between(tm(13, 0), tm(15, 0)))},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FromTransitions(tt.args)
+			assert.Equal(t, formattedRanges(tt.want, "15:04"), formattedRanges(got, "15:04"))
+		})
+	}
+}
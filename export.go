@@ -0,0 +1,79 @@
+package trn
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// AvailabilitySlot is the slot of the resource with its availability flag,
+// which is marshaled to JSON in the shape of typical availability APIs:
+//
+//	{"start":"...","end":"...","available":true,"resource":"r1"}
+//
+// The boundaries are RFC 9557 timestamps, the resource is omitted if
+// empty.
+type AvailabilitySlot struct {
+	Range     Range
+	Available bool
+	Resource  string
+}
+
+type jsonAvailabilitySlot struct {
+	Start     string `json:"start"`
+	End       string `json:"end"`
+	Available bool   `json:"available"`
+	Resource  string `json:"resource,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s AvailabilitySlot) MarshalJSON() ([]byte, error) {
+	r := s.Range.applyPrecision()
+	return json.Marshal(jsonAvailabilitySlot{
+		Start:     FormatRFC9557(r.st),
+		End:       FormatRFC9557(r.End()),
+		Available: s.Available,
+		Resource:  s.Resource,
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *AvailabilitySlot) UnmarshalJSON(b []byte) error {
+	var js jsonAvailabilitySlot
+	if err := decodeJSON(b, &js); err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformedRange, err)
+	}
+	if err := s.Range.parse(js.Start, js.End); err != nil {
+		return err
+	}
+	s.Available, s.Resource = js.Available, js.Resource
+	return nil
+}
+
+// ExportSlots converts the slots of the resource and their availability
+// flags, e.g. the ones computed for the slots generated by Stratify, into
+// the availability slots, merging the adjacent slots with the same flag.
+// Slots must be sorted, flags are matched with the slots by index, the
+// missing ones are considered false.
+func ExportSlots(resource string, slots []Range, available []bool) []AvailabilitySlot {
+	var res []AvailabilitySlot
+	for i, slot := range slots {
+		free := i < len(available) && available[i]
+		if n := len(res); n > 0 && res[n-1].Available == free && res[n-1].Range.End().Equal(slot.st) {
+			res[n-1].Range.dur += slot.dur
+			continue
+		}
+		res = append(res, AvailabilitySlot{Range: slot, Available: free, Resource: resource})
+	}
+	return res
+}
+
+// Export converts the matrix into the availability slots of all
+// resources, in the order of resources, merging the adjacent slots of the
+// resource with the same availability.
+func (m AvailabilityMatrix) Export() []AvailabilitySlot {
+	var res []AvailabilitySlot
+	for i, name := range m.Resources {
+		res = append(res, ExportSlots(name, m.Slots, m.Free[i])...)
+	}
+	return res
+}
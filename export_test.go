@@ -0,0 +1,59 @@
+package trn
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportSlots(t *testing.T) {
+	slots := MustRanges(MustRange(Between(tm(9, 0), tm(12, 0))).Stratify(30*time.Minute, 30*time.Minute))
+	got := ExportSlots("r1", slots, []bool{true, true, false, true, true})
+
+	b, err := json.Marshal(got)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"start":"2021-06-12T09:00:00Z","end":"2021-06-12T10:00:00Z","available":true,"resource":"r1"},
+		{"start":"2021-06-12T10:00:00Z","end":"2021-06-12T10:30:00Z","available":false,"resource":"r1"},
+		{"start":"2021-06-12T10:30:00Z","end":"2021-06-12T11:30:00Z","available":true,"resource":"r1"},
+		{"start":"2021-06-12T11:30:00Z","end":"2021-06-12T12:00:00Z","available":false,"resource":"r1"}
+	]`, string(b))
+
+	var decoded []AvailabilitySlot
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, got, decoded)
+
+	// gaps between the slots are not merged
+	got = ExportSlots("", []Range{New(tm(9, 0), time.Hour), New(tm(11, 0), time.Hour)}, []bool{true, true})
+	b, err = json.Marshal(got)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"start":"2021-06-12T09:00:00Z","end":"2021-06-12T10:00:00Z","available":true},
+		{"start":"2021-06-12T11:00:00Z","end":"2021-06-12T12:00:00Z","available":true}
+	]`, string(b))
+
+	assert.Empty(t, ExportSlots("r1", nil, nil))
+	assert.Error(t, json.Unmarshal([]byte(`{"start":"2021","available":true}`), &AvailabilitySlot{}))
+}
+
+func TestAvailabilityMatrix_Export(t *testing.T) {
+	m, err := Matrix(MustRange(Between(tm(9, 0), tm(12, 0))), time.Hour, map[string][]Range{
+		"r2": {MustRange(Between(tm(10, 30), tm(11, 0)))},
+		"r1": nil,
+	})
+	require.NoError(t, err)
+
+	var got []string
+	for _, s := range m.Export() {
+		got = append(got, s.Resource+" "+s.Range.Format("15:04")+" "+map[bool]string{true: "free", false: "busy"}[s.Available])
+	}
+	assert.Equal(t, []string{
+		"r1 [09:00, 12:00] free",
+		"r2 [09:00, 10:00] free",
+		"r2 [10:00, 11:00] busy",
+		"r2 [11:00, 12:00] free",
+	}, got)
+}
@@ -0,0 +1,99 @@
+package trn
+
+import "time"
+
+// EarliestFit returns the earliest range of the given duration within the
+// free ranges, which starts not before the given time.
+// Free ranges may be unsorted and overlapping, they are merged beforehand.
+// Returns false if there is no such range or the duration is not positive.
+func EarliestFit(free []Range, duration time.Duration, after time.Time) (Range, bool) {
+	if duration <= 0 {
+		return Range{}, false
+	}
+
+	for _, rng := range MergeOverlappingRanges(free) {
+		st := rng.st
+		if st.Before(after) {
+			st = after
+		}
+
+		if rng.End().Sub(st) >= duration {
+			return Range{st: st, dur: duration}, true
+		}
+	}
+
+	return Range{}, false
+}
+
+// BestFit places the range of the given duration at the start of the
+// smallest free range, which fits it, to minimize fragmentation.
+// Returns the placement and the free ranges left after it, merged and
+// sorted. Free ranges may be unsorted and overlapping.
+// Returns false if there is no such range or the duration is not positive.
+func BestFit(free []Range, duration time.Duration) (placed Range, leftover []Range, ok bool) {
+	return fit(free, duration, func(candidate, chosen Range) bool { return candidate.dur < chosen.dur })
+}
+
+// WorstFit places the range of the given duration at the start of the
+// largest free range to keep the leftover fragments as large as possible.
+// Returns the placement and the free ranges left after it, merged and
+// sorted. Free ranges may be unsorted and overlapping.
+// Returns false if there is no such range or the duration is not positive.
+func WorstFit(free []Range, duration time.Duration) (placed Range, leftover []Range, ok bool) {
+	return fit(free, duration, func(candidate, chosen Range) bool { return candidate.dur > chosen.dur })
+}
+
+// fit places the range at the start of the free range, which is better
+// than others according to the given function.
+func fit(free []Range, duration time.Duration, better func(candidate, chosen Range) bool) (Range, []Range, bool) {
+	if duration <= 0 {
+		return Range{}, nil, false
+	}
+
+	merged := MergeOverlappingRanges(free)
+
+	idx := -1
+	for i, rng := range merged {
+		if rng.dur < duration {
+			continue
+		}
+		if idx < 0 || better(rng, merged[idx]) {
+			idx = i
+		}
+	}
+
+	if idx < 0 {
+		return Range{}, nil, false
+	}
+
+	placed := Range{st: merged[idx].st, dur: duration}
+
+	leftover := make([]Range, 0, len(merged))
+	leftover = append(leftover, merged[:idx]...)
+	if rest := merged[idx].dur - duration; rest > 0 {
+		leftover = append(leftover, Range{st: placed.End(), dur: rest})
+	}
+	leftover = append(leftover, merged[idx+1:]...)
+
+	return placed, leftover, true
+}
+
+// Fragmentation measures how chopped-up the free ranges are for the slots
+// of the given duration: it returns the number of slots, which could be
+// placed back-to-back into the free ranges, and the total free time, which
+// is too short to hold a slot.
+// Free ranges may be unsorted and overlapping, they are merged beforehand.
+// If slot is not positive, the whole free time is considered wasted.
+func Fragmentation(free []Range, slot time.Duration) (usableSlots int, wastedTime time.Duration) {
+	for _, rng := range MergeOverlappingRanges(free) {
+		if slot <= 0 {
+			wastedTime += rng.dur
+			continue
+		}
+
+		n := rng.dur / slot
+		usableSlots += int(n)
+		wastedTime += rng.dur - n*slot
+	}
+	return usableSlots, wastedTime
+}
@@ -0,0 +1,115 @@
+package trn
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEarliestFit(t *testing.T) {
+	free := []Range{
+		MustRange(Between(tm(15, 0), tm(18, 0))),
+		MustRange(Between(tm(9, 0), tm(9, 30))),
+		MustRange(Between(tm(10, 0), tm(11, 0))),
+		MustRange(Between(tm(10, 30), tm(12, 0))), // overlaps the previous one
+	}
+
+	tests := []struct {
+		name     string
+		duration time.Duration
+		after    time.Time
+		want     Range
+		wantOk   bool
+	}{
+		{name: "first range fits", duration: 30 * time.Minute, after: tm(0, 0),
+			want: MustRange(Between(tm(9, 0), tm(9, 30))), wantOk: true},
+		{name: "merged range fits", duration: 2 * time.Hour, after: tm(0, 0),
+			want: MustRange(Between(tm(10, 0), tm(12, 0))), wantOk: true},
+		{name: "after within the range", duration: time.Hour, after: tm(10, 45),
+			want: MustRange(Between(tm(10, 45), tm(11, 45))), wantOk: true},
+		{name: "after cuts the range too short", duration: time.Hour, after: tm(11, 15),
+			want: MustRange(Between(tm(15, 0), tm(16, 0))), wantOk: true},
+		{name: "too long", duration: 4 * time.Hour, after: tm(0, 0)},
+		{name: "after all ranges", duration: time.Minute, after: tm(18, 0)},
+		{name: "zero duration", duration: 0, after: tm(0, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := EarliestFit(free, tt.duration, tt.after)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, formattedRange{rng: tt.want, fmt: "15:04"}, formattedRange{rng: got, fmt: "15:04"})
+		})
+	}
+}
+
+func TestBestFit(t *testing.T) {
+	free := []Range{
+		MustRange(Between(tm(15, 0), tm(18, 0))),
+		MustRange(Between(tm(9, 0), tm(9, 30))),
+		MustRange(Between(tm(10, 0), tm(11, 0))),
+		MustRange(Between(tm(12, 0), tm(13, 0))),
+	}
+
+	placed, leftover, ok := BestFit(free, 45*time.Minute)
+	assert.True(t, ok)
+	assert.Equal(t, "[10:00, 10:45]", placed.Format("15:04"))
+	assert.Equal(t, formattedRanges([]Range{
+		MustRange(Between(tm(9, 0), tm(9, 30))),
+		MustRange(Between(tm(10, 45), tm(11, 0))),
+		MustRange(Between(tm(12, 0), tm(13, 0))),
+		MustRange(Between(tm(15, 0), tm(18, 0))),
+	}, "15:04"), formattedRanges(leftover, "15:04"))
+
+	placed, leftover, ok = BestFit(free, 30*time.Minute)
+	assert.True(t, ok)
+	assert.Equal(t, "[09:00, 09:30]", placed.Format("15:04"))
+	assert.Len(t, leftover, 3, "exactly fitting range must be consumed")
+
+	_, _, ok = BestFit(free, 4*time.Hour)
+	assert.False(t, ok)
+
+	_, _, ok = BestFit(free, 0)
+	assert.False(t, ok)
+}
+
+func TestWorstFit(t *testing.T) {
+	free := []Range{
+		MustRange(Between(tm(9, 0), tm(9, 30))),
+		MustRange(Between(tm(10, 0), tm(11, 0))),
+		MustRange(Between(tm(15, 0), tm(18, 0))),
+	}
+
+	placed, leftover, ok := WorstFit(free, 45*time.Minute)
+	assert.True(t, ok)
+	assert.Equal(t, "[15:00, 15:45]", placed.Format("15:04"))
+	assert.Equal(t, formattedRanges([]Range{
+		MustRange(Between(tm(9, 0), tm(9, 30))),
+		MustRange(Between(tm(10, 0), tm(11, 0))),
+		MustRange(Between(tm(15, 45), tm(18, 0))),
+	}, "15:04"), formattedRanges(leftover, "15:04"))
+
+	_, _, ok = WorstFit(nil, time.Minute)
+	assert.False(t, ok)
+}
+
+func TestFragmentation(t *testing.T) {
+	free := []Range{
+		MustRange(Between(tm(9, 0), tm(9, 20))),
+		MustRange(Between(tm(10, 0), tm(11, 10))),
+		MustRange(Between(tm(11, 0), tm(11, 40))), // overlaps the previous one
+		MustRange(Between(tm(15, 0), tm(16, 0))),
+	}
+
+	slots, wasted := Fragmentation(free, 30*time.Minute)
+	assert.Equal(t, 5, slots)
+	assert.Equal(t, 30*time.Minute, wasted)
+
+	slots, wasted = Fragmentation(free, 0)
+	assert.Equal(t, 0, slots)
+	assert.Equal(t, 3*time.Hour, wasted)
+
+	slots, wasted = Fragmentation(nil, time.Minute)
+	assert.Equal(t, 0, slots)
+	assert.Equal(t, time.Duration(0), wasted)
+}
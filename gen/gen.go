@@ -0,0 +1,175 @@
+// Package gen generates reproducible synthetic workloads of trn ranges,
+// e.g. for benchmarking and load testing of schedulers built on trn.
+package gen
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/cappuccinotm/trn"
+	"github.com/cappuccinotm/trn/store"
+)
+
+// maxAttempts limits the attempts to place the booking, which doesn't
+// overlap the existing ones.
+const maxAttempts = 10
+
+// Workload describes the synthetic workload of resources with working
+// hours and random bookings within them. The same workload always
+// generates the same resources.
+type Workload struct {
+	// Seed is the seed of the random source.
+	Seed int64
+	// Resources is the number of resources, 1 by default.
+	Resources int
+	// Period is the period of the workload.
+	Period trn.Range
+	// Hours are the working hours of each resource, Mo-Fr 09:00-17:00 by
+	// default.
+	Hours store.WeeklySchedule
+	// Location is the location of the working hours, UTC by default.
+	Location *time.Location
+	// BookingsPerDay is the number of bookings attempted for each working
+	// range of the resource, 4 by default.
+	BookingsPerDay int
+	// MinBooking and MaxBooking limit the duration of bookings, from 30
+	// minutes to 2 hours by default.
+	MinBooking, MaxBooking time.Duration
+	// Granularity is the step of the starts and durations of bookings,
+	// 15 minutes by default.
+	Granularity time.Duration
+}
+
+// Resource is the generated resource.
+type Resource struct {
+	ID string
+	// Hours are the working hours of the resource within the period.
+	Hours []trn.Range
+	// Bookings are sorted and don't overlap each other.
+	Bookings []trn.Range
+	// Free is the working time, which is not booked.
+	Free trn.Set
+}
+
+// Generate generates the resources of the workload.
+func (w Workload) Generate() []Resource {
+	w = w.withDefaults()
+	rnd := rand.New(rand.NewSource(w.Seed)) //nolint:gosec // not for security purposes
+
+	hours := w.Hours.Expand(w.Period, w.Location)
+
+	res := make([]Resource, w.Resources)
+	for i := range res {
+		res[i] = Resource{ID: fmt.Sprintf("resource-%04d", i), Hours: hours}
+
+		for _, h := range hours {
+			res[i].Bookings = append(res[i].Bookings, w.book(rnd, h)...)
+		}
+
+		res[i].Free = trn.NewSet(hours...)
+		res[i].Free.Remove(res[i].Bookings...)
+	}
+
+	return res
+}
+
+// book returns the random sorted bookings within the working range.
+func (w Workload) book(rnd *rand.Rand, h trn.Range) []trn.Range {
+	var res []trn.Range
+
+	steps := int64(h.Duration() / w.Granularity)
+	minSteps, maxSteps := int64(w.MinBooking/w.Granularity), int64(w.MaxBooking/w.Granularity)
+	if minSteps < 1 {
+		minSteps = 1
+	}
+
+	for b := 0; b < w.BookingsPerDay; b++ {
+		for attempt := 0; attempt < maxAttempts; attempt++ {
+			dur := minSteps
+			if maxSteps > minSteps {
+				dur += rnd.Int63n(maxSteps - minSteps + 1)
+			}
+			if dur > steps {
+				break
+			}
+
+			st := rnd.Int63n(steps - dur + 1)
+			rng := trn.New(h.Start().Add(time.Duration(st)*w.Granularity), time.Duration(dur)*w.Granularity)
+			if !overlapsAny(rng, res) {
+				res = append(res, rng)
+				break
+			}
+		}
+	}
+
+	trn.SortRanges(res)
+	return res
+}
+
+func (w Workload) withDefaults() Workload {
+	if w.Resources <= 0 {
+		w.Resources = 1
+	}
+	if w.Hours.Empty() {
+		workday := []store.TimeRange{{Start: store.NewClock(9, 0, 0), End: store.NewClock(17, 0, 0)}}
+		for d := time.Monday; d <= time.Friday; d++ {
+			w.Hours[d] = workday
+		}
+	}
+	if w.Location == nil {
+		w.Location = time.UTC
+	}
+	if w.BookingsPerDay <= 0 {
+		w.BookingsPerDay = 4
+	}
+	if w.MinBooking <= 0 {
+		w.MinBooking = 30 * time.Minute
+	}
+	if w.MaxBooking < w.MinBooking {
+		w.MaxBooking = 2 * time.Hour
+		if w.MaxBooking < w.MinBooking {
+			w.MaxBooking = w.MinBooking
+		}
+	}
+	if w.Granularity <= 0 {
+		w.Granularity = 15 * time.Minute
+	}
+	return w
+}
+
+// Ranges returns n random ranges within the period, which may be unsorted
+// and overlap each other, with durations up to maxDur, e.g. to benchmark
+// merges. The same seed always gives the same ranges.
+func Ranges(seed int64, n int, period trn.Range, maxDur time.Duration) []trn.Range {
+	rnd := rand.New(rand.NewSource(seed)) //nolint:gosec // not for security purposes
+
+	if maxDur > period.Duration() {
+		maxDur = period.Duration()
+	}
+
+	res := make([]trn.Range, n)
+	for i := range res {
+		dur := time.Duration(0)
+		if maxDur > 0 {
+			dur = time.Duration(rnd.Int63n(int64(maxDur)) + 1)
+		}
+
+		offset := time.Duration(0)
+		if span := period.Duration() - dur; span > 0 {
+			offset = time.Duration(rnd.Int63n(int64(span) + 1))
+		}
+
+		res[i] = trn.New(period.Start().Add(offset), dur)
+	}
+	return res
+}
+
+func overlapsAny(r trn.Range, rngs []trn.Range) bool {
+	for _, rng := range rngs {
+		if r.Overlaps(rng) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package gen
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cappuccinotm/trn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dt is Saturday
+var dt = time.Date(2021, 6, 12, 0, 0, 0, 0, time.UTC)
+
+func TestWorkload_Generate(t *testing.T) {
+	w := Workload{Seed: 42, Resources: 3, Period: trn.New(dt, 7*24*time.Hour)}
+
+	got := w.Generate()
+	require.Len(t, got, 3)
+	assert.Equal(t, got, w.Generate(), "reproducible")
+	assert.NotEqual(t, got, Workload{Seed: 43, Resources: 3, Period: w.Period}.Generate())
+
+	for _, r := range got {
+		assert.Len(t, r.Hours, 5, "working days")
+		assert.NotEmpty(t, r.Bookings)
+
+		var booked time.Duration
+		for i, b := range r.Bookings {
+			booked += b.Duration()
+			assert.True(t, trn.Covers(b, r.Hours), "booking %s is out of working hours", b)
+			assert.True(t, b.Duration() >= 30*time.Minute && b.Duration() <= 2*time.Hour)
+			assert.Zero(t, b.Start().Minute()%15)
+			if i > 0 {
+				assert.False(t, r.Bookings[i-1].Overlaps(b))
+				assert.False(t, b.Start().Before(r.Bookings[i-1].Start()))
+			}
+		}
+		assert.Equal(t, 40*time.Hour-booked, r.Free.Duration())
+	}
+
+	assert.Equal(t, "resource-0002", got[2].ID)
+}
+
+func TestRanges(t *testing.T) {
+	period := trn.New(dt, 24*time.Hour)
+
+	got := Ranges(1, 1000, period, time.Hour)
+	require.Len(t, got, 1000)
+	assert.Equal(t, got, Ranges(1, 1000, period, time.Hour))
+
+	for _, r := range got {
+		assert.True(t, period.Contains(r))
+		assert.True(t, r.Duration() > 0 && r.Duration() <= time.Hour)
+	}
+
+	short := trn.New(dt, time.Minute)
+	for _, r := range Ranges(1, 10, short, time.Hour) {
+		assert.True(t, short.Contains(r), "duration is limited by the period")
+	}
+}
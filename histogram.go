@@ -0,0 +1,84 @@
+package trn
+
+import "time"
+
+// HistogramByBucket counts the starts of the ranges per bucket of the given
+// duration, e.g. bookings per hour. The histogram is keyed by the start of
+// the bucket in the given location, UTC if nil.
+// Buckets are aligned to the local midnight of each day, so the bucket
+// duration is expected to divide 24 hours, otherwise the last bucket of
+// each day is shorter. Buckets of a day or longer are treated as daily.
+// Returns nil if the bucket is not positive.
+func HistogramByBucket(ranges []Range, bucket time.Duration, loc *time.Location) map[time.Time]int {
+	if bucket <= 0 {
+		return nil
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	res := map[time.Time]int{}
+	for _, rng := range ranges {
+		res[bucketOf(rng.st, bucket, loc).st]++
+	}
+	return res
+}
+
+// CoverageByBucket sums the time covered by the ranges per bucket of the
+// given duration, e.g. busy time per hour. The overlapping ranges count
+// several times. Buckets are aligned the same way as in HistogramByBucket.
+// Returns nil if the bucket is not positive.
+func CoverageByBucket(ranges []Range, bucket time.Duration, loc *time.Location) map[time.Time]time.Duration {
+	if bucket <= 0 {
+		return nil
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	res := map[time.Time]time.Duration{}
+	for _, rng := range ranges {
+		for b := bucketOf(rng.st, bucket, loc); b.st.Before(rng.End()); b = bucketOf(b.End(), bucket, loc) {
+			if part := rng.Truncate(b); part.dur > 0 {
+				res[b.st] += part.dur
+			}
+		}
+	}
+	return res
+}
+
+// bucketOf returns the bucket, which contains t, aligned to the local
+// midnight and cut at the next local midnight.
+func bucketOf(t time.Time, bucket time.Duration, loc *time.Location) Range {
+	lt := t.In(loc)
+	y, m, d := lt.Date()
+	midnight := time.Date(y, m, d, 0, 0, 0, 0, loc)
+	nextMidnight := time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+
+	st := midnight.Add(lt.Sub(midnight) / bucket * bucket)
+	end := st.Add(bucket)
+	if end.After(nextMidnight) {
+		end = nextMidnight
+	}
+
+	return Range{st: st, dur: end.Sub(st)}
+}
+
+// WeekHeatmap sums the time covered by the ranges per weekday and hour of
+// the day in the given location, UTC if nil, indexed as
+// [time.Weekday][hour].
+// The ranges are split at the local hour and day boundaries, taking DST
+// transitions into account, e.g. the repeated hour on the DST end day
+// accumulates both its occurrences.
+func WeekHeatmap(ranges []Range, loc *time.Location) [7][24]time.Duration {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	var res [7][24]time.Duration
+	for st, dur := range CoverageByBucket(ranges, time.Hour, loc) {
+		lt := st.In(loc)
+		res[lt.Weekday()][lt.Hour()] += dur
+	}
+	return res
+}
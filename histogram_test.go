@@ -0,0 +1,95 @@
+package trn
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistogramByBucket(t *testing.T) {
+	ranges := []Range{
+		MustRange(Between(tm(9, 0), tm(9, 30))),
+		MustRange(Between(tm(9, 45), tm(11, 15))),
+		MustRange(Between(tm(10, 59), tm(11, 0))),
+		MustRange(Between(dhm(13, 9, 10), dhm(13, 9, 20))),
+	}
+
+	assert.Equal(t, map[time.Time]int{
+		tm(9, 0):      2,
+		tm(10, 0):     1,
+		dhm(13, 9, 0): 1,
+	}, HistogramByBucket(ranges, time.Hour, time.UTC))
+
+	assert.Equal(t, map[time.Time]int{tm(0, 0): 3, dhm(13, 0, 0): 1},
+		HistogramByBucket(ranges, 48*time.Hour, time.UTC))
+
+	assert.Nil(t, HistogramByBucket(ranges, 0, time.UTC))
+	assert.Equal(t, HistogramByBucket(ranges, time.Hour, time.UTC), HistogramByBucket(ranges, time.Hour, nil))
+
+	t.Run("buckets aligned to local midnight", func(t *testing.T) {
+		loc := time.FixedZone("IST", 5*60*60+30*60)
+		got := HistogramByBucket(ranges[:1], time.Hour, loc)
+		assert.Len(t, got, 1)
+		for k, v := range got {
+			assert.Equal(t, "2021-06-12 14:00", k.Format("2006-01-02 15:04"))
+			assert.Equal(t, 1, v)
+		}
+	})
+}
+
+func TestCoverageByBucket(t *testing.T) {
+	ranges := []Range{
+		MustRange(Between(tm(9, 0), tm(9, 30))),
+		MustRange(Between(tm(9, 45), tm(11, 15))),
+		MustRange(Between(tm(10, 30), tm(10, 45))),
+		MustRange(Between(tm(23, 30), dhm(13, 0, 45))),
+	}
+
+	assert.Equal(t, map[time.Time]time.Duration{
+		tm(9, 0):      45 * time.Minute,
+		tm(10, 0):     75 * time.Minute,
+		tm(11, 0):     15 * time.Minute,
+		tm(23, 0):     30 * time.Minute,
+		dhm(13, 0, 0): 45 * time.Minute,
+	}, CoverageByBucket(ranges, time.Hour, time.UTC))
+
+	assert.Nil(t, CoverageByBucket(ranges, 0, time.UTC))
+	assert.Equal(t, CoverageByBucket(ranges, time.Hour, time.UTC), CoverageByBucket(ranges, time.Hour, nil))
+}
+
+func TestWeekHeatmap(t *testing.T) {
+	ranges := []Range{
+		MustRange(Between(tm(9, 30), tm(11, 15))),         // saturday
+		MustRange(Between(dhm(19, 9, 0), dhm(19, 10, 0))), // next saturday
+		MustRange(Between(tm(23, 30), dhm(13, 0, 45))),    // saturday to sunday
+	}
+
+	var want [7][24]time.Duration
+	want[time.Saturday][9] = 90 * time.Minute
+	want[time.Saturday][10] = time.Hour
+	want[time.Saturday][11] = 15 * time.Minute
+	want[time.Saturday][23] = 30 * time.Minute
+	want[time.Sunday][0] = 45 * time.Minute
+
+	assert.Equal(t, want, WeekHeatmap(ranges, time.UTC))
+	assert.Equal(t, want, WeekHeatmap(ranges, nil))
+}
+
+func TestWeekHeatmap_DST(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("tzdata is not available")
+	}
+
+	// DST ends on 31 October 2021 at 03:00 in Berlin, 02:00-03:00 repeats
+	day := time.Date(2021, 10, 31, 0, 0, 0, 0, loc)
+	got := WeekHeatmap([]Range{New(day, 25*time.Hour)}, loc)
+
+	assert.Equal(t, 2*time.Hour, got[time.Sunday][2])
+	for h := 0; h < 24; h++ {
+		if h != 2 {
+			assert.Equal(t, time.Hour, got[time.Sunday][h], "hour %d", h)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package trn
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// horizon is the package-wide horizon, accessed atomically.
+var horizon int64
+
+// timeNow is the clock of the horizon, replaced in tests.
+var timeNow = time.Now
+
+// SetHorizon sets the package-wide horizon: Between and the decoders, i.e.
+// the boundaries, where the user input comes in, clamp the range ends,
+// which are later than now+d, to now+d, e.g. to protect downstream systems
+// from the end dates in the year 9999 supplied by users. Ranges, which
+// start after now+d, are rejected with ErrBeyondHorizon, while the
+// decoders in DecodeLenient mode give the empty range instead. In
+// DecodeStrict mode the decoders reject all the ranges ending after now+d.
+// New and NewBetween, which the library uses for its own computations,
+// don't apply the horizon. Zero or negative d (default) disables it.
+func SetHorizon(d time.Duration) { atomic.StoreInt64(&horizon, int64(d)) }
+
+// CurrentHorizon returns the package-wide horizon.
+func CurrentHorizon() time.Duration { return time.Duration(atomic.LoadInt64(&horizon)) }
+
+// Horizon clamps the range end, which is later than now+d, to now+d, the
+// same way as the package-wide horizon does, but per call. The range,
+// which starts after now+d, becomes empty. Zero or negative d leaves the
+// range as is.
+func Horizon(d time.Duration) Option {
+	return func(r *Range) {
+		if d > 0 {
+			*r, _ = r.clampTo(AddSaturating(timeNow(), d))
+		}
+	}
+}
+
+// horizonLimit returns the instant of the package-wide horizon, if set.
+func horizonLimit() (time.Time, bool) {
+	h := CurrentHorizon()
+	if h <= 0 {
+		return time.Time{}, false
+	}
+	return AddSaturating(timeNow(), h), true
+}
+
+// checkHorizon returns ErrBeyondHorizon if the decoded range ends beyond
+// the package-wide horizon in the strict mode.
+func checkHorizon(end time.Time) error {
+	if CurrentDecodeMode() != DecodeStrict {
+		return nil
+	}
+	if limit, ok := horizonLimit(); ok && end.After(limit) {
+		return beyondHorizon(end, limit)
+	}
+	return nil
+}
+
+// beyondHorizon returns ErrBeyondHorizon, describing the time after the
+// limit.
+func beyondHorizon(t, limit time.Time) error {
+	return fmt.Errorf("%w: %s is after %s", ErrBeyondHorizon, FormatRFC9557(t), FormatRFC9557(limit))
+}
+
+// clampTo moves the range end, which is later than the limit, to the
+// limit. Returns the empty range and false if the range starts after the
+// limit.
+func (r Range) clampTo(limit time.Time) (Range, bool) {
+	switch {
+	case !r.End().After(limit):
+		return r, true
+	case r.st.After(limit):
+		return Range{}, false
+	}
+	return Range{st: r.st, dur: limit.Sub(r.st)}, true
+}
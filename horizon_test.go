@@ -0,0 +1,93 @@
+package trn
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetHorizon(t *testing.T) {
+	timeNow = func() time.Time { return dhm(12, 12, 0) }
+	defer func() { timeNow = time.Now }()
+
+	assert.Zero(t, CurrentHorizon())
+	assert.Equal(t, New(dhm(12, 12, 0), 72*time.Hour), New(dhm(12, 12, 0), 72*time.Hour))
+
+	SetHorizon(48 * time.Hour)
+	defer SetHorizon(0)
+	assert.Equal(t, 48*time.Hour, CurrentHorizon())
+
+	t.Run("between", func(t *testing.T) {
+		assert.Equal(t, New(dhm(12, 12, 0), 48*time.Hour), MustRange(Between(dhm(12, 12, 0), dhm(15, 12, 0))))
+		assert.Equal(t, New(dhm(12, 12, 0), time.Hour), MustRange(Between(dhm(12, 12, 0), dhm(12, 13, 0))))
+		assert.Equal(t, New(dhm(13, 12, 0), 24*time.Hour), MustRange(Between(dhm(13, 12, 0), MaxTime)))
+
+		berlin := time.FixedZone("UTC+2", 2*60*60)
+		r := MustRange(Between(dhm(13, 12, 0).In(berlin), dhm(16, 12, 0)))
+		assert.Equal(t, berlin, r.Start().Location())
+		assert.True(t, r.End().Equal(dhm(14, 12, 0)))
+
+		_, err := Between(dhm(20, 12, 0), dhm(20, 13, 0))
+		assert.ErrorIs(t, err, ErrBeyondHorizon)
+
+		// touching the horizon is fine
+		assert.Equal(t, New(dhm(14, 12, 0), 0), MustRange(Between(dhm(14, 12, 0), dhm(14, 13, 0))))
+	})
+
+	t.Run("constructors are not affected", func(t *testing.T) {
+		assert.Equal(t, 72*time.Hour, New(dhm(12, 12, 0), 72*time.Hour).Duration())
+		assert.Equal(t, Range{st: dhm(20, 12, 0), dur: time.Hour}, New(dhm(20, 12, 0), time.Hour))
+		assert.Equal(t, Range{st: dhm(20, 12, 0), dur: time.Hour}, NewBetween(dhm(20, 12, 0), dhm(20, 13, 0)))
+	})
+
+	t.Run("decoders", func(t *testing.T) {
+		const far = `{"start":"2021-06-13T12:00:00Z","end":"9999-12-31T00:00:00Z"}`
+
+		var r Range
+		require.NoError(t, json.Unmarshal([]byte(far), &r))
+		assert.Equal(t, New(dhm(13, 12, 0), 24*time.Hour), r)
+
+		b, err := NewSet(Range{st: dhm(13, 12, 0), dur: 72 * time.Hour}).MarshalBinary()
+		require.NoError(t, err)
+
+		var s Set
+		require.NoError(t, s.UnmarshalBinary(b))
+		assert.Equal(t, []Range{New(dhm(13, 12, 0), 24*time.Hour)}, s.Ranges())
+
+		const beyond = `{"start":"2021-06-20T12:00:00Z","end":"2021-06-20T13:00:00Z"}`
+		assert.ErrorIs(t, json.Unmarshal([]byte(beyond), &r), ErrBeyondHorizon)
+
+		bb, err := NewSet(New(dhm(13, 12, 0), time.Hour), Range{st: dhm(20, 12, 0), dur: time.Hour}).MarshalBinary()
+		require.NoError(t, err)
+		assert.ErrorIs(t, s.UnmarshalBinary(bb), ErrBeyondHorizon)
+
+		SetDecodeMode(DecodeLenient)
+		require.NoError(t, json.Unmarshal([]byte(beyond), &r))
+		assert.Equal(t, Range{}, r)
+		require.NoError(t, s.UnmarshalBinary(bb))
+		assert.Equal(t, []Range{New(dhm(13, 12, 0), time.Hour)}, s.Ranges())
+
+		SetDecodeMode(DecodeStrict)
+		defer SetDecodeMode(DecodeDefault)
+
+		assert.ErrorIs(t, json.Unmarshal([]byte(far), &r), ErrBeyondHorizon)
+		assert.ErrorIs(t, s.UnmarshalBinary(b), ErrBeyondHorizon)
+
+		require.NoError(t, json.Unmarshal([]byte(`{"start":"2021-06-13T12:00:00Z","end":"2021-06-13T13:00:00Z"}`), &r))
+		assert.Equal(t, New(dhm(13, 12, 0), time.Hour), r)
+	})
+}
+
+func TestHorizon(t *testing.T) {
+	timeNow = func() time.Time { return dhm(12, 12, 0) }
+	defer func() { timeNow = time.Now }()
+
+	assert.Equal(t, New(dhm(12, 12, 0), time.Hour), New(dhm(12, 12, 0), 72*time.Hour, Horizon(time.Hour)))
+	assert.Equal(t, New(dhm(12, 12, 0), 72*time.Hour), New(dhm(12, 12, 0), 72*time.Hour, Horizon(0)))
+	assert.Equal(t, New(dhm(12, 10, 0), 3*time.Hour),
+		MustRange(Between(dhm(12, 10, 0), dhm(20, 0, 0), Horizon(time.Hour))))
+	assert.Equal(t, Range{}, New(dhm(20, 12, 0), time.Hour, Horizon(time.Hour)))
+}
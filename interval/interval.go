@@ -0,0 +1,143 @@
+// Package interval provides the basic interval algebra over ordered
+// values, such as sequence numbers, block heights or byte offsets.
+//
+// It's a standalone implementation, which doesn't share the algorithms with
+// package trn and differs from it in semantics: intervals are closed, there
+// are no merge options, Flip truncates the intervals to the period and
+// doesn't return the zero-length gaps, and Intersection reports the absence
+// of the common part explicitly.
+package interval
+
+import (
+	"sort"
+
+	"github.com/cappuccinotm/trn"
+)
+
+// Ordered is a constraint for the types, which values could be compared
+// with the < operator.
+type Ordered interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64 | ~string
+}
+
+// Interval represents the closed interval [Start, End].
+type Interval[T Ordered] struct {
+	Start T
+	End   T
+}
+
+// New returns the interval between the given values.
+// Returns trn.ErrStartAfterEnd if the start is greater than the end.
+func New[T Ordered](start, end T) (Interval[T], error) {
+	if end < start {
+		return Interval[T]{}, trn.ErrStartAfterEnd
+	}
+	return Interval[T]{Start: start, End: end}, nil
+}
+
+// Empty returns true if the interval is degenerate, i.e. its start equals
+// to its end.
+func (i Interval[T]) Empty() bool { return i.Start == i.End }
+
+// Contains returns true if the other interval is within this interval.
+func (i Interval[T]) Contains(other Interval[T]) bool {
+	return i.Start <= other.Start && other.End <= i.End
+}
+
+// Overlaps returns true if the intervals share some values, touching
+// boundaries are not considered overlapping.
+func (i Interval[T]) Overlaps(other Interval[T]) bool {
+	return i.Start < other.End && other.Start < i.End
+}
+
+// Truncate returns the part of the interval within the bounds.
+// Returns false if the interval doesn't intersect the bounds.
+func (i Interval[T]) Truncate(bounds Interval[T]) (Interval[T], bool) {
+	if i.End < bounds.Start || bounds.End < i.Start {
+		return Interval[T]{}, false
+	}
+
+	res := i
+	if res.Start < bounds.Start {
+		res.Start = bounds.Start
+	}
+	if bounds.End < res.End {
+		res.End = bounds.End
+	}
+	return res, true
+}
+
+// Merge merges the overlapping and touching intervals, the resulting
+// intervals are sorted. As the intervals are closed, the touching ones
+// share the boundary value.
+func Merge[T Ordered](intervals []Interval[T]) []Interval[T] {
+	if len(intervals) == 0 {
+		return nil
+	}
+
+	sorted := make([]Interval[T], len(intervals))
+	copy(sorted, intervals)
+	sort.Slice(sorted, func(a, b int) bool { return sorted[a].Start < sorted[b].Start })
+
+	res := []Interval[T]{sorted[0]}
+	for _, i := range sorted[1:] {
+		last := &res[len(res)-1]
+		if last.End < i.Start {
+			res = append(res, i)
+			continue
+		}
+		if last.End < i.End {
+			last.End = i.End
+		}
+	}
+
+	return res
+}
+
+// Flip returns the gaps between the given intervals within the period.
+// Intervals may be unsorted, overlapping and lie outside the period. The
+// gaps span from the end of one interval to the start of the next one, the
+// boundaries are shared with the intervals, so the zero-length gaps, e.g.
+// before the interval starting at the period start, are not returned.
+func (i Interval[T]) Flip(intervals []Interval[T]) []Interval[T] {
+	var res []Interval[T]
+
+	cur := i.Start
+	for _, in := range Merge(intervals) {
+		in, ok := in.Truncate(i)
+		if !ok {
+			continue
+		}
+		if cur < in.Start {
+			res = append(res, Interval[T]{Start: cur, End: in.Start})
+		}
+		cur = in.End
+	}
+
+	if cur < i.End {
+		res = append(res, Interval[T]{Start: cur, End: i.End})
+	}
+
+	return res
+}
+
+// Intersection returns the interval, which is common for all the given
+// intervals. Returns false if there are no intervals or they don't have
+// a common part.
+func Intersection[T Ordered](intervals []Interval[T]) (Interval[T], bool) {
+	if len(intervals) == 0 {
+		return Interval[T]{}, false
+	}
+
+	res := intervals[0]
+	for _, i := range intervals[1:] {
+		var ok bool
+		if res, ok = res.Truncate(i); !ok {
+			return Interval[T]{}, false
+		}
+	}
+
+	return res, true
+}
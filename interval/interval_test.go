@@ -0,0 +1,78 @@
+package interval
+
+import (
+	"testing"
+
+	"github.com/cappuccinotm/trn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	i, err := New(10, 20)
+	assert.NoError(t, err)
+	assert.Equal(t, Interval[int]{Start: 10, End: 20}, i)
+
+	_, err = New(20, 10)
+	assert.ErrorIs(t, err, trn.ErrStartAfterEnd)
+
+	assert.True(t, Interval[int]{Start: 5, End: 5}.Empty())
+	assert.False(t, i.Empty())
+}
+
+func TestInterval_Predicates(t *testing.T) {
+	i := Interval[uint64]{Start: 10, End: 20}
+
+	assert.True(t, i.Contains(Interval[uint64]{Start: 10, End: 15}))
+	assert.False(t, i.Contains(Interval[uint64]{Start: 15, End: 25}))
+
+	assert.True(t, i.Overlaps(Interval[uint64]{Start: 15, End: 25}))
+	assert.False(t, i.Overlaps(Interval[uint64]{Start: 20, End: 25}))
+
+	got, ok := i.Truncate(Interval[uint64]{Start: 15, End: 25})
+	assert.True(t, ok)
+	assert.Equal(t, Interval[uint64]{Start: 15, End: 20}, got)
+
+	_, ok = i.Truncate(Interval[uint64]{Start: 21, End: 25})
+	assert.False(t, ok)
+}
+
+func TestMerge(t *testing.T) {
+	got := Merge([]Interval[int]{
+		{Start: 30, End: 40},
+		{Start: 0, End: 10},
+		{Start: 10, End: 15}, // touches the previous one
+		{Start: 12, End: 14}, // within the previous one
+		{Start: 35, End: 50},
+	})
+	assert.Equal(t, []Interval[int]{{Start: 0, End: 15}, {Start: 30, End: 50}}, got)
+	assert.Nil(t, Merge[int](nil))
+}
+
+func TestInterval_Flip(t *testing.T) {
+	period := Interval[float64]{Start: 0, End: 100}
+
+	got := period.Flip([]Interval[float64]{
+		{Start: 50, End: 60},
+		{Start: -10, End: 10},
+		{Start: 55, End: 70},
+		{Start: 150, End: 160},
+	})
+	assert.Equal(t, []Interval[float64]{{Start: 10, End: 50}, {Start: 70, End: 100}}, got)
+
+	assert.Equal(t, []Interval[float64]{period}, period.Flip(nil))
+	assert.Nil(t, period.Flip([]Interval[float64]{{Start: -1, End: 101}}))
+	assert.Equal(t, []Interval[float64]{{Start: 20, End: 100}},
+		period.Flip([]Interval[float64]{{Start: 10, End: 20}, {Start: 0, End: 10}}))
+}
+
+func TestIntersection(t *testing.T) {
+	got, ok := Intersection([]Interval[int]{{Start: 0, End: 50}, {Start: 10, End: 60}, {Start: 20, End: 30}})
+	assert.True(t, ok)
+	assert.Equal(t, Interval[int]{Start: 20, End: 30}, got)
+
+	_, ok = Intersection([]Interval[int]{{Start: 0, End: 10}, {Start: 20, End: 30}})
+	assert.False(t, ok)
+
+	_, ok = Intersection[int](nil)
+	assert.False(t, ok)
+}
@@ -0,0 +1,91 @@
+package trn
+
+import "time"
+
+// Interval is implemented by the types, which have the start and the end,
+// such as Range or ranges of third-party packages, so they might be merged,
+// flipped and intersected without converting them into Range beforehand.
+type Interval interface {
+	Start() time.Time
+	End() time.Time
+}
+
+// adapter is the Interval over the value of arbitrary type.
+type adapter[T any] struct {
+	v          T
+	start, end func(T) time.Time
+}
+
+func (a adapter[T]) Start() time.Time { return a.start(a.v) }
+func (a adapter[T]) End() time.Time   { return a.end(a.v) }
+
+// Adapt wraps the value into the Interval, which boundaries are read with
+// the given functions, e.g. for the event structs with exported fields.
+func Adapt[T any](v T, start, end func(T) time.Time) Interval {
+	return adapter[T]{v: v, start: start, end: end}
+}
+
+// AdaptAll wraps each of the values with Adapt.
+func AdaptAll[T any](vs []T, start, end func(T) time.Time) []Interval {
+	res := make([]Interval, len(vs))
+	for i, v := range vs {
+		res[i] = Adapt(v, start, end)
+	}
+	return res
+}
+
+// MergeIntervals merges the intervals the same way as MergeOverlappingRanges
+// does. Intervals, which end before their start, are skipped.
+func MergeIntervals[I Interval](intervals []I, opts ...MergeOption) []Range {
+	o := newMergeOptions(opts)
+
+	a := AcquireArena()
+	defer a.Release()
+
+	a.bounds = a.bounds[:0]
+	for _, i := range intervals {
+		st, end := i.Start(), i.End()
+		if end.Before(st) {
+			continue
+		}
+		a.bounds = append(a.bounds, boundary{tm: st, typ: boundaryStart}, boundary{tm: end, typ: boundaryEnd})
+	}
+
+	return dropShorter(a.sweep(nil, o.epsilon), o.minDuration)
+}
+
+// FlipIntervals flips the intervals within the period the same way as
+// Range.Flip does. Intervals, which end before their start, are skipped.
+func FlipIntervals[I Interval](period Range, intervals []I, opts ...MergeOption) []Range {
+	o := newMergeOptions(opts)
+
+	merged := MergeIntervals(intervals, Epsilon(o.epsilon))
+	if len(merged) == 0 {
+		return dropShorter([]Range{period}, o.minDuration)
+	}
+	return dropShorter(period.flipValidRanges(merged), o.minDuration)
+}
+
+// IntersectIntervals returns the date range, which is common for all the
+// given intervals, in the location of the latest start. Returns the empty
+// range if there are no intervals or they don't have common time.
+func IntersectIntervals[I Interval](intervals []I) Range {
+	if len(intervals) == 0 {
+		return Range{}
+	}
+
+	st, end := intervals[0].Start(), intervals[0].End()
+	for _, i := range intervals[1:] {
+		if s := i.Start(); s.After(st) {
+			st = s
+		}
+		if e := i.End(); e.Before(end) {
+			end = e
+		}
+	}
+
+	if end.Before(st) {
+		return Range{}
+	}
+	return Range{st: st, dur: end.Sub(st)}
+}
@@ -0,0 +1,70 @@
+package trn
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testEvent struct {
+	Title    string
+	From, To time.Time
+}
+
+func eventIntervals(events ...testEvent) []Interval {
+	return AdaptAll(events,
+		func(e testEvent) time.Time { return e.From },
+		func(e testEvent) time.Time { return e.To },
+	)
+}
+
+func TestMergeIntervals(t *testing.T) {
+	events := eventIntervals(
+		testEvent{Title: "standup", From: tm(10, 0), To: tm(10, 15)},
+		testEvent{Title: "review", From: tm(13, 0), To: tm(14, 0)},
+		testEvent{Title: "planning", From: tm(10, 10), To: tm(11, 0)},
+		testEvent{Title: "broken", From: tm(16, 0), To: tm(15, 0)},
+	)
+
+	assert.Equal(t, []Range{
+		MustRange(Between(tm(10, 0), tm(11, 0))),
+		MustRange(Between(tm(13, 0), tm(14, 0))),
+	}, MergeIntervals(events))
+
+	ranges := []Range{New(tm(10, 0), time.Hour), New(tm(10, 30), time.Hour)}
+	assert.Equal(t, MergeOverlappingRanges(ranges), MergeIntervals(ranges))
+	assert.Empty(t, MergeIntervals([]Range(nil)))
+}
+
+func TestFlipIntervals(t *testing.T) {
+	period := MustRange(Between(tm(9, 0), tm(18, 0)))
+	events := eventIntervals(
+		testEvent{From: tm(13, 0), To: tm(14, 0)},
+		testEvent{From: tm(10, 0), To: tm(12, 58)},
+	)
+
+	assert.Equal(t, period.Flip([]Range{
+		MustRange(Between(tm(13, 0), tm(14, 0))),
+		MustRange(Between(tm(10, 0), tm(12, 58))),
+	}), FlipIntervals(period, events))
+
+	assert.Equal(t, []Range{
+		MustRange(Between(tm(9, 0), tm(10, 0))),
+		MustRange(Between(tm(14, 0), tm(18, 0))),
+	}, FlipIntervals(period, events, MinDuration(5*time.Minute)))
+
+	assert.Equal(t, []Range{period}, FlipIntervals(period, []Interval(nil)))
+}
+
+func TestIntersectIntervals(t *testing.T) {
+	events := eventIntervals(
+		testEvent{From: tm(10, 0), To: tm(14, 0)},
+		testEvent{From: tm(12, 0), To: tm(16, 0)},
+	)
+	assert.Equal(t, MustRange(Between(tm(12, 0), tm(14, 0))), IntersectIntervals(events))
+
+	events = append(events, New(tm(15, 0), time.Hour))
+	assert.True(t, IntersectIntervals(events).Empty())
+	assert.True(t, IntersectIntervals([]Interval(nil)).Empty())
+}
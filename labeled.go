@@ -0,0 +1,221 @@
+package trn
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Labeled is a range with the attached value.
+type Labeled[T any] struct {
+	Range
+	Value T
+}
+
+// Label attaches the value to the range.
+func Label[T any](r Range, v T) Labeled[T] { return Labeled[T]{Range: r, Value: v} }
+
+// Ranges returns the ranges of the labeled items, without values.
+func Ranges[T any](items []Labeled[T]) []Range {
+	res := make([]Range, len(items))
+	for i, item := range items {
+		res[i] = item.Range
+	}
+	return res
+}
+
+// MergeLabeled merges the overlapping and adjacent labeled ranges, the same
+// way as MergeOverlappingRanges does, combining the values of the merged
+// ranges with the merge function in the order of ranges' starts.
+func MergeLabeled[T any](items []Labeled[T], merge func(a, b T) T) []Labeled[T] {
+	if len(items) == 0 {
+		return nil
+	}
+
+	sorted := make([]Labeled[T], len(items))
+	copy(sorted, items)
+	sort.SliceStable(sorted, func(i, j int) bool { return lessRange(sorted[i].Range, sorted[j].Range) })
+
+	res := []Labeled[T]{sorted[0]}
+	for _, item := range sorted[1:] {
+		last := &res[len(res)-1]
+		if item.st.After(last.End()) {
+			res = append(res, item)
+			continue
+		}
+
+		if item.End().After(last.End()) {
+			last.dur = item.End().Sub(last.st)
+		}
+		last.Value = merge(last.Value, item.Value)
+	}
+
+	return res
+}
+
+// FlattenLabeled splits the labeled ranges into the sorted non-overlapping
+// segments, where each segment carries the values of all ranges covering
+// it, combined with the merge function in the order of the input.
+// Adjacent segments covered by the same ranges are merged, gaps are omitted.
+func FlattenLabeled[T any](items []Labeled[T], merge func(a, b T) T) []Labeled[T] {
+	var res []Labeled[T]
+	sweepLabeled(items, func(st, end time.Time, active []int) {
+		v := items[active[0]].Value
+		for _, idx := range active[1:] {
+			v = merge(v, items[idx].Value)
+		}
+		res = append(res, Labeled[T]{Range: Range{st: st, dur: end.Sub(st)}, Value: v})
+	})
+	return res
+}
+
+// AggOp is the operation, which combines the values of the overlapping
+// weighted ranges.
+type AggOp int
+
+// Aggregation operations.
+const (
+	// AggSum sums the values, e.g. staffing levels.
+	AggSum AggOp = iota
+	// AggMax takes the maximum of the values, e.g. peak bandwidth.
+	AggMax
+	// AggMin takes the minimum of the values, e.g. the best price.
+	AggMin
+)
+
+func (op AggOp) apply(a, b float64) float64 {
+	switch op {
+	case AggMax:
+		return math.Max(a, b)
+	case AggMin:
+		return math.Min(a, b)
+	default:
+		return a + b
+	}
+}
+
+// Aggregate flattens the overlapping weighted ranges into the sorted
+// disjoint segments, the same way as FlattenLabeled does, with values
+// combined by the operation, e.g. to get staffing levels from shifts.
+// Adjacent segments with equal values are merged, gaps are omitted.
+func Aggregate(items []Labeled[float64], op AggOp) []Labeled[float64] {
+	var res []Labeled[float64]
+	for _, seg := range FlattenLabeled(items, op.apply) {
+		if n := len(res); n > 0 && res[n-1].End().Equal(seg.st) && res[n-1].Value == seg.Value {
+			res[n-1].dur += seg.dur
+			continue
+		}
+		res = append(res, seg)
+	}
+	return res
+}
+
+// TruncateLabeled truncates each labeled range to the bounds, keeping its
+// value. Ranges outside the bounds are dropped.
+func TruncateLabeled[T any](items []Labeled[T], bounds Range) []Labeled[T] {
+	var res []Labeled[T]
+	for _, item := range items {
+		if rng := item.Truncate(bounds); rng.dur > 0 {
+			res = append(res, Labeled[T]{Range: rng, Value: item.Value})
+		}
+	}
+	return res
+}
+
+// GroupBy groups the ranges of the labeled items by the key, derived from
+// their values, e.g. by employee or room. Ranges within each group keep
+// the order of the input.
+func GroupBy[K comparable, T any](items []Labeled[T], key func(T) K) map[K][]Range {
+	res := map[K][]Range{}
+	for _, item := range items {
+		k := key(item.Value)
+		res[k] = append(res[k], item.Range)
+	}
+	return res
+}
+
+// MergeGroups merges the overlapping ranges within each group, e.g. to
+// collapse busy times per employee:
+//
+//	busy := trn.MergeGroups(trn.GroupBy(events, func(e Event) string { return e.Employee }))
+func MergeGroups[K comparable](groups map[K][]Range) map[K][]Range {
+	res := make(map[K][]Range, len(groups))
+	for k, rngs := range groups {
+		res[k] = MergeOverlappingRanges(rngs)
+	}
+	return res
+}
+
+// sweepLabeled calls fn for each maximal segment, covered by the same
+// non-empty set of ranges, with the indexes of those ranges in ascending
+// order. Segments are visited in chronological order.
+func sweepLabeled[T any](items []Labeled[T], fn func(st, end time.Time, active []int)) {
+	type event struct {
+		tm  time.Time
+		idx int
+		typ boundaryType
+	}
+
+	events := make([]event, 0, len(items)*2)
+	for i, item := range items {
+		if item.dur <= 0 {
+			continue
+		}
+		events = append(events,
+			event{tm: item.st, idx: i, typ: boundaryStart},
+			event{tm: item.End(), idx: i, typ: boundaryEnd},
+		)
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].tm.Before(events[j].tm) })
+
+	var (
+		active []int
+		segSt  time.Time
+	)
+	for i := 0; i < len(events); {
+		tm := events[i].tm
+		prev := active
+
+		next := make([]int, len(active))
+		copy(next, active)
+		for ; i < len(events) && events[i].tm.Equal(tm); i++ {
+			if events[i].typ == boundaryStart {
+				next = append(next, events[i].idx)
+				continue
+			}
+			next = removeIdx(next, events[i].idx)
+		}
+		sort.Ints(next)
+
+		if equalInts(prev, next) {
+			continue
+		}
+
+		if len(prev) > 0 {
+			fn(segSt, tm, prev)
+		}
+		active, segSt = next, tm
+	}
+}
+
+func removeIdx(s []int, v int) []int {
+	for i := range s {
+		if s[i] == v {
+			return append(s[:i], s[i+1:]...)
+		}
+	}
+	return s
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
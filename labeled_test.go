@@ -0,0 +1,145 @@
+package trn
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type labeledSegment struct {
+	rng   string
+	value string
+}
+
+func formattedLabeled(items []Labeled[string]) []labeledSegment {
+	var res []labeledSegment
+	for _, item := range items {
+		res = append(res, labeledSegment{rng: item.Format("15:04"), value: item.Value})
+	}
+	return res
+}
+
+func concat(a, b string) string { return a + "+" + b }
+
+func TestMergeLabeled(t *testing.T) {
+	items := []Labeled[string]{
+		Label(MustRange(Between(tm(13, 0), tm(14, 0))), "b"),
+		Label(MustRange(Between(tm(12, 0), tm(13, 0))), "a"),
+		Label(MustRange(Between(tm(13, 30), tm(13, 45))), "c"),
+		Label(MustRange(Between(tm(15, 0), tm(16, 0))), "d"),
+	}
+
+	assert.Equal(t, []labeledSegment{
+		{rng: "[12:00, 14:00]", value: "a+b+c"},
+		{rng: "[15:00, 16:00]", value: "d"},
+	}, formattedLabeled(MergeLabeled(items, concat)))
+
+	assert.Nil(t, MergeLabeled[string](nil, concat))
+
+	// ties on start are broken by end, regardless of the input order
+	items = []Labeled[string]{
+		Label(MustRange(Between(tm(12, 0), tm(14, 0))), "long"),
+		Label(MustRange(Between(tm(12, 0), tm(13, 0))), "short"),
+	}
+	assert.Equal(t, []labeledSegment{{rng: "[12:00, 14:00]", value: "short+long"}},
+		formattedLabeled(MergeLabeled(items, concat)))
+}
+
+func TestFlattenLabeled(t *testing.T) {
+	items := []Labeled[string]{
+		Label(MustRange(Between(tm(12, 0), tm(14, 0))), "a"),
+		Label(MustRange(Between(tm(13, 0), tm(15, 0))), "b"),
+		Label(MustRange(Between(tm(13, 0), tm(13, 30))), "c"),
+		Label(MustRange(Between(tm(16, 0), tm(17, 0))), "d"),
+		Label(MustRange(Between(tm(17, 0), tm(18, 0))), "d"),
+	}
+
+	assert.Equal(t, []labeledSegment{
+		{rng: "[12:00, 13:00]", value: "a"},
+		{rng: "[13:00, 13:30]", value: "a+b+c"},
+		{rng: "[13:30, 14:00]", value: "a+b"},
+		{rng: "[14:00, 15:00]", value: "b"},
+		{rng: "[16:00, 17:00]", value: "d"},
+		{rng: "[17:00, 18:00]", value: "d"},
+	}, formattedLabeled(FlattenLabeled(items, concat)))
+}
+
+func TestAggregate(t *testing.T) {
+	shifts := []Labeled[float64]{
+		Label(MustRange(Between(tm(9, 0), tm(13, 0))), 2.0),
+		Label(MustRange(Between(tm(12, 0), tm(17, 0))), 3.0),
+		Label(MustRange(Between(tm(13, 0), tm(14, 0))), 1.0),
+		Label(MustRange(Between(tm(18, 0), tm(19, 0))), 3.0),
+	}
+
+	format := func(items []Labeled[float64]) []string {
+		var res []string
+		for _, item := range items {
+			res = append(res, fmt.Sprintf("%s %g", item.Format("15:04"), item.Value))
+		}
+		return res
+	}
+
+	assert.Equal(t, []string{
+		"[09:00, 12:00] 2",
+		"[12:00, 13:00] 5",
+		"[13:00, 14:00] 4",
+		"[14:00, 17:00] 3",
+		"[18:00, 19:00] 3",
+	}, format(Aggregate(shifts, AggSum)))
+
+	assert.Equal(t, []string{
+		"[09:00, 12:00] 2",
+		"[12:00, 17:00] 3",
+		"[18:00, 19:00] 3",
+	}, format(Aggregate(shifts, AggMax)))
+
+	assert.Equal(t, []string{
+		"[09:00, 13:00] 2",
+		"[13:00, 14:00] 1",
+		"[14:00, 17:00] 3",
+		"[18:00, 19:00] 3",
+	}, format(Aggregate(shifts, AggMin)))
+
+	assert.Nil(t, Aggregate(nil, AggSum))
+}
+
+func TestTruncateLabeled(t *testing.T) {
+	items := []Labeled[string]{
+		Label(MustRange(Between(tm(12, 0), tm(14, 0))), "a"),
+		Label(MustRange(Between(tm(15, 0), tm(16, 0))), "b"),
+	}
+
+	assert.Equal(t, []labeledSegment{{rng: "[13:00, 14:00]", value: "a"}},
+		formattedLabeled(TruncateLabeled(items, MustRange(Between(tm(13, 0), tm(15, 0))))))
+
+	assert.Equal(t, []Range{items[0].Range, items[1].Range}, Ranges(items))
+}
+
+func TestGroupBy(t *testing.T) {
+	type booking struct {
+		room  string
+		guest string
+	}
+
+	items := []Labeled[booking]{
+		Label(MustRange(Between(tm(12, 0), tm(13, 0))), booking{room: "red", guest: "alice"}),
+		Label(MustRange(Between(tm(9, 0), tm(10, 0))), booking{room: "blue", guest: "bob"}),
+		Label(MustRange(Between(tm(12, 30), tm(14, 0))), booking{room: "red", guest: "carol"}),
+		Label(MustRange(Between(tm(10, 0), tm(11, 0))), booking{room: "blue", guest: "dave"}),
+	}
+
+	groups := GroupBy(items, func(b booking) string { return b.room })
+	assert.Equal(t, map[string][]Range{
+		"red":  {items[0].Range, items[2].Range},
+		"blue": {items[1].Range, items[3].Range},
+	}, groups)
+
+	merged := MergeGroups(groups)
+	assert.Len(t, merged, 2)
+	assert.Equal(t, formattedRanges([]Range{MustRange(Between(tm(12, 0), tm(14, 0)))}, "15:04"),
+		formattedRanges(merged["red"], "15:04"))
+	assert.Equal(t, formattedRanges([]Range{MustRange(Between(tm(9, 0), tm(11, 0)))}, "15:04"),
+		formattedRanges(merged["blue"], "15:04"))
+}
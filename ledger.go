@@ -0,0 +1,82 @@
+package trn
+
+import "sync/atomic"
+
+// Ledger keeps the set of free ranges of the resource and books them.
+// It is safe for concurrent use, the changes are made by atomically
+// replacing the immutable set, so readers are never blocked.
+type Ledger struct {
+	free atomic.Value // *Set
+}
+
+// NewLedger makes a new ledger with the given free ranges.
+func NewLedger(free Set) *Ledger {
+	l := &Ledger{}
+	l.free.Store(&Set{rngs: free.rngs})
+	return l
+}
+
+// Free returns the current set of free ranges, it shares the ranges with
+// the ledger until modified, so it is cheap to call.
+func (l *Ledger) Free() Set { return l.load().Clone() }
+
+// ETag returns the ETag of the current set of free ranges.
+func (l *Ledger) ETag() string { return l.load().ETag() }
+
+// Book books the range, it must be fully free.
+// Returns ErrNotAvailable if the range is not free.
+func (l *Ledger) Book(r Range) error {
+	return l.update(func(s *Set) error {
+		if !s.Covers(r) {
+			return ErrNotAvailable
+		}
+		s.Remove(r)
+		return nil
+	})
+}
+
+// TryBookIfMatch books the range only if the set of free ranges has not
+// changed since the etag was taken, e.g. to implement If-Match semantics
+// of booking APIs. Returns the ETag of the new set of free ranges.
+// Returns ErrETagMismatch if the set has changed and ErrNotAvailable if
+// the range is not free.
+func (l *Ledger) TryBookIfMatch(etag string, r Range) (string, error) {
+	var newETag string
+	err := l.update(func(s *Set) error {
+		if s.ETag() != etag {
+			return ErrETagMismatch
+		}
+		if !s.Covers(r) {
+			return ErrNotAvailable
+		}
+		s.Remove(r)
+		newETag = s.ETag()
+		return nil
+	})
+	return newETag, err
+}
+
+// Release makes the range free again.
+func (l *Ledger) Release(r Range) {
+	_ = l.update(func(s *Set) error {
+		s.Add(r)
+		return nil
+	})
+}
+
+func (l *Ledger) load() *Set { return l.free.Load().(*Set) }
+
+// update applies the change to the copy of the current set and replaces
+// the current set with it, retrying if the set was replaced concurrently.
+func (l *Ledger) update(change func(s *Set) error) error {
+	for {
+		old := l.load()
+		upd := &Set{rngs: old.rngs}
+		if err := change(upd); err != nil {
+			return err
+		}
+		if l.free.CompareAndSwap(old, upd) {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package trn
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSet_ETag(t *testing.T) {
+	s := NewSet(MustRange(Between(tm(9, 0), tm(12, 0))))
+	etag := s.ETag()
+	assert.Equal(t, etag, NewSet(MustRange(Between(tm(9, 0), tm(12, 0))).In(time.Local)).ETag())
+
+	s.Remove(MustRange(Between(tm(10, 0), tm(11, 0))))
+	assert.NotEqual(t, etag, s.ETag())
+
+	s.Add(MustRange(Between(tm(10, 0), tm(11, 0))))
+	assert.Equal(t, etag, s.ETag())
+}
+
+func TestLedger(t *testing.T) {
+	l := NewLedger(NewSet(MustRange(Between(tm(9, 0), tm(12, 0)))))
+
+	assert.NoError(t, l.Book(MustRange(Between(tm(9, 0), tm(10, 0)))))
+	assert.ErrorIs(t, l.Book(MustRange(Between(tm(9, 30), tm(10, 30)))), ErrNotAvailable)
+	assert.Equal(t, []Range{MustRange(Between(tm(10, 0), tm(12, 0)))}, l.Free().Ranges())
+
+	etag := l.ETag()
+	newETag, err := l.TryBookIfMatch(etag, MustRange(Between(tm(10, 0), tm(11, 0))))
+	require.NoError(t, err)
+	assert.Equal(t, l.ETag(), newETag)
+
+	_, err = l.TryBookIfMatch(etag, MustRange(Between(tm(11, 0), tm(12, 0))))
+	assert.ErrorIs(t, err, ErrETagMismatch, "stale etag")
+
+	_, err = l.TryBookIfMatch(newETag, MustRange(Between(tm(10, 0), tm(12, 0))))
+	assert.ErrorIs(t, err, ErrNotAvailable)
+
+	l.Release(MustRange(Between(tm(9, 0), tm(11, 0))))
+	assert.Equal(t, []Range{MustRange(Between(tm(9, 0), tm(12, 0)))}, l.Free().Ranges())
+}
+
+func TestLedger_Concurrent(t *testing.T) {
+	slots, err := MustRange(Between(tm(9, 0), tm(17, 0))).Split(5*time.Minute, 0)
+	require.NoError(t, err)
+
+	l := NewLedger(NewSet(MustRange(Between(tm(9, 0), tm(17, 0)))))
+
+	var wg sync.WaitGroup
+	booked := make([]bool, len(slots))
+	for i := range slots {
+		for j := 0; j < 3; j++ { // each slot is contended by several clients
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				if err := l.Book(slots[i]); err == nil {
+					assert.False(t, booked[i], "slot is booked twice")
+					booked[i] = true
+				}
+			}(i)
+		}
+	}
+	wg.Wait()
+
+	assert.Empty(t, l.Free().Ranges())
+	for i := range booked {
+		assert.True(t, booked[i])
+	}
+}
@@ -0,0 +1,93 @@
+package trn
+
+import (
+	"sort"
+	"time"
+)
+
+// AvailabilityMatrix is the resource×slot grid of availability, e.g. the
+// one rendered by booking grids.
+type AvailabilityMatrix struct {
+	// Resources are the names of the resources, sorted, in the order of
+	// rows.
+	Resources []string
+	// Slots are the slots of the period, in the order of columns.
+	Slots []Range
+	// Free tells whether the slot is fully free for the resource, indexed
+	// by the row and the column.
+	Free [][]bool
+}
+
+// Matrix splits the period into the slots of the given granularity, the
+// last one might be shorter, and marks the slots, which don't overlap the
+// busy ranges of each resource, as free. It takes O(slots×resources +
+// ranges×log(ranges)) time, instead of checking each cell against each
+// range. Busy ranges may be unsorted, overlapping and lie outside the
+// period. Returns the empty matrix if granularity is not positive.
+// Of the split options, only MaxSlots is taken into account, returns
+// TooManySlotsError, matching ErrTooManySlots, if the number of the slots
+// exceeds it.
+func Matrix(period Range, granularity time.Duration, busyByResource map[string][]Range, opts ...SplitOption) (AvailabilityMatrix, error) {
+	var res AvailabilityMatrix
+	if granularity <= 0 {
+		return res, nil
+	}
+
+	o := newSplitOptions(opts)
+	slots := int64(period.dur / granularity)
+	if period.dur%granularity != 0 {
+		slots++
+	}
+
+	if o.maxSlots > 0 && slots > int64(o.maxSlots) {
+		return AvailabilityMatrix{}, TooManySlotsError{Slots: slots, Max: o.maxSlots}
+	}
+
+	if slots > 0 {
+		res.Slots = make([]Range, 0, prealloc(slots))
+	}
+	for st := period.st; st.Before(period.End()); st = st.Add(granularity) {
+		slot := Range{st: st, dur: granularity}
+		if end := period.End(); slot.End().After(end) {
+			slot.dur = end.Sub(st)
+		}
+		res.Slots = append(res.Slots, slot)
+	}
+
+	for name := range busyByResource {
+		res.Resources = append(res.Resources, name)
+	}
+	sort.Strings(res.Resources)
+
+	cells := make([]bool, len(res.Resources)*len(res.Slots))
+	for i := range cells {
+		cells[i] = true
+	}
+
+	res.Free = make([][]bool, len(res.Resources))
+	for i, name := range res.Resources {
+		row := cells[i*len(res.Slots) : (i+1)*len(res.Slots)]
+		res.Free[i] = row
+
+		for _, busy := range MergeOverlappingRanges(busyByResource[name]) {
+			if busy.dur <= 0 || !busy.Overlaps(period) {
+				continue
+			}
+
+			// slots from the one containing the start up to the one
+			// containing the last instant of the busy range
+			from, to := 0, len(row)-1
+			if busy.st.After(period.st) {
+				from = int(busy.st.Sub(period.st) / granularity)
+			}
+			if end := busy.End(); end.Before(period.End()) {
+				to = int((end.Sub(period.st) - 1) / granularity)
+			}
+			for j := from; j <= to; j++ {
+				row[j] = false
+			}
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,82 @@
+package trn
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatrix(t *testing.T) {
+	period := MustRange(Between(tm(9, 0), tm(12, 30)))
+	m, err := Matrix(period, time.Hour, map[string][]Range{
+		"b": nil,
+		"a": {
+			MustRange(Between(tm(11, 0), tm(12, 0))),
+			MustRange(Between(tm(9, 30), tm(10, 0))),
+			MustRange(Between(tm(9, 45), tm(10, 0))),
+		},
+		"c": {
+			MustRange(Between(tm(8, 0), tm(9, 0))),
+			MustRange(Between(tm(12, 15), tm(13, 0))),
+			MustRange(Between(tm(10, 0), tm(10, 0))),
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"a", "b", "c"}, m.Resources)
+	var slots []string
+	for _, slot := range m.Slots {
+		slots = append(slots, slot.Format("15:04"))
+	}
+	assert.Equal(t, []string{"[09:00, 10:00]", "[10:00, 11:00]", "[11:00, 12:00]", "[12:00, 12:30]"}, slots)
+	assert.Equal(t, [][]bool{
+		{false, true, false, true},
+		{true, true, true, true},
+		{true, true, true, false},
+	}, m.Free)
+
+	m, err = Matrix(period, 0, map[string][]Range{"a": nil})
+	require.NoError(t, err)
+	assert.Equal(t, AvailabilityMatrix{}, m)
+
+	t.Run("max slots", func(t *testing.T) {
+		m, err := Matrix(period, time.Hour, map[string][]Range{"a": nil}, MaxSlots(4))
+		require.NoError(t, err)
+		assert.Len(t, m.Slots, 4)
+
+		_, err = Matrix(period, time.Hour, map[string][]Range{"a": nil}, MaxSlots(3))
+		assert.Equal(t, TooManySlotsError{Slots: 4, Max: 3}, err)
+
+		_, err = Matrix(New(tm(0, 0), math.MaxInt64), time.Nanosecond, nil, MaxSlots(1000))
+		assert.ErrorIs(t, err, ErrTooManySlots)
+	})
+}
+
+func TestMatrix_Naive(t *testing.T) {
+	period := MustRange(Between(dhm(12, 0, 0), dhm(14, 0, 0)))
+	busy := map[string][]Range{}
+	rnd := rand.New(rand.NewSource(42)) //nolint:gosec // not for security purposes
+	for i := 0; i < 200; i++ {
+		name := string(rune('a' + i%7))
+		st := dhm(12, 0, 0).Add(time.Duration(rnd.Int63n(int64(3 * time.Hour)))).Add(-30 * time.Minute)
+		busy[name] = append(busy[name], New(st, time.Duration(rnd.Int63n(int64(20*time.Minute)))))
+	}
+
+	m, err := Matrix(period, 7*time.Minute, busy)
+	require.NoError(t, err)
+	for i, name := range m.Resources {
+		for j, slot := range m.Slots {
+			free := true
+			for _, b := range busy[name] {
+				if b.Overlaps(slot) {
+					free = false
+				}
+			}
+			assert.Equal(t, free, m.Free[i][j], "%s %s", name, slot)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// Package metrics provides adapters to publish availability metrics derived
+// from a trn.Set, e.g. to Prometheus, without depending on any particular
+// metrics library.
+package metrics
+
+import (
+	"github.com/cappuccinotm/trn"
+)
+
+// Gauge is a metric, which value could be set, prometheus.Gauge satisfies
+// it.
+type Gauge interface{ Set(float64) }
+
+// Counter is a metric, which value could only be incremented,
+// prometheus.Counter satisfies it.
+type Counter interface{ Inc() }
+
+// Availability publishes the metrics of the set of free ranges.
+// Nil metrics are not published.
+type Availability struct {
+	// Source returns the current set of free ranges.
+	Source func() trn.Set
+
+	// FreeSeconds is the total free time within the period in seconds.
+	FreeSeconds Gauge
+	// Slots is the number of free ranges within the period.
+	Slots Gauge
+	// Utilization is the ratio of the busy time to the period duration,
+	// within [0, 1].
+	Utilization Gauge
+	// Observations is the number of observations made.
+	Observations Counter
+}
+
+// Observe computes the metrics of the current set within the period and
+// publishes them.
+func (a Availability) Observe(period trn.Range) {
+	free := period.TruncateAll(a.Source().Ranges())
+
+	var total float64
+	for _, r := range free {
+		total += r.Duration().Seconds()
+	}
+
+	if a.FreeSeconds != nil {
+		a.FreeSeconds.Set(total)
+	}
+
+	if a.Slots != nil {
+		a.Slots.Set(float64(len(free)))
+	}
+
+	if a.Utilization != nil {
+		utilization := 0.0
+		if period.Duration() > 0 {
+			utilization = 1 - total/period.Duration().Seconds()
+		}
+		a.Utilization.Set(utilization)
+	}
+
+	if a.Observations != nil {
+		a.Observations.Inc()
+	}
+}
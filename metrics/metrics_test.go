@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cappuccinotm/trn"
+	"github.com/stretchr/testify/assert"
+)
+
+type gauge float64
+
+func (g *gauge) Set(v float64) { *g = gauge(v) }
+
+type counter int
+
+func (c *counter) Inc() { *c++ }
+
+func tm(h, m int) time.Time { return time.Date(2021, 6, 12, h, m, 0, 0, time.UTC) }
+
+func TestAvailability_Observe(t *testing.T) {
+	set := trn.NewSet(
+		trn.MustRange(trn.Between(tm(8, 0), tm(10, 0))),
+		trn.MustRange(trn.Between(tm(12, 0), tm(13, 0))),
+		trn.MustRange(trn.Between(tm(17, 0), tm(19, 0))),
+	)
+
+	var free, slots, utilization gauge
+	var observations counter
+	a := Availability{
+		Source:       func() trn.Set { return set },
+		FreeSeconds:  &free,
+		Slots:        &slots,
+		Utilization:  &utilization,
+		Observations: &observations,
+	}
+
+	a.Observe(trn.MustRange(trn.Between(tm(9, 0), tm(18, 0))))
+	assert.Equal(t, gauge(3*60*60), free)
+	assert.Equal(t, gauge(3), slots)
+	assert.InDelta(t, 1-3.0/9, float64(utilization), 1e-9)
+	assert.Equal(t, counter(1), observations)
+
+	a.Observe(trn.New(tm(14, 0), 0))
+	assert.Equal(t, gauge(0), free)
+	assert.Equal(t, gauge(0), slots)
+	assert.Equal(t, gauge(0), utilization)
+	assert.Equal(t, counter(2), observations)
+
+	assert.NotPanics(t, func() {
+		Availability{Source: func() trn.Set { return set }}.Observe(trn.New(tm(9, 0), time.Hour))
+	})
+}
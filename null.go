@@ -0,0 +1,85 @@
+package trn
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"fmt"
+)
+
+// NullRange represents the Range, which may be absent, e.g. the optional
+// validity period. Like sql.NullTime, it implements sql.Scanner and
+// driver.Valuer, the range is stored in the text form of MarshalText.
+// It's marshaled to JSON null and to the empty text if not valid.
+type NullRange struct {
+	Range
+	Valid bool // Valid is true if Range is not NULL
+}
+
+// Scan implements sql.Scanner.
+func (n *NullRange) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*n = NullRange{}
+		return nil
+	case string:
+		return n.UnmarshalText([]byte(v))
+	case []byte:
+		return n.UnmarshalText(v)
+	default:
+		return fmt.Errorf("%w: can't scan %T", ErrMalformedRange, src)
+	}
+}
+
+// Value implements driver.Valuer.
+func (n NullRange) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	b, err := n.Range.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (n NullRange) MarshalText() ([]byte, error) {
+	if !n.Valid {
+		return []byte{}, nil
+	}
+	return n.Range.MarshalText()
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (n *NullRange) UnmarshalText(b []byte) error {
+	if len(b) == 0 {
+		*n = NullRange{}
+		return nil
+	}
+	if err := n.Range.UnmarshalText(b); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (n NullRange) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return n.Range.MarshalJSON()
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (n *NullRange) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(b, []byte("null")) {
+		*n = NullRange{}
+		return nil
+	}
+	if err := n.Range.UnmarshalJSON(b); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
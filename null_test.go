@@ -0,0 +1,78 @@
+package trn
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNullRange_JSON(t *testing.T) {
+	type policy struct {
+		Name     string    `json:"name"`
+		Validity NullRange `json:"validity"`
+	}
+
+	b, err := json.Marshal(policy{Name: "open-ended"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"open-ended","validity":null}`, string(b))
+
+	var got policy
+	require.NoError(t, json.Unmarshal(b, &got))
+	assert.Equal(t, policy{Name: "open-ended"}, got)
+
+	want := policy{Name: "summer", Validity: NullRange{Range: New(dhm(12, 9, 0), time.Hour), Valid: true}}
+	b, err = json.Marshal(want)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"summer",`+
+		`"validity":{"start":"2021-06-12T09:00:00Z","end":"2021-06-12T10:00:00Z"}}`, string(b))
+
+	got = policy{}
+	require.NoError(t, json.Unmarshal(b, &got))
+	assert.Equal(t, want, got)
+
+	got = policy{Validity: want.Validity}
+	require.NoError(t, json.Unmarshal([]byte(`{"validity":null}`), &got))
+	assert.False(t, got.Validity.Valid)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"validity":{"start":"2021"}}`), &got))
+}
+
+func TestNullRange_SQL(t *testing.T) {
+	var n NullRange
+	v, err := n.Value()
+	require.NoError(t, err)
+	assert.Nil(t, v)
+
+	n = NullRange{Range: New(dhm(12, 9, 0), time.Hour), Valid: true}
+	v, err = n.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "2021-06-12T09:00:00Z/2021-06-12T10:00:00Z", v)
+
+	var got NullRange
+	require.NoError(t, got.Scan(v))
+	assert.Equal(t, n, got)
+
+	got = NullRange{}
+	require.NoError(t, got.Scan([]byte(v.(string))))
+	assert.Equal(t, n, got)
+
+	require.NoError(t, got.Scan(nil))
+	assert.Equal(t, NullRange{}, got)
+
+	assert.ErrorIs(t, got.Scan(42), ErrMalformedRange)
+	assert.Error(t, got.Scan("2021-06-12"))
+	assert.False(t, got.Valid)
+}
+
+func TestNullRange_Text(t *testing.T) {
+	b, err := NullRange{}.MarshalText()
+	require.NoError(t, err)
+	assert.Empty(t, b)
+
+	n := NullRange{Range: New(dhm(12, 9, 0), time.Hour)}
+	require.NoError(t, n.UnmarshalText(nil))
+	assert.Equal(t, NullRange{}, n)
+}
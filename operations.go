@@ -1,6 +1,7 @@
 package trn
 
 import (
+	"context"
 	"sort"
 	"time"
 )
@@ -22,57 +23,250 @@ func Intersection(ranges []Range) Range {
 
 // MergeOverlappingRanges looks in the ranges slice, seeks for overlapping ranges and
 // merges such ranges into the one range.
-func MergeOverlappingRanges(ranges []Range) []Range {
-	var res []Range
+func MergeOverlappingRanges(ranges []Range, opts ...MergeOption) []Range {
+	a := AcquireArena()
+	defer a.Release()
+	return a.MergeOverlappingRanges(nil, ranges, opts...)
+}
+
+// MergeOverlappingRangesContext is like MergeOverlappingRanges, but stops
+// once the context is done, returning nil along with the context's error.
+// The ranges are sorted in chunks, so even the huge inputs are interrupted
+// shortly after the cancellation.
+func MergeOverlappingRangesContext(ctx context.Context, ranges []Range, opts ...MergeOption) ([]Range, error) {
+	a := AcquireArena()
+	defer a.Release()
 
-	boundaries := rangesToBoundaries(ranges)
-	// sorting boundaries by time
-	sort.Slice(boundaries, func(i, j int) bool { return boundaries[i].tm.Before(boundaries[j].tm) })
+	o := newMergeOptions(opts)
+	for _, rng := range ranges {
+		a.bounds = append(a.bounds,
+			boundary{tm: rng.st, typ: boundaryStart},
+			boundary{tm: rng.End(), typ: boundaryEnd},
+		)
+	}
+	if err := a.sortContext(ctx); err != nil {
+		return nil, err
+	}
+	return dropShorter(a.sweepSorted(nil, o.epsilon), o.minDuration), nil
+}
 
-	// add first boundary
-	var rangeStartTm time.Time
-	unfinishedBoundariesCnt := 0
+// InsertMerged merges the range into the sorted ranges, which are already
+// merged, e.g. by MergeOverlappingRanges, in O(log n + k) time, where k is
+// the number of ranges overlapping or touching the new one. Like append, it
+// reuses the given slice, so it must not be used afterwards.
+func InsertMerged(sorted []Range, r Range) []Range {
+	// the first range, which ends not before the new one starts
+	i := sort.Search(len(sorted), func(i int) bool { return !sorted[i].End().Before(r.st) })
+	// the first range, which starts after the new one ends
+	j := i + sort.Search(len(sorted)-i, func(k int) bool { return sorted[i+k].st.After(r.End()) })
 
-	// skip last boundary to allow looking ahead
-	for i := 0; i < len(boundaries)-1; i++ {
-		boundary := boundaries[i]
+	if i == j {
+		sorted = append(sorted, Range{})
+		copy(sorted[i+1:], sorted[i:])
+		sorted[i] = r
+		return sorted
+	}
 
-		if boundary.typ == boundaryStart {
-			if unfinishedBoundariesCnt == 0 {
-				rangeStartTm = boundary.tm
-			}
-			unfinishedBoundariesCnt++
-			continue
+	st, end := r.st, r.End()
+	if sorted[i].st.Before(st) {
+		st = sorted[i].st
+	}
+	if sorted[j-1].End().After(end) {
+		end = sorted[j-1].End()
+	}
+
+	sorted[i] = Range{st: st, dur: end.Sub(st)}
+	return append(sorted[:i+1], sorted[j:]...)
+}
+
+// SortRanges sorts the ranges by start and the ones starting at the same
+// instant by end. The sort is stable, so the ranges with equal boundaries,
+// e.g. in different locations, keep their order. It's the order of ranges
+// returned by the functions of the package.
+func SortRanges(ranges []Range) {
+	sort.SliceStable(ranges, func(i, j int) bool { return lessRange(ranges[i], ranges[j]) })
+}
+
+// lessRange orders the ranges by start, then by end.
+func lessRange(a, b Range) bool {
+	if !a.st.Equal(b.st) {
+		return a.st.Before(b.st)
+	}
+	return a.dur < b.dur
+}
+
+// CloseGaps merges the overlapping ranges and the ones separated by the gaps
+// not longer than maxGap, e.g. to show "busy all afternoon" ignoring short
+// breaks. It's a shorthand for MergeOverlappingRanges with Epsilon.
+func CloseGaps(ranges []Range, maxGap time.Duration) []Range {
+	return MergeOverlappingRanges(ranges, Epsilon(maxGap))
+}
+
+// MergeOption configures the merge of ranges.
+type MergeOption func(o *mergeOptions)
+
+type mergeOptions struct {
+	epsilon     time.Duration
+	minDuration time.Duration
+}
+
+// Epsilon makes the merge treat the end of one range and the start of
+// the next one as equal if the gap between them is not longer than eps,
+// e.g. for the ranges made of float timestamps with jitter. Such ranges
+// are merged instead of leaving micro-gaps between them.
+func Epsilon(eps time.Duration) MergeOption {
+	return func(o *mergeOptions) { o.epsilon = eps }
+}
+
+// MinDuration drops the resulting ranges shorter than d, e.g. free slots,
+// which are too short to book anything.
+func MinDuration(d time.Duration) MergeOption {
+	return func(o *mergeOptions) { o.minDuration = d }
+}
+
+func newMergeOptions(opts []MergeOption) mergeOptions {
+	if len(opts) == 0 {
+		// options escape to heap, don't allocate them without need
+		return mergeOptions{}
+	}
+
+	var o mergeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.epsilon < 0 {
+		o.epsilon = 0
+	}
+	return o
+}
+
+// DropShorterThan returns the ranges, which are not shorter than min,
+// keeping their order. The given slice is not modified.
+func DropShorterThan(ranges []Range, min time.Duration) []Range {
+	return dropShorter(append([]Range(nil), ranges...), min)
+}
+
+// dropShorter drops the ranges shorter than min in place.
+func dropShorter(ranges []Range, min time.Duration) []Range {
+	if min <= 0 {
+		return ranges
+	}
+
+	res := ranges[:0]
+	for _, r := range ranges {
+		if r.dur >= min {
+			res = append(res, r)
 		}
+	}
+	return res
+}
 
-		nextBoundary := boundaries[i+1]
-		// if current and previous boundaries are equal - ignore them
-		if boundary.tm.Equal(nextBoundary.tm) && nextBoundary.typ == boundaryStart {
-			i++
-			continue
+// UncoveredParts returns the parts of the period, which are not covered by
+// the union of the ranges, e.g. gaps in the shift plan.
+// Ranges may be unsorted, overlapping and lie outside the period.
+func UncoveredParts(period Range, ranges []Range) []Range {
+	var res []Range
+	for _, part := range period.Exclude(ranges) {
+		if part.dur > 0 {
+			res = append(res, part)
 		}
+	}
+	return res
+}
 
-		unfinishedBoundariesCnt--
-		// if this is an ending boundary and there is where the merged range ends...
-		if unfinishedBoundariesCnt == 0 {
-			res = append(res, Range{st: rangeStartTm, dur: boundary.tm.Sub(rangeStartTm)})
+// Covers returns true if the period is fully covered by the union of the
+// ranges. The period of zero duration is always covered.
+func Covers(period Range, ranges []Range) bool { return len(UncoveredParts(period, ranges)) == 0 }
+
+// ClipPastSet returns the parts of the ranges, which are not before now,
+// dropping the ranges, which have ended by now. The order of ranges is
+// kept.
+func ClipPastSet(ranges []Range, now time.Time) []Range {
+	var res []Range
+	for _, r := range ranges {
+		if clipped := r.ClipPast(now); clipped.dur > 0 {
+			res = append(res, clipped)
 		}
 	}
+	return res
+}
 
-	// process the last boundary, it must be the end boundary anyway
-	unfinishedBoundariesCnt--
-	if unfinishedBoundariesCnt == 0 {
-		res = append(res, Range{st: rangeStartTm, dur: boundaries[len(boundaries)-1].tm.Sub(rangeStartTm)})
+// Downsample splits the time into buckets of the given duration, aligned to
+// the zero time, and returns the union of the buckets, covered by the
+// ranges more than the threshold fraction of the bucket, e.g. to preview
+// the busy time with hourly cells. Returns nil if bucket is not positive.
+func Downsample(ranges []Range, bucket time.Duration, threshold float64) []Range {
+	if bucket <= 0 {
+		return nil
 	}
 
-	return res
+	var res []Range
+	var cur time.Time
+	var covered time.Duration
+	started := false
+
+	flush := func() {
+		if started && float64(covered) > threshold*float64(bucket) {
+			res = append(res, Range{st: cur, dur: bucket})
+		}
+	}
+
+	for _, r := range MergeOverlappingRanges(ranges) {
+		for st := r.st; st.Before(r.End()); {
+			b := st.Truncate(bucket)
+			if !started || !b.Equal(cur) {
+				flush()
+				cur, covered, started = b, 0, true
+			}
+
+			end := b.Add(bucket)
+			if r.End().Before(end) {
+				end = r.End()
+			}
+
+			covered += end.Sub(st)
+			st = end
+		}
+	}
+	flush()
+
+	if len(res) == 0 {
+		return nil
+	}
+
+	return MergeOverlappingRanges(res)
+}
+
+// RangesFromPairs converts the flat list of boundaries into ranges, where
+// each even element is the start of the range and each odd one is its end.
+// Returns ErrOddBoundaries if the number of times is odd and
+// ErrUnsortedBoundaries if the times are not sorted in ascending order.
+func RangesFromPairs(times []time.Time) ([]Range, error) {
+	if len(times)%2 != 0 {
+		return nil, ErrOddBoundaries
+	}
+
+	res := make([]Range, 0, len(times)/2)
+	for i := 0; i < len(times); i += 2 {
+		if i > 0 && times[i].Before(times[i-1]) {
+			return nil, ErrUnsortedBoundaries
+		}
+		if times[i].After(times[i+1]) {
+			return nil, ErrUnsortedBoundaries
+		}
+		res = append(res, Range{st: times[i], dur: times[i+1].Sub(times[i])})
+	}
+
+	return res, nil
 }
 
-func rangesToBoundaries(ranges []Range) []*boundary {
-	res := make([]*boundary, len(ranges)*2)
-	for i, rng := range ranges {
-		res[i*2] = &boundary{tm: rng.st, typ: boundaryStart}
-		res[i*2+1] = &boundary{tm: rng.End(), typ: boundaryEnd}
+// Boundaries returns the flat list of the ranges' boundaries, i.e. the start
+// and the end of each range in the order of ranges. It is the inverse of
+// RangesFromPairs.
+func Boundaries(ranges []Range) []time.Time {
+	res := make([]time.Time, 0, len(ranges)*2)
+	for _, rng := range ranges {
+		res = append(res, rng.st, rng.End())
 	}
 	return res
 }
@@ -89,6 +283,13 @@ type boundary struct {
 	typ boundaryType
 }
 
+// boundaries implements sort.Interface to sort boundaries by time.
+type boundaries []boundary
+
+func (b *boundaries) Len() int           { return len(*b) }
+func (b *boundaries) Less(i, j int) bool { return (*b)[i].tm.Before((*b)[j].tm) }
+func (b *boundaries) Swap(i, j int)      { (*b)[i], (*b)[j] = (*b)[j], (*b)[i] }
+
 // MustRanges is a helper that accepts the result of function, that returns
 // ranges and panics, if err is returned.
 func MustRanges(r []Range, err error) []Range {
@@ -1,10 +1,14 @@
 package trn
 
 import (
+	"context"
 	"errors"
-	"github.com/stretchr/testify/assert"
+	"math/rand"
 	"testing"
 	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMustRange(t *testing.T) {
@@ -32,6 +36,24 @@ func TestMustRanges(t *testing.T) {
 	})
 }
 
+func TestSortRanges(t *testing.T) {
+	berlin := time.FixedZone("UTC+2", 2*60*60)
+	rngs := []Range{
+		MustRange(Between(tm(13, 0), tm(15, 0))),
+		MustRange(Between(tm(12, 0), tm(13, 0))),
+		MustRange(Between(tm(13, 0), tm(14, 0))).In(berlin),
+		MustRange(Between(tm(13, 0), tm(14, 0))),
+	}
+	SortRanges(rngs)
+
+	assert.Equal(t, []Range{
+		MustRange(Between(tm(12, 0), tm(13, 0))),
+		MustRange(Between(tm(13, 0), tm(14, 0))).In(berlin),
+		MustRange(Between(tm(13, 0), tm(14, 0))),
+		MustRange(Between(tm(13, 0), tm(15, 0))),
+	}, rngs)
+}
+
 func TestMergeOverlappingRanges(t *testing.T) {
 	tests := []struct {
 		name string
@@ -133,6 +155,151 @@ func TestMergeOverlappingRanges(t *testing.T) {
 	}
 }
 
+func TestMergeOverlappingRanges_Epsilon(t *testing.T) {
+	rngs := []Range{
+		MustRange(Between(tm(9, 0), tm(10, 0).Add(-3))),
+		MustRange(Between(tm(10, 0), tm(11, 0).Add(-10))),
+		MustRange(Between(tm(11, 0), tm(12, 0))),
+	}
+
+	assert.Len(t, MergeOverlappingRanges(rngs), 3)
+	assert.Equal(t,
+		[]Range{MustRange(Between(tm(9, 0), tm(11, 0).Add(-10))), MustRange(Between(tm(11, 0), tm(12, 0)))},
+		MergeOverlappingRanges(rngs, Epsilon(5)),
+	)
+	assert.Equal(t,
+		[]Range{MustRange(Between(tm(9, 0), tm(12, 0)))},
+		MergeOverlappingRanges(rngs, Epsilon(10)),
+	)
+	assert.Len(t, MergeOverlappingRanges(rngs, Epsilon(-10)), 3)
+}
+
+func TestMergeOverlappingRanges_MinDuration(t *testing.T) {
+	rngs := []Range{
+		MustRange(Between(tm(9, 0), tm(9, 3))),
+		MustRange(Between(tm(10, 0), tm(10, 3))),
+		MustRange(Between(tm(10, 2), tm(10, 6))),
+		MustRange(Between(tm(11, 0), tm(12, 0))),
+	}
+
+	assert.Equal(t, []Range{
+		MustRange(Between(tm(10, 0), tm(10, 6))),
+		MustRange(Between(tm(11, 0), tm(12, 0))),
+	}, MergeOverlappingRanges(rngs, MinDuration(5*time.Minute)))
+}
+
+func TestMergeOverlappingRangesContext(t *testing.T) {
+	// more ranges than fit into a single sorted chunk
+	rnd := rand.New(rand.NewSource(1)) //nolint:gosec // not for security purposes
+	rngs := make([]Range, 3*sortChunk)
+	for i := range rngs {
+		rngs[i] = New(tm(0, 0).Add(time.Duration(rnd.Int63n(int64(24*time.Hour)))),
+			time.Duration(rnd.Int63n(int64(time.Second))))
+	}
+
+	got, err := MergeOverlappingRangesContext(context.Background(), rngs, MinDuration(time.Millisecond))
+	require.NoError(t, err)
+	assert.Equal(t, MergeOverlappingRanges(rngs, MinDuration(time.Millisecond)), got)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	got, err = MergeOverlappingRangesContext(ctx, rngs)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, got)
+}
+
+func TestDropShorterThan(t *testing.T) {
+	rngs := []Range{
+		MustRange(Between(tm(11, 0), tm(12, 0))),
+		MustRange(Between(tm(9, 0), tm(9, 3))),
+		MustRange(Between(tm(10, 0), tm(10, 5))),
+	}
+
+	assert.Equal(t, []Range{rngs[0], rngs[2]}, DropShorterThan(rngs, 5*time.Minute))
+	assert.Len(t, rngs, 3, "the source is not modified")
+	assert.Equal(t, rngs, DropShorterThan(rngs, 0))
+	assert.Empty(t, DropShorterThan(rngs, 2*time.Hour))
+}
+
+func TestInsertMerged(t *testing.T) {
+	sorted := func() []Range {
+		return []Range{
+			MustRange(Between(tm(9, 0), tm(10, 0))),
+			MustRange(Between(tm(11, 0), tm(12, 0))),
+			MustRange(Between(tm(14, 0), tm(15, 0))),
+		}
+	}
+
+	tests := []struct {
+		name string
+		rng  Range
+		want []Range
+	}{
+		{
+			name: "before all",
+			rng:  MustRange(Between(tm(7, 0), tm(8, 0))),
+			want: append([]Range{MustRange(Between(tm(7, 0), tm(8, 0)))}, sorted()...),
+		},
+		{
+			name: "after all",
+			rng:  MustRange(Between(tm(16, 0), tm(17, 0))),
+			want: append(sorted(), MustRange(Between(tm(16, 0), tm(17, 0)))),
+		},
+		{
+			name: "in the gap",
+			rng:  MustRange(Between(tm(12, 30), tm(13, 0))),
+			want: []Range{
+				MustRange(Between(tm(9, 0), tm(10, 0))),
+				MustRange(Between(tm(11, 0), tm(12, 0))),
+				MustRange(Between(tm(12, 30), tm(13, 0))),
+				MustRange(Between(tm(14, 0), tm(15, 0))),
+			},
+		},
+		{
+			name: "touching both neighbours",
+			rng:  MustRange(Between(tm(10, 0), tm(11, 0))),
+			want: []Range{
+				MustRange(Between(tm(9, 0), tm(12, 0))),
+				MustRange(Between(tm(14, 0), tm(15, 0))),
+			},
+		},
+		{
+			name: "covering several",
+			rng:  MustRange(Between(tm(9, 30), tm(14, 30))),
+			want: []Range{MustRange(Between(tm(9, 0), tm(15, 0)))},
+		},
+		{
+			name: "within one",
+			rng:  MustRange(Between(tm(11, 10), tm(11, 20))),
+			want: sorted(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := InsertMerged(sorted(), tt.rng)
+			assert.Equal(t, formattedRanges(tt.want, "15:04"), formattedRanges(got, "15:04"))
+			assert.Equal(t, MergeOverlappingRanges(append(sorted(), tt.rng)), got)
+		})
+	}
+
+	assert.Equal(t, []Range{New(tm(9, 0), time.Hour)}, InsertMerged(nil, New(tm(9, 0), time.Hour)))
+}
+
+func TestCloseGaps(t *testing.T) {
+	rngs := []Range{
+		MustRange(Between(tm(13, 0), tm(14, 0))),
+		MustRange(Between(tm(14, 5), tm(15, 0))),
+		MustRange(Between(tm(15, 10), tm(16, 0))),
+		MustRange(Between(tm(16, 30), tm(17, 0))),
+	}
+
+	assert.Equal(t, []Range{
+		MustRange(Between(tm(13, 0), tm(16, 0))),
+		MustRange(Between(tm(16, 30), tm(17, 0))),
+	}, CloseGaps(rngs, 10*time.Minute))
+	assert.Len(t, CloseGaps(rngs, 0), 4)
+}
+
 func TestIntersection(t *testing.T) {
 	tests := []struct {
 		name string
@@ -161,3 +328,143 @@ func TestIntersection(t *testing.T) {
 		})
 	}
 }
+
+func TestRangesFromPairs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []time.Time
+		want    []Range
+		wantErr error
+	}{
+		{name: "empty list", args: nil, want: []Range{}},
+		{
+			name: "sorted pairs",
+			args: []time.Time{tm(9, 0), tm(12, 0), tm(12, 0), tm(13, 0), tm(14, 0), tm(18, 0)},
+			want: []Range{
+				MustRange(Between(tm(9, 0), tm(12, 0))),
+				MustRange(Between(tm(12, 0), tm(13, 0))),
+				MustRange(Between(tm(14, 0), tm(18, 0))),
+			},
+		},
+		{name: "odd number", args: []time.Time{tm(9, 0), tm(12, 0), tm(13, 0)}, wantErr: ErrOddBoundaries},
+		{name: "end before start", args: []time.Time{tm(12, 0), tm(9, 0)}, wantErr: ErrUnsortedBoundaries},
+		{
+			name:    "pairs unsorted",
+			args:    []time.Time{tm(12, 0), tm(13, 0), tm(9, 0), tm(10, 0)},
+			wantErr: ErrUnsortedBoundaries,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RangesFromPairs(tt.args)
+			assert.Equal(t, formattedRanges(tt.want, "15:04"), formattedRanges(got, "15:04"))
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestBoundaries(t *testing.T) {
+	times := []time.Time{tm(9, 0), tm(12, 0), tm(14, 0), tm(18, 0)}
+	assert.Equal(t, times, Boundaries(MustRanges(RangesFromPairs(times))))
+	assert.Empty(t, Boundaries(nil))
+}
+
+func TestCovers(t *testing.T) {
+	period := MustRange(Between(tm(9, 0), tm(18, 0)))
+
+	tests := []struct {
+		name      string
+		ranges    []Range
+		uncovered []Range
+	}{
+		{name: "no ranges", uncovered: []Range{period}},
+		{
+			name: "adjacent and overlapping shifts",
+			ranges: []Range{
+				MustRange(Between(tm(13, 0), tm(19, 0))),
+				MustRange(Between(tm(8, 0), tm(12, 0))),
+				MustRange(Between(tm(11, 0), tm(13, 0))),
+			},
+		},
+		{
+			name: "gaps",
+			ranges: []Range{
+				MustRange(Between(tm(9, 30), tm(12, 0))),
+				MustRange(Between(tm(13, 0), tm(17, 0))),
+			},
+			uncovered: []Range{
+				MustRange(Between(tm(9, 0), tm(9, 30))),
+				MustRange(Between(tm(12, 0), tm(13, 0))),
+				MustRange(Between(tm(17, 0), tm(18, 0))),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := UncoveredParts(period, tt.ranges)
+			assert.Equal(t, formattedRanges(tt.uncovered, "15:04"), formattedRanges(got, "15:04"))
+			assert.Equal(t, len(tt.uncovered) == 0, Covers(period, tt.ranges))
+		})
+	}
+
+	assert.True(t, Covers(New(tm(9, 0), 0), nil))
+}
+
+func TestClipPastSet(t *testing.T) {
+	rngs := []Range{
+		MustRange(Between(tm(15, 0), tm(16, 0))),
+		MustRange(Between(tm(9, 0), tm(10, 0))),
+		MustRange(Between(tm(11, 0), tm(13, 0))),
+		MustRange(Between(tm(10, 0), tm(12, 0))),
+	}
+	assert.Equal(t, []Range{
+		MustRange(Between(tm(15, 0), tm(16, 0))),
+		MustRange(Between(tm(12, 0), tm(13, 0))),
+	}, ClipPastSet(rngs, tm(12, 0)))
+	assert.Empty(t, ClipPastSet(rngs, tm(16, 0)))
+}
+
+func TestDownsample(t *testing.T) {
+	ranges := []Range{
+		MustRange(Between(tm(9, 10), tm(9, 50))),   // 2/3 of 9:00
+		MustRange(Between(tm(10, 40), tm(11, 10))), // 1/3 of 10:00 and 1/6 of 11:00
+		MustRange(Between(tm(11, 50), tm(14, 0))),  // 1/6 of 11:00, whole 12:00 and 13:00
+		MustRange(Between(tm(15, 0), tm(15, 15))),  // 1/4 of 15:00
+	}
+
+	tests := []struct {
+		name      string
+		threshold float64
+		want      []Range
+	}{
+		{
+			name:      "any coverage",
+			threshold: 0,
+			want:      []Range{MustRange(Between(tm(9, 0), tm(14, 0))), MustRange(Between(tm(15, 0), tm(16, 0)))},
+		},
+		{
+			name:      "more than a quarter",
+			threshold: 0.25,
+			want:      []Range{MustRange(Between(tm(9, 0), tm(14, 0)))},
+		},
+		{
+			name:      "more than a half",
+			threshold: 0.5,
+			want:      []Range{MustRange(Between(tm(9, 0), tm(10, 0))), MustRange(Between(tm(12, 0), tm(14, 0)))},
+		},
+		{
+			name:      "almost fully covered",
+			threshold: 0.99,
+			want:      []Range{MustRange(Between(tm(12, 0), tm(14, 0)))},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Downsample(ranges, time.Hour, tt.threshold)
+			assert.Equal(t, formattedRanges(tt.want, "15:04"), formattedRanges(got, "15:04"))
+		})
+	}
+
+	assert.Nil(t, Downsample(ranges, 0, 0.5))
+	assert.Nil(t, Downsample(nil, time.Hour, 0.5))
+}
@@ -0,0 +1,68 @@
+// Package otel provides helpers to trace the scheduling operations over
+// trn ranges, e.g. with OpenTelemetry, without depending on any particular
+// tracing library.
+package otel
+
+import (
+	"context"
+	"time"
+
+	"github.com/cappuccinotm/trn"
+)
+
+// Attribute keys.
+const (
+	KeyStart      = "range.start"
+	KeyEnd        = "range.end"
+	KeyDurationMs = "range.duration_ms"
+	KeySlots      = "slots.count"
+	KeyError      = "error.message"
+)
+
+// KeyValue is the attribute of the span. Values are either strings or
+// int64, so they could be converted into attribute.String and
+// attribute.Int64 of OpenTelemetry.
+type KeyValue struct {
+	Key   string
+	Value interface{}
+}
+
+// Attributes returns the attributes of the range, the boundaries are
+// formatted in RFC 3339 with nanoseconds.
+func Attributes(r trn.Range) []KeyValue {
+	return []KeyValue{
+		{Key: KeyStart, Value: r.Start().Format(time.RFC3339Nano)},
+		{Key: KeyEnd, Value: r.End().Format(time.RFC3339Nano)},
+		{Key: KeyDurationMs, Value: r.Duration().Milliseconds()},
+	}
+}
+
+// Span is the started span, an adapter over trace.Span of OpenTelemetry.
+type Span interface {
+	SetAttributes(kvs ...KeyValue)
+	End()
+}
+
+// StartFunc starts the span with the given name, e.g. with trace.Tracer
+// of OpenTelemetry.
+type StartFunc func(ctx context.Context, name string) (context.Context, Span)
+
+// Search records the slot search operation as the span with the given
+// name, annotated with the attributes of the period and the number of the
+// found slots, or the error message, if the search fails.
+func Search(ctx context.Context, start StartFunc, name string, period trn.Range,
+	find func(ctx context.Context) ([]trn.Range, error)) ([]trn.Range, error) {
+	ctx, span := start(ctx, name)
+	defer span.End()
+
+	span.SetAttributes(Attributes(period)...)
+
+	res, err := find(ctx)
+	if err != nil {
+		span.SetAttributes(KeyValue{Key: KeyError, Value: err.Error()})
+		return res, err
+	}
+
+	span.SetAttributes(KeyValue{Key: KeySlots, Value: int64(len(res))})
+	return res, nil
+}
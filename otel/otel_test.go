@@ -0,0 +1,60 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cappuccinotm/trn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var dt = time.Date(2021, 6, 12, 13, 0, 0, 0, time.UTC)
+
+type testSpan struct {
+	name  string
+	attrs []KeyValue
+	ended bool
+}
+
+func (s *testSpan) SetAttributes(kvs ...KeyValue) { s.attrs = append(s.attrs, kvs...) }
+func (s *testSpan) End()                          { s.ended = true }
+
+func TestAttributes(t *testing.T) {
+	assert.Equal(t, []KeyValue{
+		{Key: "range.start", Value: "2021-06-12T13:00:00Z"},
+		{Key: "range.end", Value: "2021-06-12T14:30:00.5Z"},
+		{Key: "range.duration_ms", Value: int64(5400500)},
+	}, Attributes(trn.New(dt, 90*time.Minute+500*time.Millisecond)))
+}
+
+func TestSearch(t *testing.T) {
+	period := trn.New(dt, 2*time.Hour)
+
+	var spans []*testSpan
+	start := func(ctx context.Context, name string) (context.Context, Span) {
+		s := &testSpan{name: name}
+		spans = append(spans, s)
+		return ctx, s
+	}
+
+	slots, err := Search(context.Background(), start, "find-slots", period,
+		func(context.Context) ([]trn.Range, error) { return period.Split(30*time.Minute, 0) })
+	require.NoError(t, err)
+	assert.Len(t, slots, 4)
+
+	require.Len(t, spans, 1)
+	assert.Equal(t, "find-slots", spans[0].name)
+	assert.True(t, spans[0].ended)
+	assert.Equal(t, append(Attributes(period), KeyValue{Key: KeySlots, Value: int64(4)}), spans[0].attrs)
+
+	_, err = Search(context.Background(), start, "find-slots", period,
+		func(context.Context) ([]trn.Range, error) { return nil, errors.New("no calendar") })
+	assert.EqualError(t, err, "no calendar")
+
+	require.Len(t, spans, 2)
+	assert.True(t, spans[1].ended)
+	assert.Equal(t, KeyValue{Key: KeyError, Value: "no calendar"}, spans[1].attrs[len(spans[1].attrs)-1])
+}
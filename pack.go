@@ -0,0 +1,194 @@
+package trn
+
+import (
+	"sort"
+	"time"
+)
+
+// Assignment is the placement of the demand in the free set.
+type Assignment struct {
+	// Demand is the index of the demand in the list passed to Pack.
+	Demand int
+	Range  Range
+}
+
+// PackOption configures Pack.
+type PackOption func(o *packOptions)
+
+type packOptions struct {
+	maxSteps int
+	travel   func(from, to int) time.Duration
+}
+
+// MaxSteps limits the number of placements tried by Pack while looking for
+// the one, which satisfies all demands, 10000 by default. Once the limit is
+// exceeded, Pack returns the greedy placement.
+func MaxSteps(n int) PackOption {
+	return func(o *packOptions) { o.maxSteps = n }
+}
+
+// TravelTime makes Pack keep the gap between the consecutive demands placed
+// within the same free range, e.g. for the travel or setup time between the
+// visits. The function receives the indices of the demands and returns the
+// required gap between the end of the first one and the start of the next.
+// Negative gaps are treated as zero, so the demands never overlap. Pack
+// doesn't reorder the demands to shorten the gaps, see Pack for details.
+func TravelTime(fn func(from, to int) time.Duration) PackOption {
+	return func(o *packOptions) { o.travel = fn }
+}
+
+// MinGap makes Pack keep at least the given gap between the consecutive
+// demands placed within the same free range, negative gap is treated as
+// zero.
+func MinGap(d time.Duration) PackOption {
+	return TravelTime(func(int, int) time.Duration { return d })
+}
+
+// Pack places the demanded durations into the free set without overlaps,
+// e.g. to schedule several meetings into the free time of the day.
+// The longest demands are placed first at the earliest free time, fitting
+// them. If that doesn't satisfy all demands, the solver backtracks to find
+// the placement, which does. If it's not found, or not found within
+// MaxSteps, the greedy placement is returned along with the indices of
+// demands, which don't fit. Non-positive demands are never satisfied.
+// Assignments are sorted by their start.
+// Within the free range, each demand is placed right after the previously
+// placed one, keeping the gap required by TravelTime, if any. The solver
+// tries only the longest-first order of demands within the free range, so
+// with the asymmetric TravelTime it may miss the placement, which needs the
+// other order, e.g. the short visit before the long one.
+func Pack(free []Range, demands []time.Duration, opts ...PackOption) (assigned []Assignment, unsatisfied []int) {
+	o := packOptions{maxSteps: 10000}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	// longest first, the order of equal demands is kept
+	order := make([]int, 0, len(demands))
+	for i, d := range demands {
+		if d <= 0 {
+			unsatisfied = append(unsatisfied, i)
+			continue
+		}
+		order = append(order, i)
+	}
+	sort.SliceStable(order, func(i, j int) bool { return demands[order[i]] > demands[order[j]] })
+
+	p := packer{demands: demands, order: order, steps: o.maxSteps, travel: o.travel}
+
+	merged := MergeOverlappingRanges(free)
+	segs := make([]packSegment, len(merged))
+	for i, rng := range merged {
+		segs[i] = packSegment{Range: rng, prev: -1}
+	}
+
+	if p.solve(segs, 0) {
+		assigned = p.placed
+	} else {
+		var rest []int
+		assigned, rest = p.greedy(segs)
+		unsatisfied = append(unsatisfied, rest...)
+	}
+
+	sort.SliceStable(assigned, func(i, j int) bool { return lessRange(assigned[i].Range, assigned[j].Range) })
+	sort.Ints(unsatisfied)
+	return assigned, unsatisfied
+}
+
+// packer keeps the state of Pack.
+type packer struct {
+	demands []time.Duration
+	order   []int
+	steps   int
+	travel  func(from, to int) time.Duration
+	placed  []Assignment
+}
+
+// packSegment is the free part of the range after the placed demand with
+// the given index, -1 if there is no such demand.
+type packSegment struct {
+	Range
+	prev int
+}
+
+// solve places the demands from the n-th one in order into the free
+// segments, backtracking if any of them doesn't fit. Returns true if all
+// demands are placed.
+func (p *packer) solve(segs []packSegment, n int) bool {
+	if n == len(p.order) {
+		return true
+	}
+
+	idx := p.order[n]
+	for i, seg := range segs {
+		if p.steps <= 0 {
+			return false
+		}
+
+		rng, rest, ok := p.fit(seg, idx)
+		if !ok {
+			continue
+		}
+		p.steps--
+
+		next := make([]packSegment, len(segs))
+		copy(next, segs)
+		next[i] = rest
+
+		p.placed = append(p.placed, Assignment{Demand: idx, Range: rng})
+		if p.solve(next, n+1) {
+			return true
+		}
+		p.placed = p.placed[:len(p.placed)-1]
+	}
+
+	return false
+}
+
+// greedy places each demand in order at the earliest segment, where it
+// fits, and returns the indices of the demands, which don't fit anywhere.
+func (p *packer) greedy(segs []packSegment) (assigned []Assignment, unsatisfied []int) {
+	segs = append([]packSegment(nil), segs...)
+
+	for _, idx := range p.order {
+		placed := false
+		for i, seg := range segs {
+			rng, rest, ok := p.fit(seg, idx)
+			if !ok {
+				continue
+			}
+
+			segs[i] = rest
+			assigned = append(assigned, Assignment{Demand: idx, Range: rng})
+			placed = true
+			break
+		}
+
+		if !placed {
+			unsatisfied = append(unsatisfied, idx)
+		}
+	}
+
+	return assigned, unsatisfied
+}
+
+// fit places the demand at the start of the segment, keeping the travel
+// time from the previous demand, and returns the placed range and the rest
+// of the segment. Returns false if the demand doesn't
+// fit into the segment.
+func (p *packer) fit(seg packSegment, idx int) (rng Range, rest packSegment, ok bool) {
+	st := seg.st
+	if p.travel != nil && seg.prev >= 0 {
+		if gap := p.travel(seg.prev, idx); gap > 0 {
+			st = st.Add(gap)
+		}
+	}
+
+	d := p.demands[idx]
+	if seg.End().Sub(st) < d {
+		return Range{}, packSegment{}, false
+	}
+
+	rng = Range{st: st, dur: d}
+	return rng, packSegment{Range: Range{st: rng.End(), dur: seg.End().Sub(rng.End())}, prev: idx}, true
+}
@@ -0,0 +1,98 @@
+package trn
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPack(t *testing.T) {
+	free := []Range{
+		MustRange(Between(tm(11, 0), tm(11, 40))),
+		MustRange(Between(tm(9, 0), tm(10, 0))),
+	}
+
+	t.Run("backtracks when greedy fails", func(t *testing.T) {
+		assigned, unsatisfied := Pack(free, []time.Duration{30 * time.Minute, 40 * time.Minute, 30 * time.Minute})
+		assert.Empty(t, unsatisfied)
+		assert.Equal(t, []Assignment{
+			{Demand: 0, Range: MustRange(Between(tm(9, 0), tm(9, 30)))},
+			{Demand: 2, Range: MustRange(Between(tm(9, 30), tm(10, 0)))},
+			{Demand: 1, Range: MustRange(Between(tm(11, 0), tm(11, 40)))},
+		}, assigned)
+	})
+
+	t.Run("greedy when limited", func(t *testing.T) {
+		assigned, unsatisfied := Pack(free, []time.Duration{30 * time.Minute, 40 * time.Minute, 30 * time.Minute},
+			MaxSteps(2))
+		assert.Equal(t, []int{2}, unsatisfied)
+		assert.Equal(t, []Assignment{
+			{Demand: 1, Range: MustRange(Between(tm(9, 0), tm(9, 40)))},
+			{Demand: 0, Range: MustRange(Between(tm(11, 0), tm(11, 30)))},
+		}, assigned)
+	})
+
+	t.Run("unsatisfiable", func(t *testing.T) {
+		assigned, unsatisfied := Pack(free, []time.Duration{time.Hour, 0, 2 * time.Hour, 20 * time.Minute})
+		assert.Equal(t, []int{1, 2}, unsatisfied)
+		assert.Equal(t, []Assignment{
+			{Demand: 0, Range: MustRange(Between(tm(9, 0), tm(10, 0)))},
+			{Demand: 3, Range: MustRange(Between(tm(11, 0), tm(11, 20)))},
+		}, assigned)
+	})
+
+	t.Run("nothing to place", func(t *testing.T) {
+		assigned, unsatisfied := Pack(free, nil)
+		assert.Empty(t, assigned)
+		assert.Empty(t, unsatisfied)
+	})
+}
+
+func TestPack_TravelTime(t *testing.T) {
+	free := []Range{MustRange(Between(tm(9, 0), tm(12, 0)))}
+	demands := []time.Duration{time.Hour, time.Hour, 30 * time.Minute}
+
+	t.Run("min gap", func(t *testing.T) {
+		assigned, unsatisfied := Pack(free, demands, MinGap(15*time.Minute))
+		assert.Empty(t, unsatisfied)
+		assert.Equal(t, []Assignment{
+			{Demand: 0, Range: MustRange(Between(tm(9, 0), tm(10, 0)))},
+			{Demand: 1, Range: MustRange(Between(tm(10, 15), tm(11, 15)))},
+			{Demand: 2, Range: MustRange(Between(tm(11, 30), tm(12, 0)))},
+		}, assigned)
+	})
+
+	t.Run("depends on the demands", func(t *testing.T) {
+		// the third demand is far away from the others
+		travel := func(from, to int) time.Duration {
+			if from == 2 || to == 2 {
+				return time.Hour
+			}
+			return 0
+		}
+
+		assigned, unsatisfied := Pack(free, demands, TravelTime(travel))
+		assert.Equal(t, []int{2}, unsatisfied)
+		assert.Len(t, assigned, 2)
+
+		assigned, unsatisfied = Pack(append(free, MustRange(Between(tm(13, 0), tm(14, 0)))), demands,
+			TravelTime(travel))
+		assert.Empty(t, unsatisfied)
+		assert.Equal(t, []Assignment{
+			{Demand: 0, Range: MustRange(Between(tm(9, 0), tm(10, 0)))},
+			{Demand: 1, Range: MustRange(Between(tm(10, 0), tm(11, 0)))},
+			{Demand: 2, Range: MustRange(Between(tm(13, 0), tm(13, 30)))},
+		}, assigned)
+	})
+
+	t.Run("negative gap", func(t *testing.T) {
+		assigned, unsatisfied := Pack(free, demands, MinGap(-30*time.Minute))
+		assert.Empty(t, unsatisfied)
+		assert.Equal(t, []Assignment{
+			{Demand: 0, Range: MustRange(Between(tm(9, 0), tm(10, 0)))},
+			{Demand: 1, Range: MustRange(Between(tm(10, 0), tm(11, 0)))},
+			{Demand: 2, Range: MustRange(Between(tm(11, 0), tm(11, 30)))},
+		}, assigned)
+	})
+}
@@ -0,0 +1,70 @@
+package trn
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"sort"
+	"time"
+)
+
+// Page returns the page of at most pageSize slots, which follows the
+// continuation token, and the token of the next page. Empty token stands
+// for the first page, empty next token means that there are no more
+// slots. Slots must be sorted by start and end, as Split and Stratify
+// produce them. Tokens are opaque and refer to the time of the next slot,
+// so pagination stays consistent even if the slots are regenerated
+// between requests. Malformed token gives an empty page. Non-positive
+// pageSize gives all the remaining slots.
+func Page(slots []Range, pageSize int, token string) (page []Range, nextToken string) {
+	from := 0
+	if token != "" {
+		st, end, ok := decodePageToken(token)
+		if !ok {
+			return nil, ""
+		}
+
+		from = sort.Search(len(slots), func(i int) bool {
+			s := slots[i]
+			return s.st.After(st) || s.st.Equal(st) && !s.End().Before(end)
+		})
+	}
+
+	to := len(slots)
+	if pageSize > 0 && pageSize < to-from {
+		to = from + pageSize
+	}
+
+	if to < len(slots) {
+		nextToken = encodePageToken(slots[to])
+	}
+
+	return slots[from:to], nextToken
+}
+
+// encodePageToken encodes the boundaries of the slot as seconds and
+// nanoseconds of the Unix time.
+func encodePageToken(r Range) string {
+	var b [24]byte
+	putUnix(b[:], r.st)
+	putUnix(b[12:], r.End())
+	return base64.RawURLEncoding.EncodeToString(b[:])
+}
+
+// putUnix puts the seconds and nanoseconds of the Unix time into the first
+// 12 bytes of b.
+func putUnix(b []byte, t time.Time) {
+	binary.BigEndian.PutUint64(b, uint64(t.Unix()))
+	binary.BigEndian.PutUint32(b[8:], uint32(t.Nanosecond()))
+}
+
+func decodePageToken(token string) (st, end time.Time, ok bool) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(b) != 24 {
+		return time.Time{}, time.Time{}, false
+	}
+
+	decode := func(b []byte) time.Time {
+		return time.Unix(int64(binary.BigEndian.Uint64(b)), int64(binary.BigEndian.Uint32(b[8:])))
+	}
+	return decode(b), decode(b[12:]), true
+}
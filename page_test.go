@@ -0,0 +1,68 @@
+package trn
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPage(t *testing.T) {
+	slots, err := MustRange(Between(tm(9, 0), tm(12, 0))).Split(30*time.Minute, 0)
+	require.NoError(t, err)
+
+	var pages [][]Range
+	token := ""
+	for {
+		page, next := Page(slots, 4, token)
+		pages = append(pages, page)
+		if next == "" {
+			break
+		}
+		token = next
+	}
+	assert.Equal(t, [][]Range{slots[:4], slots[4:]}, pages)
+
+	t.Run("slots regenerated", func(t *testing.T) {
+		_, next := Page(slots, 2, "")
+
+		// the first slot is booked meanwhile, the next page still starts
+		// from the same slot
+		page, _ := Page(slots[1:], 2, next)
+		assert.Equal(t, slots[2:4], page)
+
+		// the next slot is booked meanwhile, the page starts from the
+		// following one
+		page, _ = Page(append(slots[:2:2], slots[3:]...), 2, next)
+		assert.Equal(t, slots[3:5], page)
+	})
+
+	t.Run("same start", func(t *testing.T) {
+		rngs := []Range{New(tm(9, 0), time.Hour), New(tm(9, 0), 2*time.Hour), New(tm(10, 0), time.Hour)}
+		page, next := Page(rngs, 1, "")
+		assert.Equal(t, rngs[:1], page)
+		page, _ = Page(rngs, 1, next)
+		assert.Equal(t, rngs[1:2], page)
+	})
+
+	t.Run("all", func(t *testing.T) {
+		page, next := Page(slots, 0, "")
+		assert.Equal(t, slots, page)
+		assert.Empty(t, next)
+	})
+
+	t.Run("huge page size", func(t *testing.T) {
+		_, next := Page(slots, 2, "")
+		page, next := Page(slots, math.MaxInt, next)
+		assert.Equal(t, slots[2:], page)
+		assert.Empty(t, next)
+	})
+
+	t.Run("malformed token", func(t *testing.T) {
+		page, next := Page(slots, 2, "blah")
+		assert.Empty(t, page)
+		assert.Empty(t, next)
+	})
+}
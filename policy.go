@@ -0,0 +1,61 @@
+package trn
+
+import "sort"
+
+// MergePolicy resolves the overlaps of labeled ranges, producing the sorted
+// non-overlapping labeled ranges.
+type MergePolicy[T any] func(items []Labeled[T]) ([]Labeled[T], error)
+
+// ResolveLabeled resolves the overlaps of labeled ranges with the policy,
+// e.g. to decide whether the confirmed booking overrides the tentative one.
+func ResolveLabeled[T any](items []Labeled[T], policy MergePolicy[T]) ([]Labeled[T], error) {
+	return policy(items)
+}
+
+// PriorityWins resolves the overlaps in favor of the value with the highest
+// priority, the same way as ResolvePriorities does.
+func PriorityWins[T any](priority func(T) int) MergePolicy[T] {
+	return func(items []Labeled[T]) ([]Labeled[T], error) {
+		prioritized := make([]Prioritized[T], len(items))
+		for i, item := range items {
+			prioritized[i] = Prioritized[T]{Range: item.Range, Priority: priority(item.Value), Value: item.Value}
+		}
+
+		var res []Labeled[T]
+		for _, p := range ResolvePriorities(prioritized) {
+			res = append(res, Labeled[T]{Range: p.Range, Value: p.Value})
+		}
+		return res, nil
+	}
+}
+
+// SplitOnConflict splits the overlapping ranges into segments, the
+// segments covered by several ranges carry their values combined with the
+// merge function, the same way as FlattenLabeled does.
+func SplitOnConflict[T any](merge func(a, b T) T) MergePolicy[T] {
+	return func(items []Labeled[T]) ([]Labeled[T], error) {
+		return FlattenLabeled(items, merge), nil
+	}
+}
+
+// ErrorOnConflict returns ConflictError, matching ErrConflict, for the
+// first pair of overlapping ranges, touching ranges are not considered
+// conflicting. If there are no conflicts, the ranges are returned sorted.
+func ErrorOnConflict[T any]() MergePolicy[T] {
+	return func(items []Labeled[T]) ([]Labeled[T], error) {
+		if len(items) == 0 {
+			return nil, nil
+		}
+
+		sorted := make([]Labeled[T], len(items))
+		copy(sorted, items)
+		sort.SliceStable(sorted, func(i, j int) bool { return lessRange(sorted[i].Range, sorted[j].Range) })
+
+		for i := 1; i < len(sorted); i++ {
+			if sorted[i].Overlaps(sorted[i-1].Range) {
+				return nil, ConflictError{First: sorted[i-1].Range, Second: sorted[i].Range}
+			}
+		}
+		return sorted, nil
+	}
+}
@@ -0,0 +1,74 @@
+package trn
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveLabeled(t *testing.T) {
+	items := []Labeled[string]{
+		Label(MustRange(Between(tm(9, 0), tm(12, 0))), "tentative"),
+		Label(MustRange(Between(tm(10, 0), tm(11, 0))), "confirmed"),
+		Label(MustRange(Between(tm(13, 0), tm(14, 0))), "tentative"),
+	}
+
+	t.Run("priority wins", func(t *testing.T) {
+		got, err := ResolveLabeled(items, PriorityWins(func(s string) int {
+			if s == "confirmed" {
+				return 1
+			}
+			return 0
+		}))
+		require.NoError(t, err)
+		assert.Equal(t, []labeledSegment{
+			{rng: "[09:00, 10:00]", value: "tentative"},
+			{rng: "[10:00, 11:00]", value: "confirmed"},
+			{rng: "[11:00, 12:00]", value: "tentative"},
+			{rng: "[13:00, 14:00]", value: "tentative"},
+		}, formattedLabeled(got))
+	})
+
+	t.Run("split on conflict", func(t *testing.T) {
+		got, err := ResolveLabeled(items, SplitOnConflict(concat))
+		require.NoError(t, err)
+		assert.Equal(t, []labeledSegment{
+			{rng: "[09:00, 10:00]", value: "tentative"},
+			{rng: "[10:00, 11:00]", value: "tentative+confirmed"},
+			{rng: "[11:00, 12:00]", value: "tentative"},
+			{rng: "[13:00, 14:00]", value: "tentative"},
+		}, formattedLabeled(got))
+	})
+
+	t.Run("error on conflict", func(t *testing.T) {
+		_, err := ResolveLabeled(items, ErrorOnConflict[string]())
+		assert.ErrorIs(t, err, ErrConflict)
+
+		var cErr ConflictError
+		require.True(t, errors.As(err, &cErr))
+		assert.Equal(t, ConflictError{First: items[0].Range, Second: items[1].Range}, cErr)
+		assert.EqualError(t, err, "trn: ranges overlap: "+
+			"[2021-06-12T09:00:00Z, 2021-06-12T12:00:00Z] and [2021-06-12T10:00:00Z, 2021-06-12T11:00:00Z]")
+
+		got, err := ResolveLabeled([]Labeled[string]{items[2], items[1], items[1]}, ErrorOnConflict[string]())
+		assert.ErrorIs(t, err, ErrConflict)
+		assert.Nil(t, got)
+
+		touching := []Labeled[string]{
+			items[2],
+			Label(MustRange(Between(tm(12, 0), tm(13, 0))), "confirmed"),
+		}
+		got, err = ResolveLabeled(touching, ErrorOnConflict[string]())
+		require.NoError(t, err)
+		assert.Equal(t, []labeledSegment{
+			{rng: "[12:00, 13:00]", value: "confirmed"},
+			{rng: "[13:00, 14:00]", value: "tentative"},
+		}, formattedLabeled(got))
+
+		got, err = ResolveLabeled(nil, ErrorOnConflict[string]())
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+}
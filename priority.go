@@ -0,0 +1,49 @@
+package trn
+
+import "time"
+
+// Prioritized is a range with the attached value and its priority.
+type Prioritized[T any] struct {
+	Range
+	Priority int
+	Value    T
+}
+
+// ResolvePriorities flattens the overlapping prioritized ranges into the
+// sorted non-overlapping segments, each one carrying the value of the range
+// with the highest priority among the ones covering the segment, e.g. to
+// resolve the price tiers of overlapping promotions.
+// Ties are resolved in favor of the range, which comes first in the input.
+// Adjacent segments won by the same range are merged, gaps are omitted.
+func ResolvePriorities[T any](items []Prioritized[T]) []Prioritized[T] {
+	indexes := make([]Labeled[int], len(items))
+	for i, item := range items {
+		indexes[i] = Labeled[int]{Range: item.Range, Value: i}
+	}
+
+	var res []Prioritized[T]
+	lastWinner := -1
+
+	sweepLabeled(indexes, func(st, end time.Time, active []int) {
+		winner := active[0]
+		for _, idx := range active[1:] {
+			if items[idx].Priority > items[winner].Priority {
+				winner = idx
+			}
+		}
+
+		if winner == lastWinner && res[len(res)-1].End().Equal(st) {
+			res[len(res)-1].dur = end.Sub(res[len(res)-1].st)
+			return
+		}
+
+		res = append(res, Prioritized[T]{
+			Range:    Range{st: st, dur: end.Sub(st)},
+			Priority: items[winner].Priority,
+			Value:    items[winner].Value,
+		})
+		lastWinner = winner
+	})
+
+	return res
+}
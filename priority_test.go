@@ -0,0 +1,61 @@
+package trn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolvePriorities(t *testing.T) {
+	type segment struct {
+		rng   string
+		price int
+	}
+
+	tests := []struct {
+		name  string
+		items []Prioritized[int]
+		want  []segment
+	}{
+		{name: "empty"},
+		{
+			name: "promotions over the base price",
+			items: []Prioritized[int]{
+				{Range: MustRange(Between(tm(9, 0), tm(18, 0))), Priority: 0, Value: 100},
+				{Range: MustRange(Between(tm(12, 0), tm(14, 0))), Priority: 2, Value: 50},
+				{Range: MustRange(Between(tm(13, 0), tm(15, 0))), Priority: 1, Value: 80},
+				{Range: MustRange(Between(tm(17, 0), tm(19, 0))), Priority: 1, Value: 90},
+			},
+			want: []segment{
+				{rng: "[09:00, 12:00]", price: 100},
+				{rng: "[12:00, 14:00]", price: 50},
+				{rng: "[14:00, 15:00]", price: 80},
+				{rng: "[15:00, 17:00]", price: 100},
+				{rng: "[17:00, 19:00]", price: 90},
+			},
+		},
+		{
+			name: "ties and gaps",
+			items: []Prioritized[int]{
+				{Range: MustRange(Between(tm(9, 0), tm(10, 0))), Priority: 1, Value: 1},
+				{Range: MustRange(Between(tm(9, 30), tm(11, 0))), Priority: 1, Value: 2},
+				{Range: MustRange(Between(tm(12, 0), tm(13, 0))), Priority: 1, Value: 3},
+				{Range: MustRange(Between(tm(12, 0), tm(12, 0))), Priority: 5, Value: 4}, // empty
+			},
+			want: []segment{
+				{rng: "[09:00, 10:00]", price: 1},
+				{rng: "[10:00, 11:00]", price: 2},
+				{rng: "[12:00, 13:00]", price: 3},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []segment
+			for _, s := range ResolvePriorities(tt.items) {
+				got = append(got, segment{rng: s.Format("15:04"), price: s.Value})
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -3,9 +3,10 @@
 package trn
 
 import (
-	"fmt"
+	"math"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -19,10 +20,49 @@ func In(loc *time.Location) Option {
 	return func(r *Range) { r.st = r.st.In(loc) }
 }
 
+// precision is the package-wide precision of range boundaries, accessed
+// atomically.
+var precision int64
+
+// SetPrecision sets the package-wide precision of range boundaries, e.g.
+// time.Minute for systems, which don't care about seconds. The boundaries
+// of ranges made by New and Between, as well as marshaled and unmarshaled
+// ranges, are rounded to the nearest multiple of the precision, while the
+// internal computations, e.g. of business hours, are not affected.
+// Zero precision (default) disables rounding.
+func SetPrecision(d time.Duration) { atomic.StoreInt64(&precision, int64(d)) }
+
+// Precision returns the package-wide precision of range boundaries.
+func Precision() time.Duration { return time.Duration(atomic.LoadInt64(&precision)) }
+
+// applyPrecision rounds the range boundaries to the package-wide precision.
+func (r Range) applyPrecision() Range {
+	if p := Precision(); p > 0 {
+		Round(p)(&r)
+	}
+	return r
+}
+
+// StripMonotonic strips the monotonic clock reading from the range
+// boundaries, so the ranges made of time.Now() could be compared with ==
+// and reflect.DeepEqual.
+func StripMonotonic() Option { return Round(0) }
+
+// Round strips the monotonic clock reading and rounds the range boundaries
+// to the nearest multiple of the precision, e.g. Round(time.Second) drops
+// the sub-second noise. If precision is less or equal to zero, boundaries
+// are left as is, except for the monotonic clock reading.
+func Round(precision time.Duration) Option {
+	return func(r *Range) {
+		st, end := r.st.Round(precision), r.End().Round(precision)
+		r.st, r.dur = st, end.Sub(st)
+	}
+}
+
 // New makes a new Range with start at the given time and with the given
 // duration.
 func New(start time.Time, duration time.Duration, opts ...Option) Range {
-	res := Range{st: start, dur: duration}
+	res := Range{st: start, dur: duration}.applyPrecision()
 	for _, opt := range opts {
 		opt(&res)
 	}
@@ -31,13 +71,27 @@ func New(start time.Time, duration time.Duration, opts ...Option) Range {
 
 // Between returns the new Range in the given time bounds. Range will use the
 // location of the start timestamp.
-// Returns ErrStartAfterEnd if the start time is later than the end.
+// Returns StartAfterEndError, matching ErrStartAfterEnd, if the start time
+// is later than the end, ErrBeyondHorizon if the start is beyond the
+// package-wide horizon, and ErrDurationOverflow if the range is longer
+// than the maximal time.Duration, e.g. between MinTime and MaxTime.
 func Between(start, end time.Time, opts ...Option) (Range, error) {
 	if start.After(end) {
-		return Range{}, ErrStartAfterEnd
+		return Range{}, StartAfterEndError{Start: start, End: end}
 	}
 
-	res := Range{st: start, dur: end.Sub(start)}
+	if limit, ok := horizonLimit(); ok && end.After(limit) {
+		if start.After(limit) {
+			return Range{}, beyondHorizon(start, limit)
+		}
+		end = limit
+	}
+
+	if overflows(start, end) {
+		return Range{}, ErrDurationOverflow
+	}
+
+	res := Range{st: start, dur: end.Sub(start)}.applyPrecision()
 	for _, opt := range opts {
 		opt(&res)
 	}
@@ -45,14 +99,57 @@ func Between(start, end time.Time, opts ...Option) (Range, error) {
 	return res, nil
 }
 
+// NewBetween returns the new Range in the given time bounds, like Between
+// does, but for inputs validated beforehand. If the end is before the start,
+// it's clamped to the start, i.e. the resulting range has zero duration and
+// starts at the given start, so it is never Empty unless the start is zero.
+// If the range is too long to be represented, its end is clamped as by
+// OpenEnded. Neither the package-wide precision nor the horizon are
+// applied, so the library uses it for its own computations.
+func NewBetween(start, end time.Time, opts ...Option) Range {
+	if end.Before(start) {
+		end = start
+	}
+	if overflows(start, end) {
+		end = OpenEnded(start).End()
+	}
+
+	res := Range{st: start, dur: end.Sub(start)}
+	for _, opt := range opts {
+		opt(&res)
+	}
+	return res
+}
+
 // Range represents time slot with its own start and end time boundaries
 type Range struct {
 	st  time.Time
 	dur time.Duration
 }
 
+// stringFmt is the package-wide layout used by Range.String, accessed
+// atomically, empty value stands for defaultRangeFmt.
+var stringFmt atomic.Value
+
+// SetDefaultFormat sets the package-wide layout of range boundaries used by
+// String, e.g. time.RFC3339 to make the logs less noisy. Boundaries are
+// printed in UTC. Empty layout restores the default one with nanosecond
+// precision. GoString is not affected.
+func SetDefaultFormat(layout string) { stringFmt.Store(layout) }
+
+// DefaultFormat returns the package-wide layout used by String.
+func DefaultFormat() string {
+	if layout, _ := stringFmt.Load().(string); layout != "" {
+		return layout
+	}
+	return defaultRangeFmt
+}
+
 // String implements fmt.Stringer to print and log Range properly
-func (r Range) String() string { return r.UTC().Format(defaultRangeFmt) }
+func (r Range) String() string {
+	layout := DefaultFormat()
+	return string(r.UTC().AppendFormat(make([]byte, 0, 2*len(layout)+4), layout))
+}
 
 // GoString implements fmt.GoStringer and formats r to be printed in Go source
 // code.
@@ -81,47 +178,189 @@ func (r Range) End() time.Time { return r.st.Add(r.dur) }
 // In returns the date range with boundaries in the provided location's time zone.
 func (r Range) In(loc *time.Location) Range { return Range{st: r.st.In(loc), dur: r.dur} }
 
+// InEach returns the same range in each of the locations, e.g. to display
+// the meeting in the time zones of all participants.
+func (r Range) InEach(locs []*time.Location) []Range {
+	res := make([]Range, len(locs))
+	for i, loc := range locs {
+		res[i] = r.In(loc)
+	}
+	return res
+}
+
 // Empty returns true if the date range is empty.
 func (r Range) Empty() bool { return r.st.IsZero() && r.dur == 0 }
 
+// Shift returns the date range moved by d, the duration is kept.
+func (r Range) Shift(d time.Duration) Range { return Range{st: r.st.Add(d), dur: r.dur} }
+
+// Equal returns true if both date ranges start at the same instant and have
+// the same duration, locations are not compared.
+func (r Range) Equal(other Range) bool { return r.st.Equal(other.st) && r.dur == other.dur }
+
+// Upcoming returns true if the range starts after now.
+func (r Range) Upcoming(now time.Time) bool { return now.Before(r.st) }
+
+// Active returns true if now is within the range, the end is exclusive.
+func (r Range) Active(now time.Time) bool { return r.ContainsTime(now) }
+
+// Expired returns true if the range has ended by now, i.e. the end of the
+// range is not after now.
+func (r Range) Expired(now time.Time) bool { return !now.Before(r.End()) }
+
+// TimeUntilStart returns the time left until the start of the range,
+// negative if the range has already started.
+func (r Range) TimeUntilStart(now time.Time) time.Duration { return r.st.Sub(now) }
+
+// TimeUntilEnd returns the time left until the end of the range, negative
+// if the range has already ended.
+func (r Range) TimeUntilEnd(now time.Time) time.Duration { return r.End().Sub(now) }
+
+// ClipPast returns the part of the date range, which is not before now,
+// e.g. to present only the bookable part of the availability.
+// Returns empty range if the date range has ended by now.
+func (r Range) ClipPast(now time.Time) Range {
+	switch {
+	case r.Expired(now):
+		return Range{}
+	case r.Active(now):
+		return Range{st: now.In(r.st.Location()), dur: r.End().Sub(now)}
+	default:
+		return r
+	}
+}
+
 // Format returns the string representation of the time range with the given format.
 func (r Range) Format(layout string) string {
-	return fmt.Sprintf("[%s, %s]", r.st.Format(layout), r.End().Format(layout))
+	return string(r.AppendFormat(make([]byte, 0, 2*len(layout)+4), layout))
+}
+
+// AppendFormat is like Format but appends the textual representation to b
+// and returns the extended buffer, mirroring time.Time.AppendFormat.
+func (r Range) AppendFormat(b []byte, layout string) []byte {
+	b = append(b, '[')
+	b = r.st.AppendFormat(b, layout)
+	b = append(b, ", "...)
+	b = r.End().AppendFormat(b, layout)
+	return append(b, ']')
 }
 
 // Split the date range into smaller ranges, with fixed duration and with the
 // given interval between the *end* of the one range and *start* of next range.
 // In case if the last interval doesn't fit into the given duration, MustSplit won't
 // return it.
-// Returns ErrZeroDurationInterval if the provided duration is less or equal zero.
-func (r Range) Split(duration time.Duration, interval time.Duration) ([]Range, error) {
-	if duration <= 0 {
-		return nil, ErrZeroDurationInterval
+// Returns InvalidIntervalError, matching ErrZeroDurationInterval, if the
+// provided duration is less or equal zero.
+// Returns TooManySlotsError, matching ErrTooManySlots, if the number of the
+// resulting ranges exceeds the limit set by MaxSlots.
+func (r Range) Split(duration time.Duration, interval time.Duration, opts ...SplitOption) ([]Range, error) {
+	if duration <= 0 || duration+interval <= 0 {
+		return nil, InvalidIntervalError{Duration: duration, Interval: interval}
 	}
-	return r.Stratify(duration, duration+interval)
+	return r.Stratify(duration, duration+interval, opts...)
 }
 
 // Stratify the date range into smaller ranges, with fixed duration and with the
 // given interval between the *starts* of the resulting ranges.
 // In case if the last interval doesn't fit into the given duration, MustStratify
 // won't return it.
-// Returns ErrZeroDurationInterval if the provided duration or interval is less
-// or equal to zero.
-func (r Range) Stratify(duration time.Duration, interval time.Duration) ([]Range, error) {
+// Returns InvalidIntervalError, matching ErrZeroDurationInterval, if the
+// provided duration or interval is less or equal to zero.
+// Returns TooManySlotsError, matching ErrTooManySlots, if the number of the
+// resulting ranges exceeds the limit set by MaxSlots.
+func (r Range) Stratify(duration time.Duration, interval time.Duration, opts ...SplitOption) ([]Range, error) {
 	if interval <= 0 || duration <= 0 {
-		return nil, ErrZeroDurationInterval
+		return nil, InvalidIntervalError{Duration: duration, Interval: interval}
 	}
 
-	var res []Range
+	o := newSplitOptions(opts)
+
+	slots := r.slots(duration, interval)
+	if slots == 0 {
+		return nil, nil
+	}
+
+	if o.exceeds(slots) {
+		return nil, TooManySlotsError{Slots: slots, Max: o.maxSlots}
+	}
+
+	return r.appendSlots(make([]Range, 0, prealloc(slots)), duration, interval, o)
+}
+
+// StratifySet stratifies each of the free ranges the same way as Stratify
+// does and returns all the slots in the chronological order. Free ranges
+// are merged beforehand. MaxSlots limits the total number of slots.
+func StratifySet(free []Range, duration, interval time.Duration, opts ...SplitOption) ([]Range, error) {
+	if interval <= 0 || duration <= 0 {
+		return nil, InvalidIntervalError{Duration: duration, Interval: interval}
+	}
+
+	o := newSplitOptions(opts)
+	rngs := MergeOverlappingRanges(free)
+
+	var slots int64
+	for _, r := range rngs {
+		if slots += r.slots(duration, interval); slots < 0 {
+			slots = math.MaxInt64
+		}
+	}
+
+	if slots == 0 {
+		return nil, nil
+	}
+
+	if o.exceeds(slots) {
+		return nil, TooManySlotsError{Slots: slots, Max: o.maxSlots}
+	}
+
+	res := make([]Range, 0, prealloc(slots))
+	for _, r := range rngs {
+		var err error
+		if res, err = r.appendSlots(res, duration, interval, o); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// slots returns the maximum number of slots in the range.
+func (r Range) slots(duration, interval time.Duration) int64 {
+	if r.dur < duration {
+		return 0
+	}
+	return int64((r.dur-duration)/interval) + 1
+}
+
+// prealloc returns the capacity to preallocate for the n slots, the
+// forbidden windows might leave much less of them than estimated.
+func prealloc(n int64) int {
+	if n > maxPrealloc {
+		return maxPrealloc
+	}
+	return int(n)
+}
+
+// appendSlots appends the slots of the range to dst. Returns
+// TooManySlotsError if dst gets more slots than allowed by MaxSlots.
+func (r Range) appendSlots(dst []Range, duration, interval time.Duration, o splitOptions) ([]Range, error) {
 	rangeEnd := r.End()
 	rangeStart := r.st
 
-	for rangeEnd.Sub(rangeStart.Add(duration)) >= 0 {
-		res = append(res, Range{st: rangeStart, dur: duration})
+	for {
+		rangeStart = o.allowed(rangeStart, duration)
+		if rangeEnd.Sub(rangeStart.Add(duration)) < 0 {
+			break
+		}
+
+		if o.maxSlots > 0 && len(dst) == o.maxSlots {
+			return nil, TooManySlotsError{Slots: int64(len(dst)) + 1, Max: o.maxSlots}
+		}
+
+		dst = append(dst, Range{st: o.jittered(r, rangeStart, duration), dur: duration})
 		rangeStart = rangeStart.Add(interval)
 	}
 
-	return res, nil
+	return dst, nil
 }
 
 // Contains returns true if the other date range is within this date range.
@@ -133,6 +372,12 @@ func (r Range) Contains(other Range) bool {
 	return false
 }
 
+// Overlaps returns true if the date ranges share some time, touching
+// boundaries are not considered overlapping.
+func (r Range) Overlaps(other Range) bool {
+	return r.st.Before(other.End()) && other.st.Before(r.End())
+}
+
 // Truncate returns the date range bounded to the *bounds*, i.e. it cuts
 // the start and the end of *r* to fit into the *bounds*.
 func (r Range) Truncate(bounds Range) Range {
@@ -165,21 +410,55 @@ func (r Range) Truncate(bounds Range) Range {
 	}
 }
 
+// TruncateAll returns the parts of the date range, which fall into any of
+// the bounds. Bounds may be unsorted and overlapping, they are merged
+// beforehand. The resulting ranges are sorted.
+func (r Range) TruncateAll(bounds []Range) []Range {
+	var res []Range
+	for _, b := range MergeOverlappingRanges(bounds) {
+		if part := r.Truncate(b); part.dur > 0 {
+			res = append(res, part)
+		}
+	}
+	return res
+}
+
+// Exclude returns the parts of the date range, which are not covered by any
+// of the exclusions, i.e. it is the inverse of TruncateAll. Unlike Flip,
+// exclusions may be unsorted, overlapping and lie outside the range.
+// The resulting ranges are sorted. Of the merge options, only MinDuration
+// is taken into account.
+func (r Range) Exclude(exclusions []Range, opts ...MergeOption) []Range {
+	o := newMergeOptions(opts)
+
+	within := r.TruncateAll(exclusions)
+	if len(within) == 0 {
+		return dropShorter([]Range{r}, o.minDuration)
+	}
+	return dropShorter(r.flipValidRanges(within), o.minDuration)
+}
+
 // Flip within the given period.
 //
 // The boundaries of the given ranges are considered to be inclusive, means
 // that the flipped ranges will start or end at the exact nanosecond where
-// the boundary from the input starts or ends.
-func (r Range) Flip(ranges []Range) []Range {
+// the boundary from the input starts or ends. Merge options, such as Epsilon,
+// are applied when merging the ranges, MinDuration drops the flipped ranges
+// shorter than the given duration.
+func (r Range) Flip(ranges []Range, opts ...MergeOption) []Range {
+	o := newMergeOptions(opts)
+
 	if len(ranges) == 0 {
-		return []Range{r}
+		return dropShorter([]Range{r}, o.minDuration)
 	}
 
 	// to exclude the case of distinct ranges, ranges not within the period
 	// and unsorted list of ranges
-	rngs := MergeOverlappingRanges(ranges)
+	a := AcquireArena()
+	rngs := a.merge(nil, ranges, o.epsilon)
+	a.Release()
 
-	return r.flipValidRanges(rngs)
+	return dropShorter(r.flipValidRanges(rngs), o.minDuration)
 }
 
 func (r Range) flipValidRanges(ranges []Range) []Range {
@@ -213,4 +492,21 @@ func (e Error) Error() string { return string(e) }
 const (
 	ErrStartAfterEnd        = Error("trn: start time is later than the end")
 	ErrZeroDurationInterval = Error("trn: cannot split with zero duration or interval")
+	ErrOddBoundaries        = Error("trn: odd number of boundaries")
+	ErrUnsortedBoundaries   = Error("trn: boundaries are not sorted")
+	ErrMultipleRanges       = Error("trn: more than one range in the result")
+	ErrNoPattern            = Error("trn: no recurring pattern found")
+	ErrMalformedRange       = Error("trn: malformed range")
+	ErrMalformedTimestamp   = Error("trn: malformed timestamp")
+	ErrInconsistentZone     = Error("trn: utc offset doesn't match the time zone")
+	ErrUnknownChange        = Error("trn: unknown kind of change")
+	ErrTooManySlots         = Error("trn: too many slots in the result")
+	ErrNotAvailable         = Error("trn: range is not available")
+	ErrETagMismatch         = Error("trn: etag doesn't match")
+	ErrConflict             = Error("trn: ranges overlap")
+	ErrUnsupportedVersion   = Error("trn: unsupported encoding version")
+	ErrDurationOverflow     = Error("trn: range is too long to be represented")
+	ErrBeyondHorizon        = Error("trn: range is beyond the horizon")
+	ErrShortNotice          = Error("trn: range starts too soon")
+	ErrTooClose             = Error("trn: ranges are closer than the buffer")
 )
@@ -131,6 +131,97 @@ func TestRange_Truncate(t *testing.T) {
 	}
 }
 
+func TestRange_TruncateAll(t *testing.T) {
+	rng := MustRange(Between(tm(10, 0), tm(18, 0)))
+
+	tests := []struct {
+		name   string
+		bounds []Range
+		want   []Range
+	}{
+		{name: "no bounds"},
+		{
+			name: "unsorted, overlapping and outside bounds",
+			bounds: []Range{
+				MustRange(Between(tm(16, 0), tm(20, 0))),  // -----XXX-
+				MustRange(Between(tm(8, 0), tm(11, 0))),   // XX-------
+				MustRange(Between(tm(12, 0), tm(13, 0))),  // ---XX----
+				MustRange(Between(tm(12, 30), tm(14, 0))), // ----XX---
+				MustRange(Between(tm(6, 0), tm(7, 0))),    // X--------
+			},
+			want: []Range{
+				MustRange(Between(tm(10, 0), tm(11, 0))),
+				MustRange(Between(tm(12, 0), tm(14, 0))),
+				MustRange(Between(tm(16, 0), tm(18, 0))),
+			},
+		},
+		{
+			name:   "bounds cover the range",
+			bounds: []Range{MustRange(Between(tm(9, 0), tm(19, 0)))},
+			want:   []Range{rng},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, formattedRanges(tt.want, "15:04"), formattedRanges(rng.TruncateAll(tt.bounds), "15:04"))
+		})
+	}
+}
+
+func TestRange_Exclude(t *testing.T) {
+	rng := MustRange(Between(tm(10, 0), tm(18, 0)))
+
+	tests := []struct {
+		name       string
+		exclusions []Range
+		want       []Range
+	}{
+		{name: "no exclusions", want: []Range{rng}},
+		{
+			name:       "exclusions outside the range",
+			exclusions: []Range{MustRange(Between(tm(6, 0), tm(7, 0))), MustRange(Between(tm(19, 0), tm(20, 0)))},
+			want:       []Range{rng},
+		},
+		{
+			name: "unsorted, overlapping and crossing the boundaries",
+			exclusions: []Range{
+				MustRange(Between(tm(16, 0), tm(20, 0))),  // -----XXX-
+				MustRange(Between(tm(8, 0), tm(11, 0))),   // XX-------
+				MustRange(Between(tm(12, 0), tm(13, 0))),  // ---XX----
+				MustRange(Between(tm(12, 30), tm(14, 0))), // ----XX---
+			},
+			want: []Range{
+				MustRange(Between(tm(11, 0), tm(12, 0))),
+				MustRange(Between(tm(14, 0), tm(16, 0))),
+			},
+		},
+		{
+			name:       "exclusion covers the range",
+			exclusions: []Range{MustRange(Between(tm(9, 0), tm(19, 0)))},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, formattedRanges(tt.want, "15:04"), formattedRanges(rng.Exclude(tt.exclusions), "15:04"))
+		})
+	}
+}
+
+func TestRange_Exclude_MinDuration(t *testing.T) {
+	rng := MustRange(Between(tm(10, 0), tm(18, 0)))
+	exclusions := []Range{
+		MustRange(Between(tm(10, 3), tm(12, 0))),
+		MustRange(Between(tm(12, 10), tm(17, 0))),
+	}
+
+	assert.Len(t, rng.Exclude(exclusions), 3)
+	assert.Equal(t, []Range{
+		MustRange(Between(tm(12, 0), tm(12, 10))),
+		MustRange(Between(tm(17, 0), tm(18, 0))),
+	}, rng.Exclude(exclusions, MinDuration(5*time.Minute)))
+	assert.Empty(t, rng.Exclude(nil, MinDuration(9*time.Hour)))
+}
+
 func TestRange_Contains(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -206,6 +297,14 @@ func TestRange_Contains(t *testing.T) {
 	}
 }
 
+func TestRange_Overlaps(t *testing.T) {
+	rng := MustRange(Between(tm(13, 0), tm(14, 0)))
+	assert.True(t, rng.Overlaps(MustRange(Between(tm(13, 30), tm(15, 0)))))
+	assert.True(t, rng.Overlaps(MustRange(Between(tm(12, 0), tm(15, 0)))))
+	assert.False(t, rng.Overlaps(MustRange(Between(tm(14, 0), tm(15, 0)))))
+	assert.False(t, rng.Overlaps(MustRange(Between(tm(11, 0), tm(12, 0)))))
+}
+
 func TestRange_Stratify(t *testing.T) {
 	type args struct {
 		duration time.Duration
@@ -260,6 +359,34 @@ func TestRange_Stratify(t *testing.T) {
 	}
 }
 
+func TestStratifySet(t *testing.T) {
+	free := []Range{
+		MustRange(Between(tm(15, 0), tm(16, 30))),
+		MustRange(Between(tm(10, 0), tm(11, 0))),
+		MustRange(Between(tm(10, 30), tm(11, 30))), // overlaps the previous one
+		MustRange(Between(tm(13, 0), tm(13, 20))),  // too short
+	}
+
+	got, err := StratifySet(free, 30*time.Minute, 45*time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, formattedRanges([]Range{
+		MustRange(Between(tm(10, 0), tm(10, 30))),
+		MustRange(Between(tm(10, 45), tm(11, 15))),
+		MustRange(Between(tm(15, 0), tm(15, 30))),
+		MustRange(Between(tm(15, 45), tm(16, 15))),
+	}, "15:04"), formattedRanges(got, "15:04"))
+
+	_, err = StratifySet(free, 30*time.Minute, 45*time.Minute, MaxSlots(3))
+	assert.ErrorIs(t, err, ErrTooManySlots)
+
+	_, err = StratifySet(free, 0, time.Hour)
+	assert.ErrorIs(t, err, ErrZeroDurationInterval)
+
+	got, err = StratifySet(nil, time.Hour, time.Hour)
+	assert.NoError(t, err)
+	assert.Empty(t, got)
+}
+
 func TestRange_Split(t *testing.T) {
 	type args struct {
 		duration time.Duration
@@ -407,6 +534,36 @@ func TestFlipRanges(t *testing.T) {
 	}
 }
 
+func TestRange_Flip_Epsilon(t *testing.T) {
+	period := MustRange(Between(tm(9, 0), tm(18, 0)))
+	busy := []Range{
+		MustRange(Between(tm(10, 0), tm(12, 0).Add(-time.Microsecond))),
+		MustRange(Between(tm(12, 0), tm(13, 0))),
+	}
+
+	assert.Len(t, period.Flip(busy), 3)
+	assert.Equal(t, []Range{
+		MustRange(Between(tm(9, 0), tm(10, 0))),
+		MustRange(Between(tm(13, 0), tm(18, 0))),
+	}, period.Flip(busy, Epsilon(time.Microsecond)))
+}
+
+func TestRange_Flip_MinDuration(t *testing.T) {
+	period := MustRange(Between(tm(9, 0), tm(18, 0)))
+	busy := []Range{
+		MustRange(Between(tm(9, 3), tm(12, 0))),
+		MustRange(Between(tm(12, 3), tm(12, 4))), // too short to be dropped on its own
+		MustRange(Between(tm(12, 10), tm(17, 0))),
+	}
+
+	assert.Len(t, period.Flip(busy), 4)
+	assert.Equal(t, []Range{
+		MustRange(Between(tm(12, 4), tm(12, 10))),
+		MustRange(Between(tm(17, 0), tm(18, 0))),
+	}, period.Flip(busy, MinDuration(5*time.Minute)))
+	assert.Empty(t, period.Flip(nil, MinDuration(10*time.Hour)))
+}
+
 func TestRange_Format(t *testing.T) {
 	assert.Equal(t,
 		"[2021-06-12T00:00:00, 2021-06-12T03:05:00]",
@@ -419,11 +576,55 @@ func TestRange_UTC(t *testing.T) {
 	assert.Equal(t, Range{st: dt.In(time.Local), dur: 0}, MustRange(Between(dt, dt)).In(time.Local))
 }
 
+func TestNewBetween(t *testing.T) {
+	assert.Equal(t, MustRange(Between(tm(13, 0), tm(14, 0))), NewBetween(tm(13, 0), tm(14, 0)))
+	assert.Equal(t, New(tm(13, 0), 0), NewBetween(tm(13, 0), tm(12, 0)), "end is clamped to the start")
+	assert.Equal(t, New(tm(13, 0), time.Hour, In(time.Local)), NewBetween(tm(13, 0), tm(14, 0), In(time.Local)))
+}
+
+func TestRange_InEach(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("tzdata is not available")
+	}
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip("tzdata is not available")
+	}
+
+	got := New(tm(13, 0), time.Hour).InEach([]*time.Location{berlin, tokyo})
+	assert.Equal(t, []string{"[15:00, 16:00]", "[22:00, 23:00]"}, []string{got[0].Format("15:04"), got[1].Format("15:04")})
+	assert.True(t, got[0].Start().Equal(tm(13, 0)))
+	assert.Empty(t, New(tm(13, 0), time.Hour).InEach(nil))
+}
+
 func TestRange_String(t *testing.T) {
 	assert.Equal(t,
 		"[2021-06-12 00:00:00 +0000 UTC, 2021-06-12 03:05:00 +0000 UTC]",
 		Range{st: dt, dur: 3*time.Hour + 5*time.Minute}.String(),
 	)
+
+	t.Run("custom default format", func(t *testing.T) {
+		SetDefaultFormat(time.RFC3339)
+		defer SetDefaultFormat("")
+
+		assert.Equal(t, time.RFC3339, DefaultFormat())
+		assert.Equal(t,
+			"[2021-06-12T00:00:00Z, 2021-06-12T03:05:00Z]",
+			Range{st: dt.In(time.FixedZone("", 3*60*60)), dur: 3*time.Hour + 5*time.Minute}.String(),
+		)
+	})
+	assert.Equal(t, defaultRangeFmt, DefaultFormat())
+}
+
+func TestRange_AppendFormat(t *testing.T) {
+	r := Range{st: tm(13, 0), dur: 90 * time.Minute}
+	assert.Equal(t, "range: [13:00, 14:30]", string(r.AppendFormat([]byte("range: "), "15:04")))
+	assert.Equal(t, r.Format(time.RFC3339), string(r.AppendFormat(nil, time.RFC3339)))
+
+	buf := make([]byte, 0, 64)
+	allocs := testing.AllocsPerRun(100, func() { buf = r.AppendFormat(buf[:0], time.RFC3339) })
+	assert.Zero(t, allocs)
 }
 
 func TestRange_Duration(t *testing.T) {
@@ -453,6 +654,18 @@ func TestRange_Empty(t *testing.T) {
 	}
 }
 
+func TestRange_Shift(t *testing.T) {
+	assert.Equal(t, New(tm(14, 0), time.Hour), New(tm(13, 0), time.Hour).Shift(time.Hour))
+	assert.Equal(t, New(tm(12, 0), time.Hour), New(tm(13, 0), time.Hour).Shift(-time.Hour))
+}
+
+func TestRange_Equal(t *testing.T) {
+	rng := New(tm(13, 0), time.Hour)
+	assert.True(t, rng.Equal(rng.In(time.FixedZone("UTC+2", 2*60*60))))
+	assert.False(t, rng.Equal(New(tm(13, 0), 2*time.Hour)))
+	assert.False(t, rng.Equal(New(tm(13, 1), time.Hour)))
+}
+
 func TestBetween(t *testing.T) {
 	type args struct {
 		start, end time.Time
@@ -484,7 +697,7 @@ func TestBetween(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			rng, err := Between(tt.args.start, tt.args.end, tt.args.opts...)
 			assert.Equal(t, tt.want, rng)
-			assert.ErrorIs(t, tt.wantErr, err)
+			assert.ErrorIs(t, err, tt.wantErr)
 		})
 	}
 }
@@ -502,6 +715,102 @@ func TestNew(t *testing.T) {
 	})
 }
 
+func TestRound(t *testing.T) {
+	t.Run("strip monotonic", func(t *testing.T) {
+		now := time.Now()
+		rng := New(now, time.Hour, StripMonotonic())
+		assert.Equal(t, now.Round(0), rng.Start())
+		assert.NotContains(t, rng.Start().String(), "m=")
+		assert.Equal(t, time.Hour, rng.Duration())
+	})
+
+	t.Run("round to seconds", func(t *testing.T) {
+		rng := MustRange(Between(
+			tm(13, 0).Add(400*time.Millisecond),
+			tm(14, 0).Add(600*time.Millisecond),
+			Round(time.Second),
+		))
+		assert.Equal(t, Range{st: tm(13, 0), dur: time.Hour + time.Second}, rng)
+	})
+
+	t.Run("round to minutes", func(t *testing.T) {
+		rng := New(tm(13, 0).Add(29*time.Second), 59*time.Minute+50*time.Second, Round(time.Minute))
+		assert.Equal(t, Range{st: tm(13, 0), dur: time.Hour}, rng)
+	})
+}
+
+func TestSetPrecision(t *testing.T) {
+	SetPrecision(time.Minute)
+	defer SetPrecision(0)
+
+	assert.Equal(t, time.Minute, Precision())
+
+	st := tm(13, 0).Add(10 * time.Second)
+	assert.Equal(t, Range{st: tm(13, 0), dur: time.Hour}, New(st, time.Hour))
+	assert.Equal(t, Range{st: tm(13, 0), dur: time.Hour}, MustRange(Between(st, tm(14, 0).Add(20*time.Second))))
+
+	b, err := Range{st: st, dur: time.Hour}.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "2021-06-12T13:00:00Z/2021-06-12T14:00:00Z", string(b))
+
+	var rng Range
+	assert.NoError(t, rng.UnmarshalText([]byte("2021-06-12T13:00:10Z/2021-06-12T14:00:10Z")))
+	assert.Equal(t, Range{st: tm(13, 0), dur: time.Hour}, rng)
+}
+
+func TestRange_Validity(t *testing.T) {
+	rng := MustRange(Between(tm(13, 0), tm(14, 0)))
+
+	tests := []struct {
+		name                      string
+		now                       time.Time
+		upcoming, active, expired bool
+		untilStart, untilEnd      time.Duration
+	}{
+		{name: "before", now: tm(12, 30), upcoming: true, untilStart: 30 * time.Minute, untilEnd: 90 * time.Minute},
+		{name: "at the start", now: tm(13, 0), active: true, untilStart: 0, untilEnd: time.Hour},
+		{name: "within", now: tm(13, 15), active: true, untilStart: -15 * time.Minute, untilEnd: 45 * time.Minute},
+		{name: "at the end", now: tm(14, 0), expired: true, untilStart: -time.Hour, untilEnd: 0},
+		{name: "after", now: tm(14, 30), expired: true, untilStart: -90 * time.Minute, untilEnd: -30 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.upcoming, rng.Upcoming(tt.now))
+			assert.Equal(t, tt.active, rng.Active(tt.now))
+			assert.Equal(t, tt.expired, rng.Expired(tt.now))
+			assert.Equal(t, tt.untilStart, rng.TimeUntilStart(tt.now))
+			assert.Equal(t, tt.untilEnd, rng.TimeUntilEnd(tt.now))
+		})
+	}
+}
+
+func TestRange_ClipPast(t *testing.T) {
+	rng := MustRange(Between(tm(13, 0), tm(15, 0)))
+	tests := []struct {
+		name string
+		now  time.Time
+		want Range
+	}{
+		{name: "upcoming", now: tm(12, 0), want: rng},
+		{name: "at start", now: tm(13, 0), want: rng},
+		{name: "active", now: tm(14, 15), want: MustRange(Between(tm(14, 15), tm(15, 0)))},
+		{name: "at end", now: tm(15, 0), want: Range{}},
+		{name: "expired", now: tm(16, 0), want: Range{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, rng.ClipPast(tt.now))
+		})
+	}
+
+	t.Run("keeps location", func(t *testing.T) {
+		loc := time.FixedZone("", 2*60*60)
+		got := rng.In(loc).ClipPast(tm(14, 0))
+		assert.Equal(t, loc, got.Start().Location())
+		assert.True(t, tm(14, 0).Equal(got.Start()))
+	})
+}
+
 func TestRange_GoString(t *testing.T) {
 	assert.Equal(t,
 		"trn.New(time.Date(2021, time.December, 25, 18, 34, 30, 0, time.UTC), 900000000000)",
@@ -0,0 +1,123 @@
+package trn
+
+import (
+	"context"
+	"time"
+)
+
+// Recurrence describes the range, which repeats every given number of
+// days at the same local time of day.
+type Recurrence struct {
+	// Start is the start of the reference occurrence, its location
+	// defines the local time of day of the other occurrences.
+	Start    time.Time
+	Duration time.Duration
+	// Days is the number of days between the occurrences.
+	Days int
+}
+
+// At returns the k-th occurrence after the reference one, k may be
+// negative.
+func (r Recurrence) At(k int) Range {
+	return Range{st: r.Start.AddDate(0, 0, k*r.Days), dur: r.Duration}
+}
+
+// Next returns n occurrences, which start after the given time.
+// Returns nil if the recurrence has no positive interval.
+func (r Recurrence) Next(after time.Time, n int) []Range {
+	res, _ := r.NextContext(context.Background(), after, n)
+	return res
+}
+
+// NextContext is like Next, but stops once the context is done, returning
+// the occurrences found so far along with the context's error.
+func (r Recurrence) NextContext(ctx context.Context, after time.Time, n int) ([]Range, error) {
+	if r.Days <= 0 || n <= 0 {
+		return nil, nil
+	}
+
+	// jump close to the requested time, a day earlier to avoid missing an
+	// occurrence because of DST transitions
+	period := time.Duration(r.Days) * 24 * time.Hour
+	k := int(after.Sub(r.Start)/period) - 1
+
+	capacity := n
+	if capacity > maxPrealloc {
+		capacity = maxPrealloc
+	}
+
+	res := make([]Range, 0, capacity)
+	for ; len(res) < n; k++ {
+		if err := ctx.Err(); err != nil {
+			return res, err
+		}
+
+		if occ := r.At(k); occ.st.After(after) {
+			res = append(res, occ)
+		}
+	}
+
+	return res, nil
+}
+
+// maxPrealloc limits the capacity preallocated for the results, which
+// size is requested by the caller.
+const maxPrealloc = 1024
+
+// InferPattern detects the daily or weekly pattern in the historical
+// occurrences of a recurring range and returns the recurrence, which
+// continues it. The interval is the most common number of days between
+// consecutive occurrences, which must be either 1 or 7 and describe at
+// least half of the gaps. The time of day and the duration are the median
+// ones. The reference occurrence is the last one in the history.
+// Returns ErrNoPattern if the pattern could not be inferred.
+func InferPattern(history []Range) (Recurrence, error) {
+	if len(history) < 2 {
+		return Recurrence{}, ErrNoPattern
+	}
+
+	sorted := make([]Range, len(history))
+	copy(sorted, history)
+	SortRanges(sorted)
+
+	last := sorted[len(sorted)-1].st
+	loc := last.Location()
+
+	gaps := map[int]int{}
+	clocks := make([]time.Duration, len(sorted))
+	for i, rng := range sorted {
+		clocks[i] = timeOfDay(rng.st.In(loc))
+		if i > 0 {
+			gaps[daysBetween(sorted[i-1].st.In(loc), rng.st.In(loc))]++
+		}
+	}
+
+	days, cnt := 0, 0
+	for gap, n := range gaps {
+		if n > cnt || (n == cnt && gap < days) {
+			days, cnt = gap, n
+		}
+	}
+
+	if (days != 1 && days != 7) || cnt*2 < len(sorted)-1 {
+		return Recurrence{}, ErrNoPattern
+	}
+
+	clock := medianClock(clocks)
+	y, m, d := last.Date()
+	return Recurrence{
+		Start:    time.Date(y, m, d, 0, 0, 0, 0, loc).Add(clock),
+		Duration: Stats(sorted).Median,
+		Days:     days,
+	}, nil
+}
+
+// daysBetween returns the number of calendar days between the dates of
+// the given times, both are expected to be in the same location.
+func daysBetween(a, b time.Time) int {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	da := time.Date(ay, am, ad, 0, 0, 0, 0, time.UTC)
+	db := time.Date(by, bm, bd, 0, 0, 0, 0, time.UTC)
+	return int(db.Sub(da) / (24 * time.Hour))
+}
@@ -0,0 +1,85 @@
+package trn
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInferPattern(t *testing.T) {
+	t.Run("daily with a missed day", func(t *testing.T) {
+		rec, err := InferPattern([]Range{
+			MustRange(Between(dhm(3, 9, 5), dhm(3, 17, 0))),
+			MustRange(Between(dhm(1, 9, 0), dhm(1, 17, 0))),
+			MustRange(Between(dhm(2, 8, 55), dhm(2, 17, 0))),
+			MustRange(Between(dhm(5, 9, 0), dhm(5, 17, 0))),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, Recurrence{Start: dhm(5, 9, 0), Duration: 8 * time.Hour, Days: 1}, rec)
+
+		assert.Equal(t, formattedRanges([]Range{
+			MustRange(Between(dhm(6, 9, 0), dhm(6, 17, 0))),
+			MustRange(Between(dhm(7, 9, 0), dhm(7, 17, 0))),
+		}, "02 15:04"), formattedRanges(rec.Next(dhm(5, 10, 0), 2), "02 15:04"))
+	})
+
+	t.Run("weekly", func(t *testing.T) {
+		rec, err := InferPattern([]Range{
+			MustRange(Between(dhm(1, 18, 0), dhm(1, 19, 0))),
+			MustRange(Between(dhm(8, 18, 0), dhm(8, 19, 30))),
+			MustRange(Between(dhm(15, 18, 0), dhm(15, 19, 0))),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, Recurrence{Start: dhm(15, 18, 0), Duration: time.Hour, Days: 7}, rec)
+		assert.Equal(t, "[22 18:00, 22 19:00]", rec.Next(dhm(15, 18, 0), 1)[0].Format("02 15:04"))
+		assert.Equal(t, "[01 18:00, 01 19:00]", rec.At(-2).Format("02 15:04"))
+	})
+
+	t.Run("no pattern", func(t *testing.T) {
+		_, err := InferPattern([]Range{
+			MustRange(Between(dhm(1, 18, 0), dhm(1, 19, 0))),
+			MustRange(Between(dhm(4, 18, 0), dhm(4, 19, 0))),
+			MustRange(Between(dhm(9, 18, 0), dhm(9, 19, 0))),
+		})
+		assert.ErrorIs(t, err, ErrNoPattern)
+
+		_, err = InferPattern([]Range{MustRange(Between(dhm(1, 18, 0), dhm(1, 19, 0)))})
+		assert.ErrorIs(t, err, ErrNoPattern)
+	})
+}
+
+func TestRecurrence_Next(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("tzdata is not available")
+	}
+
+	rec := Recurrence{Start: time.Date(2021, 3, 26, 9, 0, 0, 0, loc), Duration: time.Hour, Days: 1}
+	got := rec.Next(time.Date(2021, 3, 26, 9, 0, 0, 0, loc), 3)
+	require.Len(t, got, 3)
+	for _, occ := range got {
+		assert.Equal(t, 9, occ.Start().In(loc).Hour(), "keeps the local time over DST")
+	}
+
+	far := rec.Next(time.Date(2031, 3, 26, 10, 0, 0, 0, loc), 1)
+	assert.Equal(t, time.Date(2031, 3, 27, 9, 0, 0, 0, loc), far[0].Start())
+
+	assert.Nil(t, Recurrence{}.Next(time.Now(), 1))
+}
+
+func TestRecurrence_NextContext(t *testing.T) {
+	rec := Recurrence{Start: tm(9, 0), Duration: time.Hour, Days: 1}
+
+	got, err := rec.NextContext(context.Background(), tm(10, 0), 2)
+	require.NoError(t, err)
+	assert.Equal(t, []Range{New(dhm(13, 9, 0), time.Hour), New(dhm(14, 9, 0), time.Hour)}, got)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	got, err = rec.NextContext(ctx, tm(10, 0), 1_000_000_000)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, got)
+}
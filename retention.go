@@ -0,0 +1,66 @@
+package trn
+
+import (
+	"sort"
+	"time"
+)
+
+// RetentionRule keeps the latest timestamp in each bucket of Every, among
+// the timestamps not older than Within, e.g. the rule {Within: 48 *
+// time.Hour, Every: time.Hour} keeps hourly timestamps for two days.
+// Buckets are aligned to the zero time, i.e. daily buckets start at
+// midnight UTC. Zero Every keeps all timestamps within the age.
+type RetentionRule struct {
+	Within time.Duration
+	Every  time.Duration
+}
+
+// RetentionPolicy is the set of retention rules, a timestamp is kept if
+// it's kept by any of the rules.
+type RetentionPolicy []RetentionRule
+
+// Evaluate splits the timestamps into the ones to keep and the ones to
+// expire at the given moment. Both resulting slices are sorted.
+// Timestamps after now are always kept.
+func (p RetentionPolicy) Evaluate(now time.Time, timestamps []time.Time) (keep, expire []time.Time) {
+	ts := make([]time.Time, len(timestamps))
+	copy(ts, timestamps)
+	sort.Slice(ts, func(i, j int) bool { return ts[i].Before(ts[j]) })
+
+	kept := make([]bool, len(ts))
+	for i, t := range ts {
+		kept[i] = t.After(now)
+	}
+
+	for _, rule := range p {
+		since := now.Add(-rule.Within)
+
+		// walk from the latest to keep the latest timestamp of the bucket
+		var last time.Time
+		started := false
+		for i := len(ts) - 1; i >= 0 && !ts[i].Before(since); i-- {
+			if ts[i].After(now) {
+				continue
+			}
+
+			if rule.Every <= 0 {
+				kept[i] = true
+				continue
+			}
+
+			if bucket := ts[i].Truncate(rule.Every); !started || !bucket.Equal(last) {
+				kept[i], last, started = true, bucket, true
+			}
+		}
+	}
+
+	for i, t := range ts {
+		if kept[i] {
+			keep = append(keep, t)
+			continue
+		}
+		expire = append(expire, t)
+	}
+
+	return keep, expire
+}
@@ -0,0 +1,48 @@
+package trn
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetentionPolicy_Evaluate(t *testing.T) {
+	policy := RetentionPolicy{
+		{Within: 3 * time.Hour, Every: time.Hour},
+		{Within: 3 * 24 * time.Hour, Every: 24 * time.Hour},
+	}
+	now := dhm(14, 12, 0)
+
+	keep, expire := policy.Evaluate(now, []time.Time{
+		dhm(14, 11, 50),
+		dhm(14, 11, 20),
+		dhm(14, 10, 30),
+		dhm(14, 8, 30), // too old for the hourly rule
+		dhm(13, 23, 0),
+		dhm(13, 5, 0),
+		dhm(12, 18, 0),
+		dhm(12, 6, 0),
+		dhm(10, 12, 0), // too old for the daily rule
+		dhm(14, 13, 0), // in the future
+	})
+
+	assert.Equal(t, []time.Time{
+		dhm(12, 18, 0),
+		dhm(13, 23, 0),
+		dhm(14, 10, 30),
+		dhm(14, 11, 50),
+		dhm(14, 13, 0),
+	}, keep)
+	assert.Equal(t, []time.Time{
+		dhm(10, 12, 0),
+		dhm(12, 6, 0),
+		dhm(13, 5, 0),
+		dhm(14, 8, 30),
+		dhm(14, 11, 20),
+	}, expire)
+
+	keep, expire = RetentionPolicy{{Within: time.Hour}}.Evaluate(now, []time.Time{dhm(14, 11, 30), dhm(14, 11, 30), dhm(14, 10, 0)})
+	assert.Equal(t, []time.Time{dhm(14, 11, 30), dhm(14, 11, 30)}, keep)
+	assert.Equal(t, []time.Time{dhm(14, 10, 0)}, expire)
+}
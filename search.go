@@ -0,0 +1,101 @@
+package trn
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// CoveredAt returns the range of the sorted and merged ranges, which
+// covers the given instant, e.g. to find out whether the resource is busy
+// right now and until when. The end of the range is not covered by it.
+// Runs in O(log n).
+func CoveredAt(sorted []Range, t time.Time) (Range, bool) {
+	i := sort.Search(len(sorted), func(i int) bool { return sorted[i].End().After(t) })
+	if i == len(sorted) || sorted[i].st.After(t) {
+		return Range{}, false
+	}
+	return sorted[i], true
+}
+
+// NextFreeAfter returns the earliest instant within the period, not before
+// t, which is not covered by the sorted and merged busy ranges, e.g. to
+// find out when the person is free next time. Returns false if there is
+// no such instant. Runs in O(log n).
+func NextFreeAfter(sorted []Range, period Range, t time.Time) (time.Time, bool) {
+	if t.Before(period.st) {
+		t = period.st
+	}
+
+	if r, ok := CoveredAt(sorted, t); ok {
+		t = r.End()
+	}
+
+	if !t.Before(period.End()) {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// NextBusyAfter returns the earliest instant within the period, not before
+// t, which is covered by the sorted and merged busy ranges. Returns false
+// if there is no such instant. Runs in O(log n).
+func NextBusyAfter(sorted []Range, period Range, t time.Time) (time.Time, bool) {
+	if t.Before(period.st) {
+		t = period.st
+	}
+
+	if _, ok := CoveredAt(sorted, t); !ok {
+		i := sort.Search(len(sorted), func(i int) bool { return sorted[i].st.After(t) })
+		if i == len(sorted) {
+			return time.Time{}, false
+		}
+		t = sorted[i].st
+	}
+
+	if !t.Before(period.End()) {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// NextFreeSlot returns the earliest range of the duration d within the
+// period, starting not before after, which is not covered by the sorted
+// and merged busy ranges. Returns false if there is no such range.
+// Runs in O(k log n), where k is the number of free windows shorter than d
+// skipped on the way.
+func NextFreeSlot(sorted []Range, period Range, after time.Time, d time.Duration) (Range, bool) {
+	res, ok, _ := NextFreeSlotContext(context.Background(), sorted, period, after, d)
+	return res, ok
+}
+
+// NextFreeSlotContext is like NextFreeSlot, but stops once the context is
+// done, returning false along with the context's error, e.g. if lots of
+// the short free windows are skipped.
+func NextFreeSlotContext(ctx context.Context, sorted []Range, period Range, after time.Time,
+	d time.Duration) (Range, bool, error) {
+	for {
+		if err := ctx.Err(); err != nil {
+			return Range{}, false, err
+		}
+
+		st, ok := NextFreeAfter(sorted, period, after)
+		if !ok {
+			return Range{}, false, nil
+		}
+
+		end, busy := NextBusyAfter(sorted, period, st)
+		if !busy {
+			end = period.End()
+		}
+
+		if end.Sub(st) >= d {
+			return Range{st: st, dur: d}, true, nil
+		}
+
+		if !busy {
+			return Range{}, false, nil
+		}
+		after = end
+	}
+}
@@ -0,0 +1,131 @@
+package trn
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func searchRanges() []Range {
+	return []Range{
+		MustRange(Between(tm(9, 0), tm(10, 0))),
+		MustRange(Between(tm(11, 0), tm(12, 0))),
+		MustRange(Between(tm(14, 0), tm(15, 0))),
+	}
+}
+
+func TestCoveredAt(t *testing.T) {
+	sorted := searchRanges()
+
+	tests := []struct {
+		name   string
+		t      time.Time
+		want   Range
+		wantOk bool
+	}{
+		{name: "before all", t: tm(8, 0)},
+		{name: "at the start", t: tm(9, 0), want: sorted[0], wantOk: true},
+		{name: "within", t: tm(11, 30), want: sorted[1], wantOk: true},
+		{name: "at the end", t: tm(12, 0)},
+		{name: "in the gap", t: tm(13, 0)},
+		{name: "after all", t: tm(16, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := CoveredAt(sorted, tt.t)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, ok := CoveredAt(nil, tm(9, 0))
+	assert.False(t, ok)
+}
+
+func TestNextFreeAfter(t *testing.T) {
+	busy := searchRanges()
+	period := MustRange(Between(tm(8, 0), tm(14, 30)))
+
+	tests := []struct {
+		name   string
+		t      time.Time
+		want   time.Time
+		wantOk bool
+	}{
+		{name: "before the period", t: tm(7, 0), want: tm(8, 0), wantOk: true},
+		{name: "free", t: tm(10, 30), want: tm(10, 30), wantOk: true},
+		{name: "busy", t: tm(9, 30), want: tm(10, 0), wantOk: true},
+		{name: "busy till the end of the period", t: tm(14, 0)},
+		{name: "after the period", t: tm(14, 30)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := NextFreeAfter(busy, period, tt.t)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNextBusyAfter(t *testing.T) {
+	busy := searchRanges()
+	period := MustRange(Between(tm(8, 0), tm(14, 0)))
+
+	tests := []struct {
+		name   string
+		t      time.Time
+		want   time.Time
+		wantOk bool
+	}{
+		{name: "before the period", t: tm(7, 0), want: tm(9, 0), wantOk: true},
+		{name: "busy", t: tm(9, 30), want: tm(9, 30), wantOk: true},
+		{name: "free", t: tm(10, 0), want: tm(11, 0), wantOk: true},
+		{name: "busy after the end of the period", t: tm(12, 30)},
+		{name: "nothing busy later", t: tm(15, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := NextBusyAfter(busy, period, tt.t)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNextFreeSlot(t *testing.T) {
+	busy := searchRanges()
+	period := MustRange(Between(tm(8, 0), tm(16, 0)))
+
+	tests := []struct {
+		name   string
+		after  time.Time
+		d      time.Duration
+		want   Range
+		wantOk bool
+	}{
+		{name: "first window", after: tm(8, 0), d: time.Hour, want: New(tm(8, 0), time.Hour), wantOk: true},
+		{name: "skips the short windows", after: tm(8, 30), d: 90 * time.Minute, want: New(tm(12, 0), 90*time.Minute), wantOk: true},
+		{name: "longer than the windows left", after: tm(12, 0), d: 3 * time.Hour},
+		{name: "till the end of the period", after: tm(14, 30), d: time.Hour, want: New(tm(15, 0), time.Hour), wantOk: true},
+		{name: "no such window", after: tm(8, 0), d: 4 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := NextFreeSlot(busy, period, tt.after, tt.d)
+			assert.Equal(t, tt.wantOk, ok)
+			if tt.wantOk {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, ok, err := NextFreeSlotContext(ctx, busy, period, tm(8, 0), time.Hour)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.False(t, ok)
+	})
+}
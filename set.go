@@ -0,0 +1,112 @@
+package trn
+
+import "time"
+
+// Set is a normalized set of date ranges, i.e. the ranges in it are sorted,
+// don't overlap and don't touch each other, ranges of zero duration are
+// dropped. It is usually used to keep the free time of some resource.
+// The zero value is an empty set.
+//
+// Sets are copy-on-write: the ranges are never modified in place, so the
+// copies of the set, e.g. made by Clone, share the same ranges until either
+// of them is modified. The set might be frozen to share it across
+// goroutines, modification of the frozen set panics, its clones are not
+// frozen.
+type Set struct {
+	rngs   []Range
+	frozen bool
+}
+
+// NewSet makes a new set of the given ranges, which may be unsorted and
+// overlapping.
+func NewSet(rngs ...Range) Set {
+	var s Set
+	s.Add(rngs...)
+	return s
+}
+
+// Clone returns the copy of the set, which is not frozen. The ranges are
+// copied only once either of the sets is modified.
+func (s Set) Clone() Set { return Set{rngs: s.rngs} }
+
+// Freeze makes the set read-only, any following modification of the set
+// panics.
+func (s *Set) Freeze() { s.frozen = true }
+
+// Frozen returns true if the set is read-only.
+func (s Set) Frozen() bool { return s.frozen }
+
+// Add adds the ranges to the set.
+func (s *Set) Add(rngs ...Range) {
+	s.mustNotBeFrozen()
+	if len(rngs) == 0 {
+		return
+	}
+	s.rngs = normalize(append(append([]Range(nil), s.rngs...), rngs...))
+}
+
+// Remove removes the ranges from the set, the parts of the set's ranges,
+// which are not covered by the given ranges, are kept.
+func (s *Set) Remove(rngs ...Range) {
+	s.mustNotBeFrozen()
+	if len(rngs) == 0 {
+		return
+	}
+
+	s.rngs = difference(s.rngs, rngs)
+}
+
+// Replace replaces the contents of the set with the given ranges.
+func (s *Set) Replace(rngs ...Range) {
+	s.mustNotBeFrozen()
+	s.rngs = normalize(rngs)
+}
+
+// Ranges returns the copy of the ranges in the set.
+func (s Set) Ranges() []Range {
+	if len(s.rngs) == 0 {
+		return nil
+	}
+	return append([]Range(nil), s.rngs...)
+}
+
+// Len returns the number of ranges in the set.
+func (s Set) Len() int { return len(s.rngs) }
+
+// Duration returns the total duration of the ranges in the set.
+func (s Set) Duration() time.Duration { return load(s.rngs) }
+
+// Covers returns true if the range is fully within one of the set's ranges.
+func (s Set) Covers(r Range) bool {
+	for _, rng := range s.rngs {
+		if rng.Contains(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// ETag returns the hash of the set's ranges, which changes whenever the
+// set is changed, e.g. for If-Match semantics of booking APIs. The
+// locations of the ranges are not taken into account.
+func (s Set) ETag() string { return hashRanges(s.rngs) }
+
+func (s *Set) mustNotBeFrozen() {
+	if s.frozen {
+		panic("trn: modification of the frozen set")
+	}
+}
+
+// normalize drops the ranges of zero duration and merges the rest.
+func normalize(rngs []Range) []Range {
+	var nonEmpty []Range
+	for _, r := range rngs {
+		if r.dur > 0 {
+			nonEmpty = append(nonEmpty, r)
+		}
+	}
+	if len(nonEmpty) == 0 {
+		return nil
+	}
+	return MergeOverlappingRanges(nonEmpty)
+}
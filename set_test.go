@@ -0,0 +1,65 @@
+package trn
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSet(t *testing.T) {
+	s := NewSet(
+		MustRange(Between(tm(15, 0), tm(18, 0))),
+		MustRange(Between(tm(9, 0), tm(12, 0))),
+		MustRange(Between(tm(11, 0), tm(13, 0))),
+		New(tm(20, 0), 0),
+	)
+	assert.Equal(t, []string{"[09:00, 13:00]", "[15:00, 18:00]"}, formatSet(s))
+	assert.Equal(t, 2, s.Len())
+	assert.Equal(t, 7*time.Hour, s.Duration())
+	assert.True(t, s.Covers(MustRange(Between(tm(10, 0), tm(13, 0)))))
+	assert.False(t, s.Covers(MustRange(Between(tm(12, 0), tm(16, 0)))))
+
+	s.Remove(MustRange(Between(tm(10, 0), tm(11, 0))), MustRange(Between(tm(17, 0), tm(19, 0))))
+	assert.Equal(t, []string{"[09:00, 10:00]", "[11:00, 13:00]", "[15:00, 17:00]"}, formatSet(s))
+
+	s.Add(MustRange(Between(tm(13, 0), tm(15, 0))))
+	assert.Equal(t, []string{"[09:00, 10:00]", "[11:00, 17:00]"}, formatSet(s))
+
+	rngs := s.Ranges()
+	rngs[0] = Range{}
+	assert.Equal(t, []string{"[09:00, 10:00]", "[11:00, 17:00]"}, formatSet(s), "ranges must be copied")
+
+	s.Replace()
+	assert.Nil(t, s.Ranges())
+
+	var zero Set
+	zero.Remove(New(tm(9, 0), time.Hour))
+	assert.Zero(t, zero.Len())
+}
+
+func formatSet(s Set) []string {
+	var res []string
+	for _, r := range s.Ranges() {
+		res = append(res, r.Format("15:04"))
+	}
+	return res
+}
+
+func TestSet_Clone(t *testing.T) {
+	s := NewSet(New(tm(9, 0), time.Hour), New(tm(13, 0), time.Hour))
+	s.Freeze()
+	assert.True(t, s.Frozen())
+	assert.Panics(t, func() { s.Add(New(tm(11, 0), time.Hour)) })
+	assert.Panics(t, func() { s.Remove(New(tm(9, 0), time.Hour)) })
+	assert.Panics(t, func() { s.Replace() })
+
+	c := s.Clone()
+	assert.False(t, c.Frozen())
+	assert.Equal(t, s.Ranges(), c.Ranges())
+
+	c.Remove(New(tm(9, 0), 30*time.Minute))
+	c.Add(New(tm(14, 0), time.Hour))
+	assert.Equal(t, []string{"[09:30, 10:00]", "[13:00, 15:00]"}, formatSet(c))
+	assert.Equal(t, []string{"[09:00, 10:00]", "[13:00, 14:00]"}, formatSet(s), "the original set is not modified")
+}
@@ -0,0 +1,77 @@
+package trn
+
+// OpKind is the kind of the hypothetical operation over the free ranges.
+type OpKind int
+
+// Kinds of operations.
+const (
+	// OpBook books the range, it must be fully free.
+	OpBook OpKind = iota
+	// OpCancel cancels the booking of the range, so it's free again. The
+	// range must be fully booked, i.e. not overlap the free ranges.
+	OpCancel
+)
+
+// String returns the name of the kind.
+func (k OpKind) String() string {
+	switch k {
+	case OpBook:
+		return "book"
+	case OpCancel:
+		return "cancel"
+	default:
+		return "unknown"
+	}
+}
+
+// Op is the hypothetical operation over the set of free ranges, e.g. the
+// item of the cart with multiple slots.
+type Op struct {
+	Kind  OpKind
+	Range Range
+}
+
+// Conflict describes the operation, which could not be applied.
+type Conflict struct {
+	// Index is the index of the operation in the batch.
+	Index int
+	Op    Op
+	// Parts are the parts of the operation's range, which caused the
+	// conflict: the booked ones for OpBook and the free ones for OpCancel.
+	Parts []Range
+}
+
+// Simulate applies the operations to the copy of the set in order and
+// returns the resulting set, e.g. to preview the effect of the cart with
+// multiple slots. The operations, which conflict with the set, updated by
+// the preceding operations, are skipped and reported as conflicts. The
+// original set is not modified.
+func (s Set) Simulate(ops []Op) (Set, []Conflict) {
+	res := s.Clone()
+
+	var conflicts []Conflict
+	for i, op := range ops {
+		var parts []Range
+		switch op.Kind {
+		case OpBook:
+			parts = UncoveredParts(op.Range, res.rngs)
+		case OpCancel:
+			parts = op.Range.TruncateAll(res.rngs)
+		default:
+			parts = []Range{op.Range}
+		}
+
+		if len(parts) > 0 {
+			conflicts = append(conflicts, Conflict{Index: i, Op: op, Parts: parts})
+			continue
+		}
+
+		if op.Kind == OpBook {
+			res.Remove(op.Range)
+		} else {
+			res.Add(op.Range)
+		}
+	}
+
+	return res, conflicts
+}
@@ -0,0 +1,45 @@
+package trn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSet_Simulate(t *testing.T) {
+	s := NewSet(MustRange(Between(tm(9, 0), tm(12, 0))), MustRange(Between(tm(13, 0), tm(18, 0))))
+	s.Freeze()
+
+	res, conflicts := s.Simulate([]Op{
+		{Kind: OpBook, Range: MustRange(Between(tm(9, 0), tm(10, 0)))},
+		{Kind: OpBook, Range: MustRange(Between(tm(9, 30), tm(10, 30)))},  // overlaps the previous one
+		{Kind: OpBook, Range: MustRange(Between(tm(11, 30), tm(13, 30)))}, // spans the lunch
+		{Kind: OpCancel, Range: MustRange(Between(tm(12, 0), tm(13, 0)))},
+		{Kind: OpBook, Range: MustRange(Between(tm(11, 30), tm(13, 30)))},
+		{Kind: OpCancel, Range: MustRange(Between(tm(15, 0), tm(16, 0)))}, // not booked
+		{Kind: OpKind(42), Range: MustRange(Between(tm(16, 0), tm(17, 0)))},
+	})
+
+	assert.Equal(t, []string{"[10:00, 11:30]", "[13:30, 18:00]"}, formatSet(res))
+	assert.False(t, res.Frozen())
+	assert.Equal(t, []string{"[09:00, 12:00]", "[13:00, 18:00]"}, formatSet(s), "original is not modified")
+
+	var got []string
+	for _, c := range conflicts {
+		got = append(got, c.Op.Kind.String())
+		for _, p := range c.Parts {
+			got = append(got, p.Format("15:04"))
+		}
+	}
+	assert.Equal(t, []string{
+		"book", "[09:30, 10:00]",
+		"book", "[12:00, 13:00]",
+		"cancel", "[15:00, 16:00]",
+		"unknown", "[16:00, 17:00]",
+	}, got)
+	assert.Equal(t, []int{1, 2, 5, 6}, []int{conflicts[0].Index, conflicts[1].Index, conflicts[2].Index, conflicts[3].Index})
+
+	res, conflicts = Set{}.Simulate(nil)
+	assert.Zero(t, res.Len())
+	assert.Empty(t, conflicts)
+}
@@ -0,0 +1,91 @@
+// Package sla provides helpers to compute service availability over a
+// period from the set of downtime ranges.
+package sla
+
+import (
+	"time"
+
+	"github.com/cappuccinotm/trn"
+	"github.com/cappuccinotm/trn/store"
+)
+
+// Report describes the availability of the service over the period.
+type Report struct {
+	// Measured is the total time taken into account, i.e. the period
+	// without maintenance windows and non-business hours.
+	Measured time.Duration
+	// Downtime is the total time of downtime within the measured time.
+	Downtime time.Duration
+	// Availability is the percentage of the measured time, when the
+	// service was up, within [0, 100].
+	Availability float64
+}
+
+// Uptime returns the time, when the service was up.
+func (r Report) Uptime() time.Duration { return r.Measured - r.Downtime }
+
+// Option configures the availability computation.
+type Option func(o *options)
+
+type options struct {
+	maintenance []trn.Range
+	schedule    *store.WeeklySchedule
+	loc         *time.Location
+}
+
+// ExcludeMaintenance excludes the maintenance windows from the measured
+// time, the downtime during maintenance doesn't affect availability.
+func ExcludeMaintenance(windows []trn.Range) Option {
+	return func(o *options) { o.maintenance = append(o.maintenance, windows...) }
+}
+
+// BusinessHours takes into account only the business hours, described by
+// the weekly schedule in the given location, UTC if nil.
+func BusinessHours(schedule store.WeeklySchedule, loc *time.Location) Option {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return func(o *options) { o.schedule, o.loc = &schedule, loc }
+}
+
+// Availability computes the availability of the service over the period,
+// given the downtime ranges, which may be unsorted and overlapping.
+// If the measured time is empty, the availability is 100%.
+func Availability(period trn.Range, downtime []trn.Range, opts ...Option) Report {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	measured := []trn.Range{period}
+	if o.schedule != nil {
+		measured = o.schedule.Expand(period, o.loc)
+	}
+	if len(o.maintenance) > 0 {
+		var rest []trn.Range
+		for _, rng := range measured {
+			rest = append(rest, rng.Exclude(o.maintenance)...)
+		}
+		measured = rest
+	}
+
+	res := Report{Measured: total(measured)}
+	for _, rng := range measured {
+		res.Downtime += total(rng.TruncateAll(downtime))
+	}
+
+	res.Availability = 100
+	if res.Measured > 0 {
+		res.Availability = float64(res.Uptime()) / float64(res.Measured) * 100
+	}
+
+	return res
+}
+
+func total(rngs []trn.Range) time.Duration {
+	var res time.Duration
+	for _, rng := range rngs {
+		res += rng.Duration()
+	}
+	return res
+}
@@ -0,0 +1,64 @@
+package sla
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cappuccinotm/trn"
+	"github.com/cappuccinotm/trn/store"
+	"github.com/stretchr/testify/assert"
+)
+
+// dt is Saturday
+var dt = time.Date(2021, 6, 12, 0, 0, 0, 0, time.UTC)
+
+func dhm(d, h, m int) time.Time {
+	return time.Date(dt.Year(), dt.Month(), d, h, m, 0, 0, time.UTC)
+}
+
+func rng(from, to time.Time) trn.Range { return trn.MustRange(trn.Between(from, to)) }
+
+func TestAvailability(t *testing.T) {
+	// saturday and sunday
+	period := rng(dhm(12, 0, 0), dhm(14, 0, 0))
+
+	downtime := []trn.Range{
+		rng(dhm(12, 1, 0), dhm(12, 2, 0)),
+		rng(dhm(12, 1, 30), dhm(12, 3, 0)), // overlaps the previous one
+		rng(dhm(13, 10, 0), dhm(13, 12, 0)),
+		rng(dhm(11, 23, 0), dhm(12, 0, 30)), // partially out of the period
+	}
+
+	t.Run("whole period", func(t *testing.T) {
+		rep := Availability(period, downtime)
+		assert.Equal(t, 48*time.Hour, rep.Measured)
+		assert.Equal(t, 4*time.Hour+30*time.Minute, rep.Downtime)
+		assert.Equal(t, 43*time.Hour+30*time.Minute, rep.Uptime())
+		assert.InDelta(t, 90.625, rep.Availability, 1e-9)
+	})
+
+	t.Run("maintenance excluded", func(t *testing.T) {
+		rep := Availability(period, downtime, ExcludeMaintenance([]trn.Range{
+			rng(dhm(12, 0, 0), dhm(12, 4, 0)),
+		}))
+		assert.Equal(t, 44*time.Hour, rep.Measured)
+		assert.Equal(t, 2*time.Hour, rep.Downtime)
+	})
+
+	t.Run("business hours", func(t *testing.T) {
+		var w store.WeeklySchedule
+		w[time.Sunday] = []store.TimeRange{{Start: store.NewClock(9, 0, 0), End: store.NewClock(11, 0, 0)}}
+
+		rep := Availability(period, downtime, BusinessHours(w, time.UTC))
+		assert.Equal(t, 2*time.Hour, rep.Measured)
+		assert.Equal(t, time.Hour, rep.Downtime)
+		assert.InDelta(t, 50, rep.Availability, 1e-9)
+
+		assert.Equal(t, rep, Availability(period, downtime, BusinessHours(w, nil)), "nil location is UTC")
+	})
+
+	t.Run("nothing measured", func(t *testing.T) {
+		rep := Availability(period, downtime, BusinessHours(store.WeeklySchedule{}, time.UTC))
+		assert.Equal(t, Report{Availability: 100}, rep)
+	})
+}
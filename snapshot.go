@@ -0,0 +1,78 @@
+package trn
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// Snapshot is the set of ranges within the period as of the generation
+// time, e.g. the availability of the resource cached for a week. Snapshots
+// are identified by the content hash, which doesn't depend on the
+// generation time and the locations of the ranges.
+type Snapshot struct {
+	period      Range
+	set         Set
+	generatedAt time.Time
+	hash        string
+}
+
+// NewSnapshot makes a new snapshot of the set's ranges within the period.
+func NewSnapshot(period Range, set Set, generatedAt time.Time) Snapshot {
+	s := Snapshot{
+		period:      period,
+		set:         Set{rngs: period.TruncateAll(set.rngs)},
+		generatedAt: generatedAt,
+	}
+	s.hash = hashRanges(append([]Range{period}, s.set.rngs...))
+	return s
+}
+
+// Period returns the period of the snapshot.
+func (s Snapshot) Period() Range { return s.period }
+
+// Set returns the set of ranges within the period.
+func (s Snapshot) Set() Set { return s.set }
+
+// GeneratedAt returns the generation time of the snapshot.
+func (s Snapshot) GeneratedAt() time.Time { return s.generatedAt }
+
+// Hash returns the hex-encoded SHA-256 hash of the period and the ranges.
+func (s Snapshot) Hash() string { return s.hash }
+
+// Equal returns true if the snapshots have the same period and ranges,
+// regardless of their generation time.
+func (s Snapshot) Equal(other Snapshot) bool { return s.hash == other.hash }
+
+// Diff returns the ranges, which are present in the other snapshot, but
+// not in this one, and vice versa.
+func (s Snapshot) Diff(other Snapshot) (added, removed []Range) {
+	return difference(other.set.rngs, s.set.rngs), difference(s.set.rngs, other.set.rngs)
+}
+
+// difference returns the parts of the ranges a, which are not covered by
+// the ranges b.
+func difference(a, b []Range) []Range {
+	var res []Range
+	for _, r := range a {
+		for _, part := range r.Exclude(b) {
+			if part.dur > 0 {
+				res = append(res, part)
+			}
+		}
+	}
+	return res
+}
+
+// hashRanges returns the hex-encoded SHA-256 hash of the ranges'
+// boundaries as Unix time, ignoring their locations.
+func hashRanges(rngs []Range) string {
+	h := sha256.New()
+	var b [24]byte
+	for _, r := range rngs {
+		putUnix(b[:], r.st)
+		putUnix(b[12:], r.End())
+		_, _ = h.Write(b[:])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,56 @@
+package trn
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshot(t *testing.T) {
+	period := MustRange(Between(tm(9, 0), tm(18, 0)))
+	set := NewSet(
+		MustRange(Between(tm(8, 0), tm(10, 0))),
+		MustRange(Between(tm(12, 0), tm(14, 0))),
+		MustRange(Between(tm(17, 0), tm(19, 0))),
+	)
+
+	s := NewSnapshot(period, set, tm(7, 0))
+	assert.Equal(t, period, s.Period())
+	assert.Equal(t, tm(7, 0), s.GeneratedAt())
+	assert.Equal(t, []Range{
+		MustRange(Between(tm(9, 0), tm(10, 0))),
+		MustRange(Between(tm(12, 0), tm(14, 0))),
+		MustRange(Between(tm(17, 0), tm(18, 0))),
+	}, s.Set().Ranges())
+	assert.Len(t, s.Hash(), 64)
+
+	t.Run("equal regardless of generation time and location", func(t *testing.T) {
+		loc := time.FixedZone("", 2*60*60)
+		other := NewSnapshot(period.In(loc), NewSet(set.Ranges()...), tm(8, 0))
+		assert.True(t, s.Equal(other))
+		assert.Equal(t, s.Hash(), other.Hash())
+
+		added, removed := s.Diff(other)
+		assert.Empty(t, added)
+		assert.Empty(t, removed)
+	})
+
+	t.Run("different ranges", func(t *testing.T) {
+		changed := set
+		changed.Remove(MustRange(Between(tm(13, 0), tm(14, 0))))
+		changed.Add(MustRange(Between(tm(15, 0), tm(16, 0))))
+
+		other := NewSnapshot(period, changed, tm(8, 0))
+		assert.False(t, s.Equal(other))
+
+		added, removed := s.Diff(other)
+		assert.Equal(t, []Range{MustRange(Between(tm(15, 0), tm(16, 0)))}, added)
+		assert.Equal(t, []Range{MustRange(Between(tm(13, 0), tm(14, 0)))}, removed)
+	})
+
+	t.Run("different period", func(t *testing.T) {
+		other := NewSnapshot(MustRange(Between(tm(9, 0), tm(19, 0))), set, tm(7, 0))
+		assert.False(t, s.Equal(other))
+	})
+}
@@ -0,0 +1,131 @@
+package trn
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// SplitOption is an option for Split and Stratify.
+type SplitOption func(o *splitOptions)
+
+type splitOptions struct {
+	jitter   time.Duration
+	seed     *int64
+	rnd      *rand.Rand
+	maxSlots int
+	forbid   []Range
+}
+
+// Jitter randomly shifts the start of each resulting range within ±max,
+// e.g. to spread the load of staggered job windows. The resulting ranges
+// are kept within the original range, so max could be arbitrarily large.
+func Jitter(max time.Duration) SplitOption {
+	return func(o *splitOptions) { o.jitter = max }
+}
+
+// Seed sets the seed of the random source used by Jitter, so the results
+// are reproducible, e.g. in tests.
+func Seed(seed int64) SplitOption {
+	return func(o *splitOptions) { o.seed = &seed }
+}
+
+// MaxSlots limits the number of resulting ranges, Split and Stratify
+// return ErrTooManySlots instead of producing more than n ranges, e.g.
+// when splitting a decade by a second. The slots, skipped because of
+// ForbidBoundaries, don't count. Zero or negative n means no limit.
+func MaxSlots(n int) SplitOption {
+	return func(o *splitOptions) { o.maxSlots = n }
+}
+
+// ForbidBoundaries makes Split and Stratify avoid the slot boundaries
+// falling within the windows, e.g. no slot may start or end during the
+// lunch. A slot, which starts within a window or ends strictly within it,
+// is shifted forward to the nearest allowed start, and the following slots
+// are counted from the shifted one. Jitter is not applied to the slot, if
+// it would move its boundaries into a window.
+func ForbidBoundaries(windows ...Range) SplitOption {
+	return func(o *splitOptions) { o.forbid = append(o.forbid, windows...) }
+}
+
+func newSplitOptions(opts []SplitOption) splitOptions {
+	var o splitOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	// the shifted starts are kept within the range anyway, so the huge
+	// jitter is only limited to fit the random source
+	if o.jitter > math.MaxInt64/2 {
+		o.jitter = math.MaxInt64 / 2
+	}
+
+	if o.jitter > 0 {
+		seed := time.Now().UnixNano()
+		if o.seed != nil {
+			seed = *o.seed
+		}
+		o.rnd = rand.New(rand.NewSource(seed)) //nolint:gosec // not for security purposes
+	}
+
+	return o
+}
+
+// exceeds returns true if the estimated number of slots exceeds MaxSlots
+// for sure, i.e. no forbidden windows could reduce it.
+func (o splitOptions) exceeds(slots int64) bool {
+	return o.maxSlots > 0 && len(o.forbid) == 0 && slots > int64(o.maxSlots)
+}
+
+// jittered returns the start of the slot, randomly shifted within the
+// jitter, but kept within the bounds. The start is left as is, if the
+// shifted slot boundaries fall within the forbidden windows.
+func (o splitOptions) jittered(bounds Range, st time.Time, duration time.Duration) time.Time {
+	if o.jitter <= 0 {
+		return st
+	}
+
+	res := st.Add(time.Duration(o.rnd.Int63n(int64(2*o.jitter)+1)) - o.jitter)
+
+	if res.Before(bounds.st) {
+		res = bounds.st
+	}
+	if latest := bounds.End().Add(-duration); res.After(latest) {
+		res = latest
+	}
+
+	if o.forbidden(res, duration) {
+		return st
+	}
+	return res
+}
+
+// forbidden returns true if the slot boundaries fall within any of the
+// forbidden windows, the same way as allowed checks them.
+func (o splitOptions) forbidden(st time.Time, duration time.Duration) bool {
+	end := st.Add(duration)
+	for _, w := range o.forbid {
+		if w.ContainsTime(st) || end.After(w.st) && end.Before(w.End()) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowed returns the nearest start of the slot not before st, which
+// boundaries don't fall within the forbidden windows.
+func (o splitOptions) allowed(st time.Time, duration time.Duration) time.Time {
+	for shifted := true; shifted; {
+		shifted = false
+		for _, w := range o.forbid {
+			end := st.Add(duration)
+			switch {
+			case w.ContainsTime(st):
+				st, shifted = w.End(), true
+			case end.After(w.st) && end.Before(w.End()):
+				st, shifted = w.End().Add(-duration), true
+			}
+		}
+	}
+	return st
+}
@@ -0,0 +1,145 @@
+package trn
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRange_Stratify_Jitter(t *testing.T) {
+	rng := MustRange(Between(tm(0, 0), tm(12, 0)))
+
+	got, err := rng.Stratify(30*time.Minute, time.Hour, Jitter(10*time.Minute), Seed(42))
+	require.NoError(t, err)
+	require.Len(t, got, 12)
+
+	shifted := false
+	for i, r := range got {
+		assert.Equal(t, 30*time.Minute, r.Duration())
+		assert.True(t, rng.Contains(r), "slot %s is out of the range", r)
+
+		origin := tm(i, 0)
+		assert.True(t, r.Start().Sub(origin) <= 10*time.Minute && origin.Sub(r.Start()) <= 10*time.Minute,
+			"slot %s is shifted too far", r)
+		shifted = shifted || !r.Start().Equal(origin)
+	}
+	assert.True(t, shifted, "no slot was shifted")
+
+	t.Run("same seed gives the same result", func(t *testing.T) {
+		again, err := rng.Split(30*time.Minute, 30*time.Minute, Jitter(10*time.Minute), Seed(42))
+		require.NoError(t, err)
+		assert.Equal(t, got, again)
+	})
+
+	t.Run("without jitter", func(t *testing.T) {
+		plain, err := rng.Stratify(30*time.Minute, time.Hour, Seed(42))
+		require.NoError(t, err)
+		for i, r := range plain {
+			assert.Equal(t, tm(i, 0), r.Start())
+		}
+	})
+
+	t.Run("huge jitter", func(t *testing.T) {
+		huge, err := rng.Stratify(30*time.Minute, time.Hour, Jitter(math.MaxInt64), Seed(42))
+		require.NoError(t, err)
+		require.Len(t, huge, 12)
+		for _, r := range huge {
+			assert.True(t, rng.Contains(r), "slot %s is out of the range", r)
+		}
+	})
+}
+
+func TestRange_Stratify_MaxSlots(t *testing.T) {
+	rng := MustRange(Between(tm(9, 0), tm(12, 0)))
+
+	got, err := rng.Stratify(30*time.Minute, 30*time.Minute, MaxSlots(6))
+	require.NoError(t, err)
+	assert.Len(t, got, 6)
+
+	_, err = rng.Stratify(30*time.Minute, 30*time.Minute, MaxSlots(5))
+	assert.ErrorIs(t, err, ErrTooManySlots)
+
+	_, err = rng.Split(time.Minute, 0, MaxSlots(100))
+	assert.ErrorIs(t, err, ErrTooManySlots)
+
+	decade := New(tm(0, 0), 10*365*24*time.Hour)
+	_, err = decade.Split(time.Second, 0, MaxSlots(1_000_000))
+	assert.ErrorIs(t, err, ErrTooManySlots)
+
+	got, err = rng.Split(4*time.Hour, 0, MaxSlots(1))
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	t.Run("huge range", func(t *testing.T) {
+		huge := New(tm(0, 0), 1<<62)
+		_, err := huge.Split(time.Nanosecond, 0, MaxSlots(1_000_000))
+		assert.ErrorIs(t, err, ErrTooManySlots)
+
+		// the estimation is huge, but almost all boundaries are forbidden
+		lunch := New(huge.Start().Add(2), huge.Duration()-4)
+		got, err := huge.Split(time.Nanosecond, 0, ForbidBoundaries(lunch))
+		require.NoError(t, err)
+		assert.Len(t, got, 4)
+	})
+}
+
+func TestRange_Split_ForbidBoundaries(t *testing.T) {
+	rng := MustRange(Between(tm(10, 0), tm(15, 0)))
+	lunch := MustRange(Between(tm(12, 0), tm(13, 0)))
+
+	t.Run("shifted after the window", func(t *testing.T) {
+		got, err := rng.Split(45*time.Minute, 0, ForbidBoundaries(lunch))
+		require.NoError(t, err)
+		assert.Equal(t, formattedRanges([]Range{
+			MustRange(Between(tm(10, 0), tm(10, 45))),
+			MustRange(Between(tm(10, 45), tm(11, 30))),
+			// 11:30 would end during the lunch, 12:15 would start during it
+			MustRange(Between(tm(13, 0), tm(13, 45))),
+			MustRange(Between(tm(13, 45), tm(14, 30))),
+		}, "15:04"), formattedRanges(got, "15:04"))
+	})
+
+	t.Run("several windows", func(t *testing.T) {
+		got, err := rng.Stratify(time.Hour, time.Hour, ForbidBoundaries(
+			MustRange(Between(tm(13, 0), tm(13, 30))),
+			MustRange(Between(tm(10, 0), tm(10, 15))),
+		))
+		require.NoError(t, err)
+		assert.Equal(t, formattedRanges([]Range{
+			MustRange(Between(tm(10, 15), tm(11, 15))),
+			MustRange(Between(tm(11, 15), tm(12, 15))),
+			MustRange(Between(tm(12, 30), tm(13, 30))), // 12:15 would end within the window
+			MustRange(Between(tm(13, 30), tm(14, 30))),
+		}, "15:04"), formattedRanges(got, "15:04"))
+	})
+
+	t.Run("max slots count the shifted ones", func(t *testing.T) {
+		got, err := rng.Split(45*time.Minute, 0, ForbidBoundaries(lunch), MaxSlots(4))
+		require.NoError(t, err)
+		assert.Len(t, got, 4)
+
+		_, err = rng.Split(45*time.Minute, 0, ForbidBoundaries(lunch), MaxSlots(3))
+		assert.ErrorIs(t, err, ErrTooManySlots)
+
+		got, err = StratifySet([]Range{rng}, 45*time.Minute, 45*time.Minute, ForbidBoundaries(lunch), MaxSlots(4))
+		require.NoError(t, err)
+		assert.Len(t, got, 4)
+	})
+
+	t.Run("with jitter", func(t *testing.T) {
+		day := MustRange(Between(tm(9, 0), tm(17, 0)))
+		for seed := int64(0); seed < 50; seed++ {
+			got, err := day.Stratify(30*time.Minute, 45*time.Minute, ForbidBoundaries(lunch),
+				Jitter(30*time.Minute), Seed(seed))
+			require.NoError(t, err)
+			for _, r := range got {
+				assert.False(t, lunch.ContainsTime(r.Start()), "slot %s starts during the lunch", r)
+				assert.False(t, r.End().After(lunch.Start()) && r.End().Before(lunch.End()),
+					"slot %s ends during the lunch", r)
+			}
+		}
+	})
+}
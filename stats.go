@@ -0,0 +1,66 @@
+package trn
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// RangeStats describes the distribution of durations of a set of ranges.
+type RangeStats struct {
+	Count  int
+	Min    time.Duration
+	Max    time.Duration
+	Mean   time.Duration
+	Median time.Duration
+	// Total is the sum of durations of all ranges, overlaps count several
+	// times.
+	Total time.Duration
+	// Merged is the duration of the union of all ranges.
+	Merged time.Duration
+
+	sorted []time.Duration
+}
+
+// Stats computes the statistics of durations of the given ranges.
+func Stats(ranges []Range) RangeStats {
+	if len(ranges) == 0 {
+		return RangeStats{}
+	}
+
+	res := RangeStats{Count: len(ranges), sorted: make([]time.Duration, len(ranges))}
+	for i, rng := range ranges {
+		res.sorted[i] = rng.dur
+		res.Total += rng.dur
+	}
+	sort.Slice(res.sorted, func(i, j int) bool { return res.sorted[i] < res.sorted[j] })
+
+	for _, rng := range MergeOverlappingRanges(ranges) {
+		res.Merged += rng.dur
+	}
+
+	res.Min, res.Max = res.sorted[0], res.sorted[len(res.sorted)-1]
+	res.Mean = res.Total / time.Duration(res.Count)
+	res.Median = res.Percentile(50)
+
+	return res
+}
+
+// Percentile returns the p-th percentile of durations, p is within
+// [0, 100], the values between the closest ranks are linearly interpolated.
+// Returns zero if there are no ranges.
+func (s RangeStats) Percentile(p float64) time.Duration { return percentile(s.sorted, p) }
+
+// percentile returns the p-th percentile of the sorted durations.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	p = math.Max(0, math.Min(100, p))
+	rank := p / 100 * float64(len(sorted)-1)
+	lo, hi := int(math.Floor(rank)), int(math.Ceil(rank))
+	frac := rank - float64(lo)
+
+	return sorted[lo] + time.Duration(math.Round(float64(sorted[hi]-sorted[lo])*frac))
+}
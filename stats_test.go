@@ -0,0 +1,33 @@
+package trn
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats(t *testing.T) {
+	st := Stats([]Range{
+		MustRange(Between(tm(9, 0), tm(9, 10))),
+		MustRange(Between(tm(9, 5), tm(9, 45))), // overlaps the previous one
+		MustRange(Between(tm(10, 0), tm(10, 20))),
+		MustRange(Between(tm(11, 0), tm(12, 0))),
+	})
+
+	assert.Equal(t, 4, st.Count)
+	assert.Equal(t, 10*time.Minute, st.Min)
+	assert.Equal(t, time.Hour, st.Max)
+	assert.Equal(t, 130*time.Minute, st.Total)
+	assert.Equal(t, 125*time.Minute, st.Merged)
+	assert.Equal(t, 32*time.Minute+30*time.Second, st.Mean)
+	assert.Equal(t, 30*time.Minute, st.Median)
+	assert.Equal(t, 10*time.Minute, st.Percentile(0))
+	assert.Equal(t, time.Hour, st.Percentile(100))
+	assert.Equal(t, 54*time.Minute, st.Percentile(90))
+	assert.Equal(t, time.Hour, st.Percentile(150))
+
+	empty := Stats(nil)
+	assert.Equal(t, RangeStats{}, empty)
+	assert.Equal(t, time.Duration(0), empty.Percentile(50))
+}
@@ -0,0 +1,82 @@
+package trn
+
+import "time"
+
+// Status is the free-busy status of the time, as calendar systems express
+// it. Statuses are ordered by precedence, the higher one wins when ranges
+// of different statuses overlap.
+type Status int
+
+// Statuses, in order of precedence.
+const (
+	StatusFree Status = iota
+	StatusTentative
+	StatusBusy
+)
+
+// String returns the name of the status.
+func (s Status) String() string {
+	switch s {
+	case StatusFree:
+		return "free"
+	case StatusTentative:
+		return "tentative"
+	case StatusBusy:
+		return "busy"
+	default:
+		return "unknown"
+	}
+}
+
+// StatusSet keeps the ranges with their free-busy statuses, e.g. tentative
+// and confirmed meetings. The zero value is an empty set.
+type StatusSet struct {
+	items []Labeled[Status]
+}
+
+// Add adds the range with the given status.
+func (s *StatusSet) Add(r Range, status Status) {
+	s.items = append(s.items, Label(r, status))
+}
+
+// StatusAt returns the status at the given time, i.e. the status of the
+// highest precedence among the ranges containing it, the ends of ranges are
+// exclusive. Returns StatusFree if there are no such ranges.
+func (s StatusSet) StatusAt(t time.Time) Status {
+	res := StatusFree
+	for _, item := range s.items {
+		if item.Value > res && item.ContainsTime(t) {
+			res = item.Value
+		}
+	}
+	return res
+}
+
+// Segments returns the sorted non-overlapping segments with the resolved
+// statuses. Adjacent segments with the same status are merged, gaps are
+// omitted.
+func (s StatusSet) Segments() []Labeled[Status] {
+	resolved, _ := PriorityWins(func(st Status) int { return int(st) })(s.items)
+
+	var res []Labeled[Status]
+	for _, seg := range resolved {
+		if n := len(res); n > 0 && res[n-1].Value == seg.Value && res[n-1].End().Equal(seg.st) {
+			res[n-1].dur = seg.End().Sub(res[n-1].st)
+			continue
+		}
+		res = append(res, seg)
+	}
+	return res
+}
+
+// Ranges returns the merged ranges with the given status after resolution,
+// e.g. the busy time to show to other attendees.
+func (s StatusSet) Ranges(status Status) []Range {
+	var res []Range
+	for _, seg := range s.Segments() {
+		if seg.Value == status {
+			res = append(res, seg.Range)
+		}
+	}
+	return res
+}
@@ -0,0 +1,45 @@
+package trn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusSet(t *testing.T) {
+	var s StatusSet
+	s.Add(MustRange(Between(tm(9, 0), tm(18, 0))), StatusFree) // working hours
+	s.Add(MustRange(Between(tm(10, 0), tm(12, 0))), StatusTentative)
+	s.Add(MustRange(Between(tm(11, 0), tm(13, 0))), StatusBusy)
+	s.Add(MustRange(Between(tm(13, 0), tm(14, 0))), StatusBusy)
+	s.Add(MustRange(Between(tm(19, 0), tm(20, 0))), StatusTentative)
+
+	type segment struct {
+		rng    string
+		status string
+	}
+	var got []segment
+	for _, seg := range s.Segments() {
+		got = append(got, segment{rng: seg.Format("15:04"), status: seg.Value.String()})
+	}
+	assert.Equal(t, []segment{
+		{rng: "[09:00, 10:00]", status: "free"},
+		{rng: "[10:00, 11:00]", status: "tentative"},
+		{rng: "[11:00, 14:00]", status: "busy"},
+		{rng: "[14:00, 18:00]", status: "free"},
+		{rng: "[19:00, 20:00]", status: "tentative"},
+	}, got)
+
+	assert.Equal(t, StatusFree, s.StatusAt(tm(8, 0)))
+	assert.Equal(t, StatusFree, s.StatusAt(tm(9, 0)))
+	assert.Equal(t, StatusTentative, s.StatusAt(tm(10, 30)))
+	assert.Equal(t, StatusBusy, s.StatusAt(tm(11, 0)))
+	assert.Equal(t, StatusBusy, s.StatusAt(tm(13, 0)))
+	assert.Equal(t, StatusFree, s.StatusAt(tm(14, 0)))
+
+	assert.Equal(t, []Range{MustRange(Between(tm(11, 0), tm(14, 0)))}, s.Ranges(StatusBusy))
+	assert.Len(t, s.Ranges(StatusTentative), 2)
+
+	assert.Equal(t, "unknown", Status(42).String())
+	assert.Empty(t, StatusSet{}.Segments())
+}
@@ -0,0 +1,71 @@
+package trn
+
+import (
+	"sort"
+	"time"
+)
+
+// StepFunction is the piecewise-constant function of time, e.g. the price
+// changing over time. The function is zero outside of its segments.
+type StepFunction struct {
+	segs []Labeled[float64]
+}
+
+// NewStepFunction makes the step function of the weighted ranges, the
+// values of overlapping ranges are summed, as by Aggregate with AggSum.
+func NewStepFunction(items []Labeled[float64]) StepFunction {
+	return StepFunction{segs: Aggregate(items, AggSum)}
+}
+
+// Segments returns the copy of the sorted disjoint segments of the
+// function, where it's not zero.
+func (f StepFunction) Segments() []Labeled[float64] {
+	if len(f.segs) == 0 {
+		return nil
+	}
+	return append([]Labeled[float64](nil), f.segs...)
+}
+
+// ValueAt returns the value of the function at the instant.
+func (f StepFunction) ValueAt(t time.Time) float64 {
+	i := sort.Search(len(f.segs), func(i int) bool { return f.segs[i].End().After(t) })
+	if i == len(f.segs) || f.segs[i].st.After(t) {
+		return 0
+	}
+	return f.segs[i].Value
+}
+
+// Integral returns the integral of the function over the range, with time
+// measured in seconds, e.g. for the price per second it is the total cost
+// of the range. Divide it by 3600 for the price per hour.
+func (f StepFunction) Integral(r Range) float64 {
+	var res float64
+
+	i := sort.Search(len(f.segs), func(i int) bool { return f.segs[i].End().After(r.st) })
+	for ; i < len(f.segs) && f.segs[i].st.Before(r.End()); i++ {
+		if part := f.segs[i].Truncate(r); part.dur > 0 {
+			res += f.segs[i].Value * part.dur.Seconds()
+		}
+	}
+
+	return res
+}
+
+// Resample returns the average values of the function on the grid of the
+// given step within the period, the last cell might be shorter. Returns
+// nil if the step is not positive.
+func (f StepFunction) Resample(period Range, step time.Duration) []Labeled[float64] {
+	if step <= 0 {
+		return nil
+	}
+
+	var res []Labeled[float64]
+	for st := period.st; st.Before(period.End()); st = st.Add(step) {
+		cell := Range{st: st, dur: step}
+		if end := period.End(); cell.End().After(end) {
+			cell.dur = end.Sub(st)
+		}
+		res = append(res, Labeled[float64]{Range: cell, Value: f.Integral(cell) / cell.dur.Seconds()})
+	}
+	return res
+}
@@ -0,0 +1,69 @@
+package trn
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testStepFunction() StepFunction {
+	// price per second
+	return NewStepFunction([]Labeled[float64]{
+		Label(MustRange(Between(tm(9, 0), tm(12, 0))), 1.0),
+		Label(MustRange(Between(tm(12, 0), tm(14, 0))), 2.0),
+		Label(MustRange(Between(tm(13, 0), tm(14, 0))), 0.5), // surcharge
+		Label(MustRange(Between(tm(16, 0), tm(17, 0))), 3.0),
+	})
+}
+
+func TestStepFunction_ValueAt(t *testing.T) {
+	f := testStepFunction()
+	assert.Len(t, f.Segments(), 4)
+
+	assert.Zero(t, f.ValueAt(tm(8, 0)))
+	assert.Equal(t, 1.0, f.ValueAt(tm(9, 0)))
+	assert.Equal(t, 2.0, f.ValueAt(tm(12, 0)))
+	assert.Equal(t, 2.5, f.ValueAt(tm(13, 30)))
+	assert.Zero(t, f.ValueAt(tm(14, 0)))
+	assert.Equal(t, 3.0, f.ValueAt(tm(16, 59)))
+	assert.Zero(t, f.ValueAt(tm(17, 0)))
+	assert.Zero(t, StepFunction{}.ValueAt(tm(9, 0)))
+}
+
+func TestStepFunction_Integral(t *testing.T) {
+	f := testStepFunction()
+
+	assert.InDelta(t, 3600.0, f.Integral(MustRange(Between(tm(8, 0), tm(10, 0)))), 1e-9)
+	assert.InDelta(t, 1800.0+2*3600+2.5*1800, f.Integral(MustRange(Between(tm(11, 30), tm(13, 30)))), 1e-9)
+	assert.InDelta(t, 3*3600+2*3600+2.5*3600+3*3600, f.Integral(MustRange(Between(tm(0, 0), tm(23, 0)))), 1e-9)
+	assert.Zero(t, f.Integral(MustRange(Between(tm(14, 0), tm(16, 0)))))
+}
+
+func TestStepFunction_Resample(t *testing.T) {
+	f := testStepFunction()
+
+	got := f.Resample(MustRange(Between(tm(11, 0), tm(14, 30))), time.Hour)
+	assert.Equal(t, []string{"[11:00, 12:00]", "[12:00, 13:00]", "[13:00, 14:00]", "[14:00, 14:30]"},
+		formatLabeledRanges(got))
+
+	var values []float64
+	for _, item := range got {
+		values = append(values, item.Value)
+	}
+	assert.Equal(t, []float64{1, 2, 2.5, 0}, values)
+
+	got = f.Resample(MustRange(Between(tm(11, 30), tm(12, 30))), time.Hour)
+	assert.Len(t, got, 1)
+	assert.InDelta(t, 1.5, got[0].Value, 1e-9)
+
+	assert.Nil(t, f.Resample(MustRange(Between(tm(11, 0), tm(12, 0))), 0))
+}
+
+func formatLabeledRanges(items []Labeled[float64]) []string {
+	var res []string
+	for _, item := range items {
+		res = append(res, item.Format("15:04"))
+	}
+	return res
+}
@@ -0,0 +1,137 @@
+package store
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/cappuccinotm/trn"
+)
+
+// BusinessCalendar describes the business hours and holidays in the
+// location.
+type BusinessCalendar struct {
+	Hours    WeeklySchedule
+	Holidays []Date
+	Location *time.Location
+}
+
+// IsBusinessDay returns true if the date is not a holiday and there are
+// business hours on its weekday.
+func (c BusinessCalendar) IsBusinessDay(d Date) bool {
+	return len(c.Hours[d.Weekday()]) > 0 && !c.isHoliday(d)
+}
+
+// Ranges returns the business hours within the period, merged and sorted.
+// Time ranges, which start on holidays, are skipped.
+func (c BusinessCalendar) Ranges(period trn.Range) []trn.Range {
+	res, _ := c.RangesContext(context.Background(), period)
+	return res
+}
+
+// RangesContext is like Ranges, but stops once the context is done,
+// returning the ranges expanded so far along with the context's error.
+func (c BusinessCalendar) RangesContext(ctx context.Context, period trn.Range) ([]trn.Range, error) {
+	return c.Hours.expand(ctx, period, c.location(), c.isHoliday)
+}
+
+// WithinBusinessHours reports for each calendar whether the range falls
+// entirely within its business hours, e.g. to flag the participants, for
+// whom the meeting is out of office hours.
+func WithinBusinessHours(r trn.Range, cals []BusinessCalendar) []bool {
+	res := make([]bool, len(cals))
+	for i, cal := range cals {
+		res[i] = trn.Covers(r, cal.Ranges(r))
+	}
+	return res
+}
+
+// BusinessDuration returns the business time between from and to, e.g. to
+// measure turnaround time of a ticket. Returns negative duration if to is
+// before from, saturates if the business time doesn't fit into
+// time.Duration.
+func BusinessDuration(from, to time.Time, cal BusinessCalendar) time.Duration {
+	if to.Before(from) {
+		return -BusinessDuration(to, from, cal)
+	}
+
+	// the period might be too long to be represented by a single range,
+	// e.g. since the zero time, so walk it in chunks
+	var res time.Duration
+	for st := from; st.Before(to) && res < math.MaxInt64; {
+		chunk := trn.NewBetween(st, to)
+		for _, rng := range cal.Ranges(chunk) {
+			if res += rng.Duration(); res < 0 {
+				return math.MaxInt64
+			}
+		}
+		if !chunk.End().After(st) {
+			break
+		}
+		st = chunk.End()
+	}
+	return res
+}
+
+// NextDeadline returns the time, when the sla of business time elapses
+// since start, i.e. the timer is paused outside of business hours and on
+// holidays. Returns start if sla is not positive and zero time if there are
+// no business hours in the calendar.
+func NextDeadline(start time.Time, sla time.Duration, cal BusinessCalendar) time.Time {
+	if sla <= 0 {
+		return start
+	}
+
+	if cal.Hours.Empty() {
+		return time.Time{}
+	}
+
+	const week = 7 * 24 * time.Hour
+	for from := start; ; from = from.Add(week) {
+		for _, rng := range cal.Ranges(trn.NewBetween(from, from.Add(week))) {
+			if rng.Duration() >= sla {
+				return rng.Start().Add(sla)
+			}
+			sla -= rng.Duration()
+		}
+	}
+}
+
+// AddBusinessDays returns the date, which is n business days after d, or
+// before it, if n is negative. The date d itself is not counted.
+// Returns d if there are no business days in the calendar.
+func AddBusinessDays(d Date, n int, cal BusinessCalendar) Date {
+	if cal.Hours.Empty() {
+		return d
+	}
+
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+
+	for n > 0 {
+		d = d.AddDays(step)
+		if cal.IsBusinessDay(d) {
+			n--
+		}
+	}
+
+	return d
+}
+
+func (c BusinessCalendar) isHoliday(d Date) bool {
+	for _, h := range c.Holidays {
+		if h == d {
+			return true
+		}
+	}
+	return false
+}
+
+func (c BusinessCalendar) location() *time.Location {
+	if c.Location == nil {
+		return time.UTC
+	}
+	return c.Location
+}
@@ -0,0 +1,143 @@
+package store
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/cappuccinotm/trn"
+	"github.com/stretchr/testify/assert"
+)
+
+func testCalendar(t *testing.T) BusinessCalendar {
+	w, err := ParseOpeningHours("Mo-Fr 09:00-17:00")
+	assert.NoError(t, err)
+	return BusinessCalendar{
+		Hours:    w,
+		Holidays: []Date{NewDate(2021, time.June, 15)}, // tuesday
+		Location: time.UTC,
+	}
+}
+
+func TestBusinessCalendar_IsBusinessDay(t *testing.T) {
+	cal := testCalendar(t)
+	assert.False(t, cal.IsBusinessDay(NewDate(2021, time.June, 12)), "saturday")
+	assert.True(t, cal.IsBusinessDay(NewDate(2021, time.June, 14)), "monday")
+	assert.False(t, cal.IsBusinessDay(NewDate(2021, time.June, 15)), "holiday")
+}
+
+func TestBusinessCalendar_RangesContext(t *testing.T) {
+	cal := testCalendar(t)
+	period := trn.MustRange(trn.Between(dhm(14, 0, 0), dhm(19, 0, 0)))
+
+	got, err := cal.RangesContext(context.Background(), period)
+	assert.NoError(t, err)
+	assert.Equal(t, cal.Ranges(period), got)
+	assert.Len(t, got, 4)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	got, err = cal.RangesContext(ctx, period)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, got)
+}
+
+func TestWithinBusinessHours(t *testing.T) {
+	utc := testCalendar(t)
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("tzdata is not available")
+	}
+	nyc := BusinessCalendar{Hours: utc.Hours, Location: ny}
+
+	cals := []BusinessCalendar{utc, nyc}
+	assert.Equal(t, []bool{true, true}, WithinBusinessHours(trn.New(dhm(14, 14, 0), time.Hour), cals))
+	assert.Equal(t, []bool{true, false}, WithinBusinessHours(trn.New(dhm(14, 12, 30), time.Hour), cals))
+	assert.Equal(t, []bool{false, true}, WithinBusinessHours(trn.New(dhm(15, 14, 0), time.Hour), cals), "holiday")
+	assert.Equal(t, []bool{false, false}, WithinBusinessHours(trn.New(dhm(12, 14, 0), time.Hour), cals), "weekend")
+}
+
+func TestBusinessDuration(t *testing.T) {
+	cal := testCalendar(t)
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     time.Duration
+	}{
+		{name: "within a day", from: dhm(14, 10, 0), to: dhm(14, 12, 30), want: 150 * time.Minute},
+		{name: "over the weekend", from: dhm(11, 16, 0), to: dhm(14, 10, 0), want: 2 * time.Hour},
+		{name: "over the holiday", from: dhm(14, 16, 0), to: dhm(16, 10, 0), want: 2 * time.Hour},
+		{name: "reversed", from: dhm(14, 12, 0), to: dhm(14, 10, 0), want: -2 * time.Hour},
+		{name: "outside business hours", from: dhm(14, 18, 0), to: dhm(14, 20, 0), want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, BusinessDuration(tt.from, tt.to, cal))
+		})
+	}
+
+	t.Run("since zero time", func(t *testing.T) {
+		cal := BusinessCalendar{Hours: cal.Hours, Location: time.UTC}
+		// two millennia of business hours don't fit into time.Duration
+		assert.Equal(t, time.Duration(math.MaxInt64), BusinessDuration(time.Time{}, dhm(14, 10, 0), cal))
+		assert.Equal(t, time.Duration(0), BusinessDuration(time.Time{}, time.Time{}.Add(time.Hour), cal))
+	})
+}
+
+func TestNextDeadline(t *testing.T) {
+	cal := testCalendar(t)
+
+	tests := []struct {
+		name  string
+		start time.Time
+		sla   time.Duration
+		want  time.Time
+	}{
+		{name: "within a day", start: dhm(14, 10, 0), sla: 4 * time.Hour, want: dhm(14, 14, 0)},
+		{name: "before business hours", start: dhm(14, 6, 0), sla: time.Hour, want: dhm(14, 10, 0)},
+		{name: "paused at night and on the holiday", start: dhm(14, 16, 0), sla: 2 * time.Hour, want: dhm(16, 10, 0)},
+		{name: "over the weekend", start: dhm(11, 16, 0), sla: 8 * time.Hour, want: dhm(14, 16, 0)},
+		{name: "ends at the end of the day", start: dhm(14, 9, 0), sla: 8 * time.Hour, want: dhm(14, 17, 0)},
+		{name: "longer than a week", start: dhm(12, 0, 0), sla: 40 * time.Hour, want: dhm(21, 17, 0)},
+		{name: "zero sla", start: dhm(12, 3, 0), want: dhm(12, 3, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NextDeadline(tt.start, tt.sla, cal))
+		})
+	}
+
+	assert.True(t, NextDeadline(dhm(14, 10, 0), time.Hour, BusinessCalendar{}).IsZero())
+
+	t.Run("beyond the horizon", func(t *testing.T) {
+		trn.SetHorizon(30 * 24 * time.Hour)
+		defer trn.SetHorizon(0)
+
+		// the same weekdays a decade later
+		future := func(t time.Time) time.Time { return t.AddDate(0, 0, 52*7*10) }
+		assert.Equal(t, future(dhm(14, 11, 0)), NextDeadline(future(dhm(14, 10, 0)), time.Hour, cal))
+	})
+}
+
+func TestBusinessCalendar_Precision(t *testing.T) {
+	trn.SetPrecision(time.Hour)
+	defer trn.SetPrecision(0)
+
+	cal := testCalendar(t)
+	assert.Equal(t, 30*time.Minute, BusinessDuration(dhm(14, 9, 20), dhm(14, 9, 50), cal))
+	assert.Equal(t, dhm(14, 9, 50), NextDeadline(dhm(14, 9, 20), 30*time.Minute, cal))
+}
+
+func TestAddBusinessDays(t *testing.T) {
+	cal := testCalendar(t)
+	fri := NewDate(2021, time.June, 11)
+
+	assert.Equal(t, NewDate(2021, time.June, 14), AddBusinessDays(fri, 1, cal))
+	assert.Equal(t, NewDate(2021, time.June, 16), AddBusinessDays(fri, 2, cal), "skips the holiday")
+	assert.Equal(t, NewDate(2021, time.June, 10), AddBusinessDays(fri, -1, cal))
+	assert.Equal(t, NewDate(2021, time.June, 14), AddBusinessDays(NewDate(2021, time.June, 16), -1, cal))
+	assert.Equal(t, fri, AddBusinessDays(fri, 0, cal))
+	assert.Equal(t, fri, AddBusinessDays(fri, 5, BusinessCalendar{}))
+}
@@ -0,0 +1,154 @@
+// Package store provides calendar building blocks, such as time of day,
+// daily time ranges and weekly schedules, which are convenient to keep in
+// configuration and storage and could be expanded into trn ranges.
+package store
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Clock represents the time of day as a duration since midnight.
+type Clock time.Duration
+
+// NewClock makes a new Clock with the given hours, minutes and seconds.
+func NewClock(hour, min, sec int) Clock {
+	return Clock(time.Duration(hour)*time.Hour +
+		time.Duration(min)*time.Minute +
+		time.Duration(sec)*time.Second)
+}
+
+// ParseClock parses the time of day in format "15:04" or "15:04:05".
+// "24:00" is allowed to represent the end of the day.
+// Returns ErrInvalidClock if the string is malformed.
+func ParseClock(s string) (Clock, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidClock, s)
+	}
+
+	var vals [3]int
+	for i, p := range parts {
+		if len(p) != 2 {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidClock, s)
+		}
+		v, err := strconv.Atoi(p)
+		if err != nil || v < 0 {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidClock, s)
+		}
+		vals[i] = v
+	}
+
+	h, m, sec := vals[0], vals[1], vals[2]
+	if h > 24 || m > 59 || sec > 59 || (h == 24 && (m != 0 || sec != 0)) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidClock, s)
+	}
+
+	return NewClock(h, m, sec), nil
+}
+
+// Hour returns the hour of the day, within [0, 24].
+func (c Clock) Hour() int { return int(time.Duration(c) / time.Hour) }
+
+// Minute returns the minute offset within the hour, within [0, 59].
+func (c Clock) Minute() int { return int(time.Duration(c) % time.Hour / time.Minute) }
+
+// Second returns the second offset within the minute, within [0, 59].
+func (c Clock) Second() int { return int(time.Duration(c) % time.Minute / time.Second) }
+
+// Nanosecond returns the nanosecond offset within the second.
+func (c Clock) Nanosecond() int { return int(time.Duration(c) % time.Second) }
+
+// OnDate returns the instant of the clock on the date in the given
+// location. The clock, which falls into the DST gap, is normalized the same
+// way as time.Date does.
+func (c Clock) OnDate(d Date, loc *time.Location) time.Time { return d.At(c, loc) }
+
+// In converts the clock in the location from to the clock in the location
+// to. As the offset between locations changes over the year, the conversion
+// is made on the reference date ref in the location from. The date, on
+// which the resulting clock falls in the location to, is returned as well,
+// e.g. 01:00 in Berlin is 23:00 in UTC on the previous day.
+func (c Clock) In(ref Date, from, to *time.Location) (Clock, Date) {
+	t := c.OnDate(ref, from).In(to)
+	return NewClock(t.Hour(), t.Minute(), t.Second()) + Clock(t.Nanosecond()), DateOf(t)
+}
+
+// String returns the time of day in format "15:04", or "15:04:05" if the
+// clock has non-zero seconds.
+func (c Clock) String() string {
+	if c.Second() != 0 {
+		return fmt.Sprintf("%02d:%02d:%02d", c.Hour(), c.Minute(), c.Second())
+	}
+	return fmt.Sprintf("%02d:%02d", c.Hour(), c.Minute())
+}
+
+// MarshalText implements encoding.TextMarshaler, the clock is formatted
+// the same way as by String.
+func (c Clock) MarshalText() ([]byte, error) { return []byte(c.String()), nil }
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (c *Clock) UnmarshalText(b []byte) error {
+	res, err := ParseClock(string(b))
+	if err != nil {
+		return err
+	}
+	*c = res
+	return nil
+}
+
+// GoString implements fmt.GoStringer and formats c to be printed in Go
+// source code.
+func (c Clock) GoString() string {
+	if c.Nanosecond() != 0 || c < 0 {
+		return "store.Clock(" + strconv.FormatInt(int64(c), 10) + ")"
+	}
+	return fmt.Sprintf("store.NewClock(%d, %d, %d)", c.Hour(), c.Minute(), c.Second())
+}
+
+// TimeRange is a range within a day. If the End is not after the Start,
+// the range is considered to cross midnight and end on the next day.
+type TimeRange struct {
+	Start Clock
+	End   Clock
+}
+
+// ParseTimeRange parses the time range in format "09:00-17:00".
+func ParseTimeRange(s string) (TimeRange, error) {
+	parts := strings.Split(s, "-")
+	if len(parts) != 2 {
+		return TimeRange{}, fmt.Errorf("%w: %q", ErrInvalidTimeRange, s)
+	}
+
+	st, err := ParseClock(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return TimeRange{}, err
+	}
+
+	end, err := ParseClock(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return TimeRange{}, err
+	}
+
+	return TimeRange{Start: st, End: end}, nil
+}
+
+// Duration returns the duration of the time range, ignoring DST
+// transitions.
+func (r TimeRange) Duration() time.Duration {
+	if r.End <= r.Start {
+		return time.Duration(r.End-r.Start) + 24*time.Hour
+	}
+	return time.Duration(r.End - r.Start)
+}
+
+// String returns the time range in format "09:00-17:00".
+func (r TimeRange) String() string { return r.Start.String() + "-" + r.End.String() }
+
+// GoString implements fmt.GoStringer and formats r to be printed in Go
+// source code.
+func (r TimeRange) GoString() string {
+	return "store.TimeRange{Start: " + r.Start.GoString() + ", End: " + r.End.GoString() + "}"
+}
@@ -0,0 +1,91 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseClock(t *testing.T) {
+	tests := []struct {
+		arg     string
+		want    Clock
+		wantErr error
+	}{
+		{arg: "09:30", want: NewClock(9, 30, 0)},
+		{arg: "23:59:59", want: NewClock(23, 59, 59)},
+		{arg: "24:00", want: NewClock(24, 0, 0)},
+		{arg: "24:01", wantErr: ErrInvalidClock},
+		{arg: "9:30", wantErr: ErrInvalidClock},
+		{arg: "09:60", wantErr: ErrInvalidClock},
+		{arg: "09", wantErr: ErrInvalidClock},
+		{arg: "ab:cd", wantErr: ErrInvalidClock},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			got, err := ParseClock(tt.arg)
+			assert.Equal(t, tt.want, got)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestClock_String(t *testing.T) {
+	assert.Equal(t, "09:05", NewClock(9, 5, 0).String())
+	assert.Equal(t, "09:05:07", NewClock(9, 5, 7).String())
+	assert.Equal(t, "24:00", NewClock(24, 0, 0).String())
+}
+
+func TestClock_GoString(t *testing.T) {
+	assert.Equal(t, "store.NewClock(9, 5, 7)", NewClock(9, 5, 7).GoString())
+	assert.Equal(t, "store.Clock(32700000000001)", (NewClock(9, 5, 0) + 1).GoString())
+	assert.Equal(t,
+		"store.TimeRange{Start: store.NewClock(22, 0, 0), End: store.NewClock(2, 0, 0)}",
+		TimeRange{Start: NewClock(22, 0, 0), End: NewClock(2, 0, 0)}.GoString(),
+	)
+}
+
+func TestTimeRange(t *testing.T) {
+	tr, err := ParseTimeRange("09:00-17:30")
+	assert.NoError(t, err)
+	assert.Equal(t, TimeRange{Start: NewClock(9, 0, 0), End: NewClock(17, 30, 0)}, tr)
+	assert.Equal(t, 8*time.Hour+30*time.Minute, tr.Duration())
+	assert.Equal(t, "09:00-17:30", tr.String())
+
+	overnight := TimeRange{Start: NewClock(22, 0, 0), End: NewClock(2, 0, 0)}
+	assert.Equal(t, 4*time.Hour, overnight.Duration())
+
+	_, err = ParseTimeRange("09:00")
+	assert.ErrorIs(t, err, ErrInvalidTimeRange)
+	_, err = ParseTimeRange("09:00-25:00")
+	assert.ErrorIs(t, err, ErrInvalidClock)
+}
+
+func TestClock_In(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	summer, winter := NewDate(2021, time.June, 15), NewDate(2021, time.January, 15)
+	nine := NewClock(9, 0, 0)
+
+	assert.Equal(t, time.Date(2021, time.June, 15, 7, 0, 0, 0, time.UTC), nine.OnDate(summer, berlin).UTC())
+	assert.Equal(t, time.Date(2021, time.January, 15, 8, 0, 0, 0, time.UTC), nine.OnDate(winter, berlin).UTC())
+
+	c, d := nine.In(summer, berlin, time.UTC)
+	assert.Equal(t, NewClock(7, 0, 0), c)
+	assert.Equal(t, summer, d)
+
+	c, d = nine.In(winter, berlin, time.UTC)
+	assert.Equal(t, NewClock(8, 0, 0), c)
+	assert.Equal(t, winter, d)
+
+	c, d = NewClock(1, 0, 0).In(summer, berlin, time.UTC)
+	assert.Equal(t, NewClock(23, 0, 0), c)
+	assert.Equal(t, summer.AddDays(-1), d)
+
+	c, d = NewClock(23, 30, 0).In(summer, time.UTC, berlin)
+	assert.Equal(t, NewClock(1, 30, 0), c)
+	assert.Equal(t, summer.AddDays(1), d)
+}
@@ -0,0 +1,105 @@
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cappuccinotm/trn"
+)
+
+const dateFmt = "2006-01-02"
+
+// Date represents the calendar date without time and location.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// NewDate makes a new Date, normalizing the values the same way as
+// time.Date does, e.g. October 32 converts to November 1.
+func NewDate(year int, month time.Month, day int) Date {
+	return DateOf(time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+}
+
+// DateOf returns the date of the given time in its location.
+func DateOf(t time.Time) Date {
+	y, m, d := t.Date()
+	return Date{Year: y, Month: m, Day: d}
+}
+
+// ParseDate parses the date in format "2006-01-02".
+func ParseDate(s string) (Date, error) {
+	t, err := time.Parse(dateFmt, s)
+	if err != nil {
+		return Date{}, fmt.Errorf("%w: %q", ErrInvalidDate, s)
+	}
+	return DateOf(t), nil
+}
+
+// String returns the date in format "2006-01-02".
+func (d Date) String() string { return d.midnight(time.UTC).Format(dateFmt) }
+
+// MarshalText implements encoding.TextMarshaler, the date is formatted as
+// "2006-01-02".
+func (d Date) MarshalText() ([]byte, error) { return []byte(d.String()), nil }
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Date) UnmarshalText(b []byte) error {
+	res, err := ParseDate(string(b))
+	if err != nil {
+		return err
+	}
+	*d = res
+	return nil
+}
+
+// GoString implements fmt.GoStringer and formats d to be printed in Go
+// source code.
+func (d Date) GoString() string {
+	return fmt.Sprintf("store.Date{Year: %d, Month: time.%s, Day: %d}", d.Year, d.Month, d.Day)
+}
+
+// Weekday returns the day of the week of the date.
+func (d Date) Weekday() time.Weekday { return d.midnight(time.UTC).Weekday() }
+
+// AddDays returns the date n days after d, n might be negative.
+func (d Date) AddDays(n int) Date { return NewDate(d.Year, d.Month, d.Day+n) }
+
+// Before returns true if d is before the other date.
+func (d Date) Before(other Date) bool { return d.midnight(time.UTC).Before(other.midnight(time.UTC)) }
+
+// After returns true if d is after the other date.
+func (d Date) After(other Date) bool { return other.Before(d) }
+
+// At returns the time at the clock c on the date in the given location.
+func (d Date) At(c Clock, loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, c.Hour(), c.Minute(), c.Second(), c.Nanosecond(), loc)
+}
+
+func (d Date) midnight(loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
+// Day returns the range of the whole day d in the given location. The
+// duration of the range might differ from 24 hours on DST transitions.
+func Day(d Date, loc *time.Location) trn.Range {
+	return trn.NewBetween(d.midnight(loc), d.AddDays(1).midnight(loc))
+}
+
+// Between returns the range of the time range tr on the date d in the given
+// location. If tr crosses midnight, the range ends on the next day.
+func Between(d Date, tr TimeRange, loc *time.Location) trn.Range {
+	endDate := d
+	if tr.End <= tr.Start {
+		endDate = d.AddDays(1)
+	}
+
+	st, end := d.At(tr.Start, loc), endDate.At(tr.End, loc)
+	if end.Before(st) {
+		// the clocks fall into the DST gap, both are shifted differently
+		end = st
+	}
+
+	return trn.NewBetween(st, end)
+}
@@ -0,0 +1,51 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDate(t *testing.T) {
+	d, err := ParseDate("2021-06-12")
+	assert.NoError(t, err)
+	assert.Equal(t, Date{Year: 2021, Month: time.June, Day: 12}, d)
+	assert.Equal(t, "2021-06-12", d.String())
+	assert.Equal(t, "store.Date{Year: 2021, Month: time.June, Day: 12}", d.GoString())
+	assert.Equal(t, time.Saturday, d.Weekday())
+	assert.Equal(t, NewDate(2021, time.July, 2), d.AddDays(20))
+	assert.Equal(t, NewDate(2021, time.May, 31), d.AddDays(-12))
+	assert.True(t, d.Before(d.AddDays(1)))
+	assert.True(t, d.After(d.AddDays(-1)))
+	assert.False(t, d.After(d))
+	assert.Equal(t, d, DateOf(dhm(12, 23, 59)))
+	assert.Equal(t, dhm(12, 13, 30), d.At(NewClock(13, 30, 0), time.UTC))
+
+	_, err = ParseDate("2021-02-30")
+	assert.ErrorIs(t, err, ErrInvalidDate)
+}
+
+func TestBetween(t *testing.T) {
+	d := NewDate(2021, time.June, 12)
+
+	rng := Between(d, TimeRange{Start: NewClock(9, 0, 0), End: NewClock(17, 0, 0)}, time.UTC)
+	assert.Equal(t, "[Sat 12 09:00, Sat 12 17:00]", rng.Format("Mon 02 15:04"))
+
+	rng = Between(d, TimeRange{Start: NewClock(22, 0, 0), End: NewClock(2, 0, 0)}, time.UTC)
+	assert.Equal(t, "[Sat 12 22:00, Sun 13 02:00]", rng.Format("Mon 02 15:04"))
+
+	rng = Between(d, TimeRange{Start: 0, End: NewClock(24, 0, 0)}, time.UTC)
+	assert.Equal(t, 24*time.Hour, rng.Duration())
+}
+
+func TestDay(t *testing.T) {
+	assert.Equal(t, "[Sat 12 00:00, Sun 13 00:00]",
+		Day(NewDate(2021, time.June, 12), time.UTC).Format("Mon 02 15:04"))
+
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("tzdata is not available")
+	}
+	assert.Equal(t, 23*time.Hour, Day(NewDate(2021, time.March, 28), loc).Duration())
+}
@@ -0,0 +1,131 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cappuccinotm/trn"
+)
+
+// DateRange is a range between two timestamps, which is convenient to keep
+// in storage as a pair of columns. Unlike trn.Range it keeps the end
+// timestamp as is, with its own location.
+type DateRange struct {
+	st  time.Time
+	end time.Time
+}
+
+// NewDateRange makes a new DateRange with the given boundaries. The
+// boundaries are not validated, use Range to get the valid trn.Range.
+func NewDateRange(start, end time.Time) DateRange { return DateRange{st: start, end: end} }
+
+// DateRangeOf makes a new DateRange of the given trn.Range.
+func DateRangeOf(r trn.Range) DateRange { return DateRange{st: r.Start(), end: r.End()} }
+
+// Range returns the trn.Range between the boundaries of the date range.
+// Returns trn.ErrStartAfterEnd if the start is later than the end.
+func (r DateRange) Range() (trn.Range, error) { return trn.Between(r.st, r.end) }
+
+// Start returns the start of the date range.
+func (r DateRange) Start() time.Time { return r.st }
+
+// End returns the end of the date range.
+func (r DateRange) End() time.Time { return r.end }
+
+// Duration returns the duration between the boundaries, which is negative
+// if the start is later than the end.
+func (r DateRange) Duration() time.Duration { return r.end.Sub(r.st) }
+
+// Empty returns true if both boundaries are zero.
+func (r DateRange) Empty() bool { return r.st.IsZero() && r.end.IsZero() }
+
+// Shift returns the date range with both boundaries moved by d.
+func (r DateRange) Shift(d time.Duration) DateRange {
+	return DateRange{st: r.st.Add(d), end: r.end.Add(d)}
+}
+
+// Equal returns true if both boundaries represent the same instants as the
+// other's ones, locations are not compared.
+func (r DateRange) Equal(other DateRange) bool {
+	return r.st.Equal(other.st) && r.end.Equal(other.end)
+}
+
+// String returns the date range in format "start/end", where the
+// boundaries are formatted with trn.FormatRFC9557.
+func (r DateRange) String() string {
+	return trn.FormatRFC9557(r.st) + "/" + trn.FormatRFC9557(r.end)
+}
+
+// MarshalText implements encoding.TextMarshaler, the date range is
+// formatted as by String.
+func (r DateRange) MarshalText() ([]byte, error) { return []byte(r.String()), nil }
+
+// UnmarshalText implements encoding.TextUnmarshaler and parses the date
+// range formatted by MarshalText, the boundaries keep their own locations.
+// Returns trn.ErrMalformedRange if there is no separator.
+func (r *DateRange) UnmarshalText(b []byte) error {
+	// zone names contain slashes too, so look for the one outside brackets
+	depth := 0
+	for i, c := range b {
+		switch c {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case '/':
+			if depth == 0 {
+				return r.parse(string(b[:i]), string(b[i+1:]))
+			}
+		}
+	}
+	return fmt.Errorf("%w: %q", trn.ErrMalformedRange, string(b))
+}
+
+type jsonDateRange struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// MarshalJSON implements json.Marshaler. The date range is represented as
+// an object with "start" and "end" RFC 9557 timestamps, like trn.Range.
+func (r DateRange) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonDateRange{Start: trn.FormatRFC9557(r.st), End: trn.FormatRFC9557(r.end)})
+}
+
+// UnmarshalJSON implements json.Unmarshaler and parses the date range
+// formatted by MarshalJSON.
+func (r *DateRange) UnmarshalJSON(b []byte) error {
+	var jr jsonDateRange
+	if err := json.Unmarshal(b, &jr); err != nil {
+		return fmt.Errorf("%w: %v", trn.ErrMalformedRange, err)
+	}
+	return r.parse(jr.Start, jr.End)
+}
+
+// parse sets the boundaries parsed with trn.ParseRFC9557.
+func (r *DateRange) parse(start, end string) error {
+	st, err := trn.ParseRFC9557(start)
+	if err != nil {
+		return err
+	}
+	e, err := trn.ParseRFC9557(end)
+	if err != nil {
+		return err
+	}
+	*r = DateRange{st: st, end: e}
+	return nil
+}
+
+// GoString implements fmt.GoStringer and formats r to be printed in Go
+// source code.
+func (r DateRange) GoString() string {
+	sb := &strings.Builder{}
+	sb.WriteString("store.NewDateRange(")
+	sb.WriteString(r.st.GoString())
+	sb.WriteString(", ")
+	sb.WriteString(r.end.GoString())
+	sb.WriteRune(')')
+	return sb.String()
+}
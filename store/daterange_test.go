@@ -0,0 +1,88 @@
+package store
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/cappuccinotm/trn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDateRange(t *testing.T) {
+	r := NewDateRange(dhm(12, 9, 0), dhm(12, 17, 30))
+	assert.Equal(t, "2021-06-12T09:00:00Z/2021-06-12T17:30:00Z", r.String())
+	assert.Equal(t,
+		"store.NewDateRange(time.Date(2021, time.June, 12, 9, 0, 0, 0, time.UTC), "+
+			"time.Date(2021, time.June, 12, 17, 30, 0, 0, time.UTC))",
+		r.GoString(),
+	)
+
+	rng, err := r.Range()
+	require.NoError(t, err)
+	assert.Equal(t, trn.New(dhm(12, 9, 0), 8*time.Hour+30*time.Minute), rng)
+	assert.Equal(t, r, DateRangeOf(rng))
+
+	_, err = NewDateRange(dhm(12, 17, 30), dhm(12, 9, 0)).Range()
+	assert.ErrorIs(t, err, trn.ErrStartAfterEnd)
+}
+
+func TestDateRange_Accessors(t *testing.T) {
+	// both types might be used via the same interface
+	type rangeLike interface {
+		Start() time.Time
+		End() time.Time
+		Duration() time.Duration
+		Empty() bool
+	}
+
+	r := NewDateRange(dhm(12, 9, 0), dhm(12, 17, 30))
+	for _, rl := range []rangeLike{r, trn.MustRange(r.Range())} {
+		assert.Equal(t, dhm(12, 9, 0), rl.Start())
+		assert.Equal(t, dhm(12, 17, 30), rl.End())
+		assert.Equal(t, 8*time.Hour+30*time.Minute, rl.Duration())
+		assert.False(t, rl.Empty())
+	}
+
+	assert.Equal(t, []trn.Range{trn.MustRange(r.Range())}, trn.MergeIntervals([]DateRange{r}))
+
+	assert.True(t, DateRange{}.Empty())
+	assert.Equal(t, -time.Hour, NewDateRange(dhm(12, 10, 0), dhm(12, 9, 0)).Duration())
+	assert.Equal(t, NewDateRange(dhm(12, 10, 0), dhm(12, 18, 30)), r.Shift(time.Hour))
+	assert.True(t, r.Equal(NewDateRange(dhm(12, 9, 0).In(time.FixedZone("UTC+2", 2*60*60)), dhm(12, 17, 30))))
+	assert.False(t, r.Equal(NewDateRange(dhm(12, 9, 0), dhm(12, 17, 0))))
+}
+
+func TestDateRange_Codecs(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("tzdata is not available")
+	}
+
+	// the boundaries are in different locations
+	r := NewDateRange(dhm(12, 9, 0).In(berlin), dhm(12, 17, 30))
+
+	b, err := r.MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "2021-06-12T11:00:00+02:00[Europe/Berlin]/2021-06-12T17:30:00Z", string(b))
+
+	var got DateRange
+	require.NoError(t, got.UnmarshalText(b))
+	assert.Equal(t, "Europe/Berlin", got.Start().Location().String())
+	assert.Equal(t, "UTC", got.End().Location().String())
+	assert.True(t, r.Equal(got))
+
+	b, err = json.Marshal(r)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"start":"2021-06-12T11:00:00+02:00[Europe/Berlin]","end":"2021-06-12T17:30:00Z"}`, string(b))
+
+	got = DateRange{}
+	require.NoError(t, json.Unmarshal(b, &got))
+	assert.Equal(t, "Europe/Berlin", got.Start().Location().String())
+	assert.True(t, r.Equal(got))
+
+	assert.ErrorIs(t, got.UnmarshalText([]byte("2021-06-12T11:00:00Z")), trn.ErrMalformedRange)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"start":1}`), &got), trn.ErrMalformedRange)
+	assert.ErrorIs(t, got.UnmarshalText([]byte("blah/2021-06-12T11:00:00Z")), trn.ErrMalformedTimestamp)
+}
@@ -0,0 +1,11 @@
+package store
+
+import "github.com/cappuccinotm/trn"
+
+// package errors
+const (
+	ErrInvalidDate         = trn.Error("store: invalid date")
+	ErrInvalidClock        = trn.Error("store: invalid clock")
+	ErrInvalidTimeRange    = trn.Error("store: invalid time range")
+	ErrInvalidOpeningHours = trn.Error("store: invalid opening hours")
+)
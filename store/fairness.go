@@ -0,0 +1,81 @@
+package store
+
+import (
+	"sort"
+	"time"
+
+	"github.com/cappuccinotm/trn"
+)
+
+// ParticipantTZ describes the time zone of the meeting participant and
+// the hours, which are comfortable for them in their local time.
+type ParticipantTZ struct {
+	Location    *time.Location
+	Comfortable TimeRange
+}
+
+// Score rates the slot by how sociable it is for the participants, as the
+// average fraction of the slot, which falls into the comfortable hours of
+// each participant. The score is within [0, 1], where 1 means that the
+// slot is comfortable for everyone. Returns 0 if there are no participants.
+func Score(r trn.Range, participants []ParticipantTZ) float64 {
+	if len(participants) == 0 {
+		return 0
+	}
+
+	var sum float64
+	for _, p := range participants {
+		sum += p.comfort(r)
+	}
+	return sum / float64(len(participants))
+}
+
+// SortByScore sorts the candidate slots by their score in the descending
+// order, keeping the order of the equally rated ones.
+func SortByScore(candidates []trn.Range, participants []ParticipantTZ) {
+	type scored struct {
+		rng   trn.Range
+		score float64
+	}
+
+	res := make([]scored, len(candidates))
+	for i, c := range candidates {
+		res[i] = scored{rng: c, score: Score(c, participants)}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool { return res[i].score > res[j].score })
+
+	for i := range res {
+		candidates[i] = res[i].rng
+	}
+}
+
+// comfort returns the fraction of the range within the comfortable hours
+// of the participant.
+func (p ParticipantTZ) comfort(r trn.Range) float64 {
+	var w WeeklySchedule
+	for d := range w {
+		w[d] = []TimeRange{p.Comfortable}
+	}
+
+	loc := p.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	if r.Duration() == 0 {
+		// the point is comfortable if it is within the hours around it
+		for _, rng := range w.Expand(trn.NewBetween(r.Start().Add(-24*time.Hour), r.Start().Add(24*time.Hour)), loc) {
+			if rng.ContainsTime(r.Start()) {
+				return 1
+			}
+		}
+		return 0
+	}
+
+	var covered time.Duration
+	for _, rng := range w.Expand(r, loc) {
+		covered += rng.Duration()
+	}
+	return float64(covered) / float64(r.Duration())
+}
@@ -0,0 +1,66 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cappuccinotm/trn"
+	"github.com/stretchr/testify/assert"
+)
+
+func testParticipants(t *testing.T) []ParticipantTZ {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("tzdata is not available")
+	}
+
+	workday := TimeRange{Start: NewClock(9, 0, 0), End: NewClock(18, 0, 0)}
+	return []ParticipantTZ{
+		{Location: time.UTC, Comfortable: workday},
+		{Location: ny, Comfortable: workday}, // UTC-4 in June
+	}
+}
+
+func TestScore(t *testing.T) {
+	participants := testParticipants(t)
+
+	tests := []struct {
+		name string
+		rng  trn.Range
+		want float64
+	}{
+		{name: "comfortable for everyone", rng: trn.New(dhm(14, 14, 0), time.Hour), want: 1},
+		{name: "early in New York", rng: trn.New(dhm(14, 12, 0), time.Hour), want: 0.5},
+		{name: "half an hour early in New York", rng: trn.New(dhm(14, 12, 30), time.Hour), want: 0.75},
+		{name: "night for everyone", rng: trn.New(dhm(14, 3, 0), time.Hour), want: 0},
+		{name: "point", rng: trn.New(dhm(14, 13, 0), 0), want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, Score(tt.rng, participants), 1e-9)
+		})
+	}
+
+	assert.Zero(t, Score(trn.New(dhm(14, 14, 0), time.Hour), nil))
+}
+
+func TestSortByScore(t *testing.T) {
+	participants := testParticipants(t)
+
+	candidates := []trn.Range{
+		trn.New(dhm(14, 3, 0), time.Hour),
+		trn.New(dhm(14, 12, 0), time.Hour),
+		trn.New(dhm(14, 14, 0), time.Hour),
+		trn.New(dhm(14, 5, 0), time.Hour),
+		trn.New(dhm(14, 15, 0), time.Hour),
+	}
+
+	SortByScore(candidates, participants)
+	assert.Equal(t, []trn.Range{
+		trn.New(dhm(14, 14, 0), time.Hour),
+		trn.New(dhm(14, 15, 0), time.Hour),
+		trn.New(dhm(14, 12, 0), time.Hour),
+		trn.New(dhm(14, 3, 0), time.Hour),
+		trn.New(dhm(14, 5, 0), time.Hour),
+	}, candidates)
+}
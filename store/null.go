@@ -0,0 +1,172 @@
+package store
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// NullDate represents the Date, which may be absent. Like sql.NullTime, it
+// implements sql.Scanner and driver.Valuer, and is marshaled to JSON null
+// if not valid.
+type NullDate struct {
+	Date
+	Valid bool // Valid is true if Date is not NULL
+}
+
+// Scan implements sql.Scanner. Besides the text in format "2006-01-02", it
+// accepts time.Time, which drivers return for DATE columns.
+func (n *NullDate) Scan(src interface{}) error {
+	var err error
+	switch v := src.(type) {
+	case nil:
+		*n = NullDate{}
+		return nil
+	case time.Time:
+		n.Date = DateOf(v)
+	case string:
+		n.Date, err = ParseDate(v)
+	case []byte:
+		n.Date, err = ParseDate(string(v))
+	default:
+		return fmt.Errorf("%w: can't scan %T", ErrInvalidDate, src)
+	}
+	n.Valid = err == nil
+	return err
+}
+
+// Value implements driver.Valuer.
+func (n NullDate) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Date.String(), nil
+}
+
+// MarshalText implements encoding.TextMarshaler, the invalid Date is
+// marshaled to the empty text.
+func (n NullDate) MarshalText() ([]byte, error) {
+	if !n.Valid {
+		return []byte{}, nil
+	}
+	return n.Date.MarshalText()
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (n *NullDate) UnmarshalText(b []byte) error {
+	if len(b) == 0 {
+		*n = NullDate{}
+		return nil
+	}
+	if err := n.Date.UnmarshalText(b); err != nil {
+		*n = NullDate{}
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (n NullDate) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Date)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (n *NullDate) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(b, []byte("null")) {
+		*n = NullDate{}
+		return nil
+	}
+	if err := json.Unmarshal(b, &n.Date); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// NullClock represents the Clock, which may be absent. Like sql.NullTime,
+// it implements sql.Scanner and driver.Valuer, and is marshaled to JSON
+// null if not valid.
+type NullClock struct {
+	Clock
+	Valid bool // Valid is true if Clock is not NULL
+}
+
+// Scan implements sql.Scanner. It accepts the text in format "15:04" or
+// "15:04:05" and time.Time, which drivers return for TIME columns, the
+// time of day of time.Time is taken in its location.
+func (n *NullClock) Scan(src interface{}) error {
+	var err error
+	switch v := src.(type) {
+	case nil:
+		*n = NullClock{}
+		return nil
+	case time.Time:
+		n.Clock = NewClock(v.Hour(), v.Minute(), v.Second()) + Clock(v.Nanosecond())
+	case string:
+		n.Clock, err = ParseClock(v)
+	case []byte:
+		n.Clock, err = ParseClock(string(v))
+	default:
+		return fmt.Errorf("%w: can't scan %T", ErrInvalidClock, src)
+	}
+	n.Valid = err == nil
+	return err
+}
+
+// Value implements driver.Valuer.
+func (n NullClock) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Clock.String(), nil
+}
+
+// MarshalText implements encoding.TextMarshaler, the invalid Clock is
+// marshaled to the empty text.
+func (n NullClock) MarshalText() ([]byte, error) {
+	if !n.Valid {
+		return []byte{}, nil
+	}
+	return n.Clock.MarshalText()
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (n *NullClock) UnmarshalText(b []byte) error {
+	if len(b) == 0 {
+		*n = NullClock{}
+		return nil
+	}
+	if err := n.Clock.UnmarshalText(b); err != nil {
+		*n = NullClock{}
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (n NullClock) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Clock)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (n *NullClock) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(b, []byte("null")) {
+		*n = NullClock{}
+		return nil
+	}
+	if err := json.Unmarshal(b, &n.Clock); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
@@ -0,0 +1,131 @@
+package store
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNullDate(t *testing.T) {
+	type holiday struct {
+		Name string   `json:"name"`
+		Date NullDate `json:"date"`
+	}
+
+	b, err := json.Marshal(holiday{Name: "tbd"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"tbd","date":null}`, string(b))
+
+	want := holiday{Name: "midsummer", Date: NullDate{Date: NewDate(2021, time.June, 25), Valid: true}}
+	b, err = json.Marshal(want)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"midsummer","date":"2021-06-25"}`, string(b))
+
+	var got holiday
+	require.NoError(t, json.Unmarshal(b, &got))
+	assert.Equal(t, want, got)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"date":null}`), &got))
+	assert.False(t, got.Date.Valid)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"date":"2021-02-30"}`), &got), ErrInvalidDate)
+
+	v, err := want.Date.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "2021-06-25", v)
+
+	v, err = NullDate{}.Value()
+	require.NoError(t, err)
+	assert.Nil(t, v)
+
+	var n NullDate
+	require.NoError(t, n.Scan(dhm(25, 23, 0)))
+	assert.Equal(t, NullDate{Date: NewDate(2021, time.June, 25), Valid: true}, n)
+	require.NoError(t, n.Scan(nil))
+	assert.Equal(t, NullDate{}, n)
+	require.NoError(t, n.Scan([]byte("2021-06-25")))
+	assert.Equal(t, want.Date, n)
+	assert.ErrorIs(t, n.Scan("25.06.2021"), ErrInvalidDate)
+	assert.False(t, n.Valid)
+	assert.ErrorIs(t, n.Scan(42), ErrInvalidDate)
+
+	t.Run("text", func(t *testing.T) {
+		b, err := NullDate{}.MarshalText()
+		require.NoError(t, err)
+		assert.Empty(t, b)
+
+		b, err = want.Date.MarshalText()
+		require.NoError(t, err)
+		assert.Equal(t, "2021-06-25", string(b))
+
+		var n NullDate
+		require.NoError(t, n.UnmarshalText(b))
+		assert.Equal(t, want.Date, n)
+		require.NoError(t, n.UnmarshalText(nil))
+		assert.Equal(t, NullDate{}, n)
+		assert.ErrorIs(t, n.UnmarshalText([]byte("2021-02-30")), ErrInvalidDate)
+		assert.False(t, n.Valid)
+	})
+}
+
+func TestNullClock(t *testing.T) {
+	type shift struct {
+		Start NullClock `json:"start"`
+	}
+
+	b, err := json.Marshal(shift{})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"start":null}`, string(b))
+
+	want := shift{Start: NullClock{Clock: NewClock(9, 30, 0), Valid: true}}
+	b, err = json.Marshal(want)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"start":"09:30"}`, string(b))
+
+	var got shift
+	require.NoError(t, json.Unmarshal(b, &got))
+	assert.Equal(t, want, got)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"start":null}`), &got))
+	assert.False(t, got.Start.Valid)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"start":"9:30"}`), &got), ErrInvalidClock)
+
+	v, err := want.Start.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "09:30", v)
+
+	v, err = NullClock{}.Value()
+	require.NoError(t, err)
+	assert.Nil(t, v)
+
+	var n NullClock
+	require.NoError(t, n.Scan([]byte("09:30:15")))
+	assert.Equal(t, NullClock{Clock: NewClock(9, 30, 15), Valid: true}, n)
+	require.NoError(t, n.Scan("09:30"))
+	assert.Equal(t, want.Start, n)
+	require.NoError(t, n.Scan(nil))
+	assert.Equal(t, NullClock{}, n)
+	require.NoError(t, n.Scan(time.Date(0, time.January, 1, 9, 30, 15, 5, time.UTC)))
+	assert.Equal(t, NullClock{Clock: NewClock(9, 30, 15) + 5, Valid: true}, n)
+	assert.ErrorIs(t, n.Scan(int64(42)), ErrInvalidClock)
+
+	t.Run("text", func(t *testing.T) {
+		b, err := NullClock{}.MarshalText()
+		require.NoError(t, err)
+		assert.Empty(t, b)
+
+		b, err = want.Start.MarshalText()
+		require.NoError(t, err)
+		assert.Equal(t, "09:30", string(b))
+
+		var n NullClock
+		require.NoError(t, n.UnmarshalText(b))
+		assert.Equal(t, want.Start, n)
+		require.NoError(t, n.UnmarshalText(nil))
+		assert.Equal(t, NullClock{}, n)
+		assert.ErrorIs(t, n.UnmarshalText([]byte("9:30")), ErrInvalidClock)
+		assert.False(t, n.Valid)
+	})
+}
@@ -0,0 +1,181 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+var weekdayAbbrs = [7]string{"Su", "Mo", "Tu", "We", "Th", "Fr", "Sa"}
+
+// osmWeek lists the days of week in the order, used by opening_hours.
+var osmWeek = [7]time.Weekday{
+	time.Monday, time.Tuesday, time.Wednesday, time.Thursday,
+	time.Friday, time.Saturday, time.Sunday,
+}
+
+// ParseOpeningHours parses the subset of the OpenStreetMap opening_hours
+// syntax, e.g. "Mo-Fr 09:00-12:00,13:00-17:00; Sa 10:00-14:00; Su off".
+//
+// Supported are weekday selectors (ranges and lists), time ranges, "off"
+// and "24/7". The later rules override the earlier ones for the days they
+// select, the rule without weekday selector applies to all days.
+// Returns ErrInvalidOpeningHours for the unsupported or malformed rules.
+func ParseOpeningHours(s string) (WeeklySchedule, error) {
+	var res WeeklySchedule
+
+	for _, rule := range strings.Split(s, ";") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+
+		days := osmWeek[:]
+		if isWeekdaySelector(rule) {
+			idx := strings.IndexByte(rule, ' ')
+			if idx < 0 {
+				idx = len(rule)
+			}
+
+			var err error
+			if days, err = parseWeekdays(rule[:idx]); err != nil {
+				return WeeklySchedule{}, err
+			}
+			rule = strings.TrimSpace(rule[idx:])
+		}
+
+		trs, err := parseRuleTimes(rule)
+		if err != nil {
+			return WeeklySchedule{}, err
+		}
+
+		for _, d := range days {
+			res[d] = append([]TimeRange(nil), trs...)
+		}
+	}
+
+	return res, nil
+}
+
+// String returns the schedule in the OpenStreetMap opening_hours syntax.
+// Consecutive days with the same time ranges are collapsed into the
+// weekday range.
+func (w WeeklySchedule) String() string {
+	var rules []string
+
+	for i := 0; i < len(osmWeek); i++ {
+		trs := w[osmWeek[i]]
+		if len(trs) == 0 {
+			continue
+		}
+
+		j := i
+		for j+1 < len(osmWeek) && equalTimeRanges(trs, w[osmWeek[j+1]]) {
+			j++
+		}
+
+		sel := weekdayAbbrs[osmWeek[i]]
+		if j > i {
+			sel += "-" + weekdayAbbrs[osmWeek[j]]
+		}
+
+		times := make([]string, len(trs))
+		for k, tr := range trs {
+			times[k] = tr.String()
+		}
+
+		rules = append(rules, sel+" "+strings.Join(times, ","))
+		i = j
+	}
+
+	switch {
+	case len(rules) == 0:
+		return "off"
+	case rules[0] == "Mo-Su 00:00-24:00":
+		return "24/7"
+	default:
+		return strings.Join(rules, "; ")
+	}
+}
+
+func parseRuleTimes(s string) ([]TimeRange, error) {
+	switch s {
+	case "off", "closed":
+		return nil, nil
+	case "24/7", "":
+		return []TimeRange{{Start: 0, End: NewClock(24, 0, 0)}}, nil
+	}
+
+	var res []TimeRange
+	for _, part := range strings.Split(s, ",") {
+		tr, err := ParseTimeRange(strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %v", ErrInvalidOpeningHours, s, err)
+		}
+		res = append(res, tr)
+	}
+
+	return res, nil
+}
+
+func isWeekdaySelector(s string) bool {
+	if len(s) < 2 {
+		return false
+	}
+	_, ok := weekdayByAbbr(s[:2])
+	return ok
+}
+
+func parseWeekdays(s string) ([]time.Weekday, error) {
+	var res []time.Weekday
+
+	for _, part := range strings.Split(s, ",") {
+		bounds := strings.Split(part, "-")
+		if len(bounds) > 2 {
+			return nil, fmt.Errorf("%w: weekday selector %q", ErrInvalidOpeningHours, s)
+		}
+
+		from, ok := weekdayByAbbr(bounds[0])
+		if !ok {
+			return nil, fmt.Errorf("%w: weekday selector %q", ErrInvalidOpeningHours, s)
+		}
+
+		to := from
+		if len(bounds) == 2 {
+			if to, ok = weekdayByAbbr(bounds[1]); !ok {
+				return nil, fmt.Errorf("%w: weekday selector %q", ErrInvalidOpeningHours, s)
+			}
+		}
+
+		// ranges could wrap over the end of the week, e.g. "Fr-Mo"
+		for d := from; ; d = (d + 1) % 7 {
+			res = append(res, d)
+			if d == to {
+				break
+			}
+		}
+	}
+
+	return res, nil
+}
+
+func weekdayByAbbr(s string) (time.Weekday, bool) {
+	for i, abbr := range weekdayAbbrs {
+		if abbr == s {
+			return time.Weekday(i), true
+		}
+	}
+	return 0, false
+}
+
+func equalTimeRanges(a, b []TimeRange) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,79 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseOpeningHours(t *testing.T) {
+	nineToFive := []TimeRange{{Start: NewClock(9, 0, 0), End: NewClock(17, 0, 0)}}
+	allDay := []TimeRange{{Start: 0, End: NewClock(24, 0, 0)}}
+
+	tests := []struct {
+		name    string
+		arg     string
+		want    WeeklySchedule
+		wantErr error
+	}{
+		{name: "empty", arg: ""},
+		{
+			name: "weekday ranges",
+			arg:  "Mo-Fr 09:00-17:00; Sa 10:00-12:00,13:00-14:00",
+			want: WeeklySchedule{
+				time.Monday: nineToFive, time.Tuesday: nineToFive, time.Wednesday: nineToFive,
+				time.Thursday: nineToFive, time.Friday: nineToFive,
+				time.Saturday: {
+					{Start: NewClock(10, 0, 0), End: NewClock(12, 0, 0)},
+					{Start: NewClock(13, 0, 0), End: NewClock(14, 0, 0)},
+				},
+			},
+		},
+		{
+			name: "lists, wrapping and overrides",
+			arg:  "Fr-Mo,We 09:00-17:00; Su off",
+			want: WeeklySchedule{
+				time.Monday: nineToFive, time.Wednesday: nineToFive,
+				time.Friday: nineToFive, time.Saturday: nineToFive,
+			},
+		},
+		{
+			name: "24/7",
+			arg:  "24/7",
+			want: WeeklySchedule{allDay, allDay, allDay, allDay, allDay, allDay, allDay},
+		},
+		{
+			name: "weekday without times",
+			arg:  "Su",
+			want: WeeklySchedule{time.Sunday: allDay},
+		},
+		{name: "public holidays", arg: "PH off", wantErr: ErrInvalidOpeningHours},
+		{name: "invalid weekday", arg: "Mo-Xy 09:00-17:00", wantErr: ErrInvalidOpeningHours},
+		{name: "invalid time", arg: "Mo 09:00-27:00", wantErr: ErrInvalidOpeningHours},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseOpeningHours(tt.arg)
+			assert.Equal(t, tt.want, got)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestWeeklySchedule_String(t *testing.T) {
+	tests := []string{
+		"Mo-Fr 09:00-17:00; Sa 10:00-12:00,13:00-14:00",
+		"Mo 09:00-17:00; We 09:00-17:00; Fr-Sa 09:00-17:00",
+		"Sa-Su 22:00-02:00",
+		"24/7",
+		"off",
+	}
+	for _, tt := range tests {
+		t.Run(tt, func(t *testing.T) {
+			w, err := ParseOpeningHours(tt)
+			assert.NoError(t, err)
+			assert.Equal(t, tt, w.String())
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/cappuccinotm/trn"
+)
+
+// maxDeferWeeks limits the lookup of the allowed instant in Defer.
+const maxDeferWeeks = 53
+
+// QuietHours describes the do-not-disturb windows for each day of the week
+// in the location. Quiet hours, which start on the exception dates, are
+// not observed.
+type QuietHours struct {
+	Hours      WeeklySchedule
+	Exceptions []Date
+	Location   *time.Location
+}
+
+// Defer returns the next allowed instant not before t, i.e. t itself if it
+// is outside of the quiet hours, or the end of the quiet hours otherwise.
+// Returns zero time if there are no allowed instants within a year.
+func (q QuietHours) Defer(t time.Time) time.Time {
+	const week = 7 * 24 * time.Hour
+
+	for i := 0; i < maxDeferWeeks; i++ {
+		// look a day before to catch the quiet hours started earlier
+		period := trn.NewBetween(t.Add(-24*time.Hour), t.Add(week))
+
+		w := q.windows(period)
+		idx := len(w)
+		for j, win := range w {
+			if win.ContainsTime(t) {
+				idx = j
+				break
+			}
+		}
+
+		switch {
+		case idx == len(w):
+			return t
+		case w[idx].End().Before(period.End()):
+			return w[idx].End()
+		}
+
+		t = w[idx].End()
+	}
+
+	return time.Time{}
+}
+
+// Allowed returns the parts of the range outside of the quiet hours,
+// sorted.
+func (q QuietHours) Allowed(r trn.Range) []trn.Range {
+	return r.Exclude(q.windows(r))
+}
+
+// windows returns the quiet hours within the period.
+func (q QuietHours) windows(period trn.Range) []trn.Range {
+	res, _ := q.Hours.expand(context.Background(), period, q.location(), q.isException)
+	return res
+}
+
+func (q QuietHours) isException(d Date) bool {
+	for _, e := range q.Exceptions {
+		if e == d {
+			return true
+		}
+	}
+	return false
+}
+
+func (q QuietHours) location() *time.Location {
+	if q.Location == nil {
+		return time.UTC
+	}
+	return q.Location
+}
@@ -0,0 +1,74 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cappuccinotm/trn"
+	"github.com/stretchr/testify/assert"
+)
+
+func testQuietHours() QuietHours {
+	night := []TimeRange{{Start: NewClock(22, 0, 0), End: NewClock(7, 0, 0)}}
+	weekend := []TimeRange{{Start: NewClock(0, 0, 0), End: NewClock(0, 0, 0)}}
+	return QuietHours{
+		Hours: WeeklySchedule{
+			time.Sunday:    weekend,
+			time.Monday:    night,
+			time.Tuesday:   night,
+			time.Wednesday: night,
+			time.Thursday:  night,
+			time.Friday:    night,
+			time.Saturday:  weekend,
+		},
+		Exceptions: []Date{NewDate(2021, time.June, 15)}, // tuesday
+		Location:   time.UTC,
+	}
+}
+
+func TestQuietHours_Defer(t *testing.T) {
+	q := testQuietHours()
+
+	tests := []struct {
+		name string
+		t    time.Time
+		want time.Time
+	}{
+		{name: "allowed", t: dhm(14, 12, 0), want: dhm(14, 12, 0)},
+		{name: "at night", t: dhm(14, 23, 0), want: dhm(15, 7, 0)},
+		{name: "at the start", t: dhm(14, 22, 0), want: dhm(15, 7, 0)},
+		{name: "at the end", t: dhm(15, 7, 0), want: dhm(15, 7, 0)},
+		{name: "exception", t: dhm(15, 23, 0), want: dhm(15, 23, 0)},
+		{name: "over the weekend", t: dhm(18, 23, 0), want: dhm(21, 0, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, q.Defer(tt.t))
+		})
+	}
+
+	t.Run("beyond the horizon", func(t *testing.T) {
+		trn.SetHorizon(30 * 24 * time.Hour)
+		defer trn.SetHorizon(0)
+
+		// the same weekdays a decade later
+		future := func(t time.Time) time.Time { return t.AddDate(0, 0, 52*7*10) }
+		assert.Equal(t, future(dhm(15, 7, 0)), q.Defer(future(dhm(14, 23, 0))))
+	})
+
+	always := QuietHours{}
+	for d := range always.Hours {
+		always.Hours[d] = []TimeRange{{}}
+	}
+	assert.True(t, always.Defer(dhm(14, 12, 0)).IsZero())
+}
+
+func TestQuietHours_Allowed(t *testing.T) {
+	q := testQuietHours()
+
+	got := q.Allowed(trn.MustRange(trn.Between(dhm(14, 20, 0), dhm(16, 8, 0))))
+	assert.Equal(t, []trn.Range{
+		trn.MustRange(trn.Between(dhm(14, 20, 0), dhm(14, 22, 0))),
+		trn.MustRange(trn.Between(dhm(15, 7, 0), dhm(16, 8, 0))),
+	}, got)
+}
@@ -0,0 +1,54 @@
+package store
+
+import (
+	"sort"
+	"time"
+
+	"github.com/cappuccinotm/trn"
+)
+
+// DeriveReminders returns the times to fire reminders of the range, each
+// one the offset before its start, moved out of the quiet hours in the
+// given location, UTC if nil. The reminder, which falls within the quiet
+// hours, is deferred to their end as by QuietHours.Defer if it is still
+// not after the start of the range, or brought forward to their start
+// otherwise. The resulting times are sorted and deduplicated.
+func DeriveReminders(r trn.Range, offsets []time.Duration, quiet []TimeRange, loc *time.Location) []time.Time {
+	if len(offsets) == 0 {
+		return nil
+	}
+
+	q := QuietHours{Location: loc}
+	for d := range q.Hours {
+		q.Hours[d] = quiet
+	}
+
+	res := make([]time.Time, len(offsets))
+	for i, offset := range offsets {
+		t := r.Start().Add(-offset)
+		res[i] = t
+
+		switch deferred := q.Defer(t); {
+		case deferred.Equal(t):
+			// outside of the quiet hours
+		case !deferred.IsZero() && !deferred.After(r.Start()):
+			res[i] = deferred
+		default:
+			// quiet hours might last up to a day, so look a day back for
+			// the last allowed instant before them
+			if allowed := q.Allowed(trn.NewBetween(trn.AddSaturating(t, -24*time.Hour), t)); len(allowed) > 0 {
+				res[i] = allowed[len(allowed)-1].End()
+			}
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Before(res[j]) })
+
+	uniq := res[:1]
+	for _, t := range res[1:] {
+		if !t.Equal(uniq[len(uniq)-1]) {
+			uniq = append(uniq, t)
+		}
+	}
+	return uniq
+}
@@ -0,0 +1,69 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cappuccinotm/trn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeriveReminders(t *testing.T) {
+	quiet := []TimeRange{{Start: NewClock(22, 0, 0), End: NewClock(7, 0, 0)}}
+	appointment := trn.New(dhm(14, 9, 0), time.Hour)
+
+	tests := []struct {
+		name    string
+		offsets []time.Duration
+		quiet   []TimeRange
+		want    []time.Time
+	}{
+		{
+			name:    "outside quiet hours",
+			offsets: []time.Duration{15 * time.Minute, time.Hour},
+			quiet:   quiet,
+			want:    []time.Time{dhm(14, 8, 0), dhm(14, 8, 45)},
+		},
+		{
+			name:    "deferred to the end of quiet hours",
+			offsets: []time.Duration{4 * time.Hour},
+			quiet:   quiet,
+			want:    []time.Time{dhm(14, 7, 0)},
+		},
+		{
+			name:    "brought forward to the start of quiet hours",
+			offsets: []time.Duration{4 * time.Hour},
+			quiet:   []TimeRange{{Start: NewClock(4, 0, 0), End: NewClock(10, 0, 0)}},
+			want:    []time.Time{dhm(14, 4, 0)},
+		},
+		{
+			name:    "at the boundary",
+			offsets: []time.Duration{2 * time.Hour},
+			quiet:   quiet,
+			want:    []time.Time{dhm(14, 7, 0)},
+		},
+		{
+			name:    "at the start of quiet hours",
+			offsets: []time.Duration{11 * time.Hour},
+			quiet:   quiet,
+			want:    []time.Time{dhm(14, 7, 0)},
+		},
+		{
+			name:    "deduplicated",
+			offsets: []time.Duration{3 * time.Hour, 5 * time.Hour, 24 * time.Hour},
+			quiet:   quiet,
+			want:    []time.Time{dhm(13, 9, 0), dhm(14, 7, 0)},
+		},
+		{
+			name:    "without quiet hours",
+			offsets: []time.Duration{5 * time.Hour},
+			want:    []time.Time{dhm(14, 4, 0)},
+		},
+		{name: "no offsets"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, DeriveReminders(appointment, tt.offsets, tt.quiet, time.UTC))
+		})
+	}
+}
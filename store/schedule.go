@@ -0,0 +1,71 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/cappuccinotm/trn"
+)
+
+// WeeklySchedule describes the recurring time ranges for each day of the
+// week, indexed by time.Weekday.
+type WeeklySchedule [7][]TimeRange
+
+// Expand returns the concrete ranges of the schedule within the period,
+// with clocks interpreted in the given location. The resulting ranges are
+// truncated to the period, merged and sorted.
+func (w WeeklySchedule) Expand(period trn.Range, loc *time.Location) []trn.Range {
+	res, _ := w.ExpandContext(context.Background(), period, loc)
+	return res
+}
+
+// ExpandContext is like Expand, but stops once the context is done,
+// returning the ranges expanded so far along with the context's error.
+func (w WeeklySchedule) ExpandContext(ctx context.Context, period trn.Range, loc *time.Location) ([]trn.Range, error) {
+	return w.expand(ctx, period, loc, func(Date) bool { return false })
+}
+
+// Empty returns true if there are no time ranges in the schedule.
+func (w WeeklySchedule) Empty() bool {
+	for _, trs := range w {
+		if len(trs) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// expand returns the concrete ranges of the schedule within the period,
+// skipping the ranges, which start at the dates, for which skip returns
+// true. Once the context is done, it returns the ranges expanded so far
+// along with the context's error.
+func (w WeeklySchedule) expand(ctx context.Context, period trn.Range, loc *time.Location,
+	skip func(Date) bool) ([]trn.Range, error) {
+	var res []trn.Range
+	var err error
+
+	// start a day earlier to catch the ranges crossing midnight
+	day, last := DateOf(period.Start().In(loc)).AddDays(-1), DateOf(period.End().In(loc))
+	for ; !day.After(last); day = day.AddDays(1) {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+
+		if skip(day) {
+			continue
+		}
+
+		for _, tr := range w[day.Weekday()] {
+			rng := Between(day, tr, loc).Truncate(period)
+			if rng.Duration() > 0 {
+				res = append(res, rng)
+			}
+		}
+	}
+
+	if len(res) == 0 {
+		return nil, err
+	}
+
+	return trn.MergeOverlappingRanges(res), err
+}
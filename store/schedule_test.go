@@ -0,0 +1,96 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cappuccinotm/trn"
+	"github.com/stretchr/testify/assert"
+)
+
+// dt is Saturday
+var dt = time.Date(2021, 6, 12, 0, 0, 0, 0, time.UTC)
+
+func dhm(d, h, m int) time.Time {
+	return time.Date(dt.Year(), dt.Month(), d, h, m, 0, 0, time.UTC)
+}
+
+func formatRanges(rngs []trn.Range) []string {
+	res := make([]string, len(rngs))
+	for i, rng := range rngs {
+		res[i] = rng.Format("Mon 02 15:04")
+	}
+	return res
+}
+
+func TestWeeklySchedule_Expand(t *testing.T) {
+	var w WeeklySchedule
+	w[time.Friday] = []TimeRange{{Start: NewClock(22, 0, 0), End: NewClock(2, 0, 0)}}
+	w[time.Saturday] = []TimeRange{
+		{Start: NewClock(1, 0, 0), End: NewClock(3, 0, 0)}, // overlaps with friday's night
+		{Start: NewClock(10, 0, 0), End: NewClock(14, 0, 0)},
+	}
+	w[time.Monday] = []TimeRange{{Start: NewClock(9, 0, 0), End: NewClock(17, 0, 0)}}
+
+	period := trn.MustRange(trn.Between(dhm(12, 0, 0), dhm(14, 12, 0)))
+	assert.Equal(t, []string{
+		"[Sat 12 00:00, Sat 12 03:00]",
+		"[Sat 12 10:00, Sat 12 14:00]",
+		"[Mon 14 09:00, Mon 14 12:00]",
+	}, formatRanges(w.Expand(period, time.UTC)))
+
+	assert.Empty(t, WeeklySchedule{}.Expand(period, time.UTC))
+}
+
+func TestWeeklySchedule_ExpandContext(t *testing.T) {
+	var w WeeklySchedule
+	for d := range w {
+		w[d] = []TimeRange{{Start: NewClock(9, 0, 0), End: NewClock(17, 0, 0)}}
+	}
+	period := trn.MustRange(trn.Between(dhm(12, 0, 0), dhm(19, 0, 0)))
+
+	got, err := w.ExpandContext(context.Background(), period, time.UTC)
+	assert.NoError(t, err)
+	assert.Len(t, got, 7)
+
+	// the day before the period and two days of it are expanded
+	got, err = w.ExpandContext(&countdownCtx{Context: context.Background(), left: 3}, period, time.UTC)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []string{"[Sat 12 09:00, Sat 12 17:00]", "[Sun 13 09:00, Sun 13 17:00]"}, formatRanges(got))
+}
+
+// countdownCtx is the context, which is canceled after the given number of
+// checks.
+type countdownCtx struct {
+	context.Context
+	left int
+}
+
+func (c *countdownCtx) Err() error {
+	if c.left <= 0 {
+		return context.Canceled
+	}
+	c.left--
+	return nil
+}
+
+func TestWeeklySchedule_Expand_DST(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("tzdata is not available")
+	}
+
+	var w WeeklySchedule
+	w[time.Sunday] = []TimeRange{{Start: NewClock(1, 0, 0), End: NewClock(4, 0, 0)}}
+
+	// DST starts on 28 March 2021 at 02:00 in Berlin
+	day := time.Date(2021, 3, 28, 0, 0, 0, 0, loc)
+	rngs := w.Expand(trn.New(day, 24*time.Hour), loc)
+	assert.Len(t, rngs, 1)
+	assert.Equal(t, 2*time.Hour, rngs[0].Duration())
+
+	// both clocks within the gap
+	w[time.Sunday] = []TimeRange{{Start: NewClock(2, 30, 0), End: NewClock(3, 0, 0)}}
+	assert.Empty(t, w.Expand(trn.New(day, 24*time.Hour), loc))
+}
@@ -0,0 +1,283 @@
+package stream
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/cappuccinotm/trn"
+)
+
+// MergeSortedStreams merges the overlapping and touching ranges of the
+// streams, each sorted by the start of ranges, and writes the merged ranges
+// to dst in the chronological order. Only one range per stream is kept in
+// memory. Returns ErrUnsorted if any of the streams is not sorted.
+// Once the context is done, it stops and returns the context's error, the
+// ranges written so far are left in dst. The Progress hook is reported at
+// StageMerge.
+func MergeSortedStreams(ctx context.Context, dst Writer, streams []Reader, opts ...Option) error {
+	pr := newOptions(opts).progress(StageMerge)
+
+	h := &heads{}
+	for i, s := range streams {
+		if err := h.advance(s, i, trn.Range{}); err != nil {
+			return err
+		}
+	}
+
+	var cur trn.Range
+	started := false
+	for h.Len() > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		top := (*h)[0]
+		if err := h.advance(streams[top.idx], top.idx, top.rng); err != nil {
+			return err
+		}
+		pr.tick()
+
+		switch {
+		case !started:
+			cur, started = top.rng, true
+		case !top.rng.Start().After(cur.End()):
+			if top.rng.End().After(cur.End()) {
+				cur = trn.NewBetween(cur.Start(), top.rng.End())
+			}
+		default:
+			if err := dst.Write(cur); err != nil {
+				return err
+			}
+			cur = top.rng
+		}
+	}
+
+	if started {
+		if err := dst.Write(cur); err != nil {
+			return err
+		}
+	}
+
+	pr.finish()
+	return nil
+}
+
+// head is the current range of the stream.
+type head struct {
+	rng trn.Range
+	idx int
+}
+
+// heads is the min-heap of the streams' heads ordered by start.
+type heads []head
+
+func (h heads) Len() int            { return len(h) }
+func (h heads) Less(i, j int) bool  { return h[i].rng.Start().Before(h[j].rng.Start()) }
+func (h heads) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *heads) Push(x interface{}) { *h = append(*h, x.(head)) }
+func (h *heads) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// advance reads the next range of the stream with the given index and puts
+// it into the heap, replacing the previous head of the stream, if it was
+// on top of the heap. The stream's head is removed from the heap if the
+// stream is exhausted.
+func (h *heads) advance(s Reader, idx int, prev trn.Range) error {
+	onTop := h.Len() > 0 && (*h)[0].idx == idx
+
+	r, err := s.Read()
+	switch {
+	case errors.Is(err, io.EOF):
+		if onTop {
+			heap.Pop(h)
+		}
+		return nil
+	case err != nil:
+		return err
+	case onTop && r.Start().Before(prev.Start()):
+		return ErrUnsorted
+	}
+
+	if onTop {
+		(*h)[0] = head{rng: r, idx: idx}
+		heap.Fix(h, 0)
+		return nil
+	}
+
+	heap.Push(h, head{rng: r, idx: idx})
+	return nil
+}
+
+// Option configures the batch operations.
+type Option func(o *options)
+
+type options struct {
+	runSize  int
+	tempDir  string
+	every    int64
+	onReport func(stage Stage, processed int64)
+}
+
+func newOptions(opts []Option) options {
+	o := options{runSize: 1 << 20}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.runSize < 1 {
+		o.runSize = 1
+	}
+	if o.every < 1 {
+		o.every = 1
+	}
+	return o
+}
+
+// RunSize sets the maximum number of ranges kept in memory, 1 << 20 by
+// default.
+func RunSize(n int) Option { return func(o *options) { o.runSize = n } }
+
+// TempDir sets the directory for the temporary files, os.TempDir() by
+// default.
+func TempDir(dir string) Option { return func(o *options) { o.tempDir = dir } }
+
+// Stage is the stage of the batch operation reported to the progress hook.
+type Stage string
+
+// Stages of batch operations.
+const (
+	// StageRead is reading and sorting of the unsorted source.
+	StageRead Stage = "read"
+	// StageMerge is merging of the sorted streams.
+	StageMerge Stage = "merge"
+)
+
+// Progress sets the hook, which is called every n ranges processed at the
+// stage of the operation with the number of ranges processed at the stage
+// so far, and once more when the stage is completed.
+func Progress(n int, fn func(stage Stage, processed int64)) Option {
+	return func(o *options) { o.every, o.onReport = int64(n), fn }
+}
+
+// progress counts the ranges processed at the stage and reports them to
+// the hook, if any.
+type progress struct {
+	stage     Stage
+	every     int64
+	onReport  func(stage Stage, processed int64)
+	processed int64
+}
+
+func (o options) progress(stage Stage) *progress {
+	return &progress{stage: stage, every: o.every, onReport: o.onReport}
+}
+
+func (p *progress) tick() {
+	p.processed++
+	if p.onReport != nil && p.processed%p.every == 0 {
+		p.onReport(p.stage, p.processed)
+	}
+}
+
+func (p *progress) finish() {
+	if p.onReport != nil && p.processed%p.every != 0 {
+		p.onReport(p.stage, p.processed)
+	}
+}
+
+// Merge merges the overlapping and touching ranges of the unsorted source,
+// which might not fit into memory, and writes the merged ranges to dst in
+// the chronological order. The source is read in runs of RunSize ranges,
+// each run is sorted and written to a temporary file, then the runs are
+// merged with MergeSortedStreams. Temporary files are removed afterwards.
+// Once the context is done, it stops and returns the context's error.
+// The Progress hook is reported at StageRead for the ranges read from the
+// source and at StageMerge for the ranges of the runs being merged.
+func Merge(ctx context.Context, dst Writer, src Reader, opts ...Option) (err error) {
+	o := newOptions(opts)
+	pr := o.progress(StageRead)
+
+	var runs []*os.File
+	defer func() {
+		for _, f := range runs {
+			_ = f.Close()
+			if rmErr := os.Remove(f.Name()); rmErr != nil && err == nil {
+				err = rmErr
+			}
+		}
+	}()
+
+	buf := make([]trn.Range, 0, o.runSize)
+	for eof := false; !eof; {
+		buf = buf[:0]
+		for len(buf) < o.runSize {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			r, err := src.Read()
+			if errors.Is(err, io.EOF) {
+				eof = true
+				break
+			}
+			if err != nil {
+				return err
+			}
+			buf = append(buf, r)
+			pr.tick()
+		}
+
+		trn.SortRanges(buf)
+
+		if eof && len(runs) == 0 {
+			// everything fits into memory
+			pr.finish()
+			return MergeSortedStreams(ctx, dst, []Reader{FromSlice(buf)}, opts...)
+		}
+
+		if len(buf) == 0 {
+			break
+		}
+
+		f, err := writeRun(ctx, o.tempDir, buf)
+		if f != nil {
+			runs = append(runs, f)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	pr.finish()
+
+	streams := make([]Reader, len(runs))
+	for i, f := range runs {
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		streams[i] = NewLineReader(f)
+	}
+
+	return MergeSortedStreams(ctx, dst, streams, opts...)
+}
+
+// writeRun writes the sorted run of ranges into a new temporary file.
+func writeRun(ctx context.Context, dir string, rngs []trn.Range) (*os.File, error) {
+	f, err := os.CreateTemp(dir, "trn-run-*")
+	if err != nil {
+		return nil, err
+	}
+
+	// merge the run beforehand to write less
+	w := NewLineWriter(f)
+	if err = MergeSortedStreams(ctx, w, []Reader{FromSlice(rngs)}); err != nil {
+		return f, err
+	}
+	return f, w.Flush()
+}
@@ -0,0 +1,196 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"os"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/cappuccinotm/trn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func randomRanges(rnd *rand.Rand, n int) []trn.Range {
+	res := make([]trn.Range, n)
+	for i := range res {
+		res[i] = trn.New(tm(0, 0).Add(time.Duration(rnd.Intn(24*60))*time.Minute),
+			time.Duration(rnd.Intn(30))*time.Minute)
+	}
+	return res
+}
+
+func TestMergeSortedStreams(t *testing.T) {
+	t.Run("matches MergeOverlappingRanges", func(t *testing.T) {
+		rnd := rand.New(rand.NewSource(42))
+		var all []trn.Range
+		var streams []Reader
+		for i := 0; i < 5; i++ {
+			rngs := randomRanges(rnd, 50)
+			sortRanges(rngs)
+			all = append(all, rngs...)
+			streams = append(streams, FromSlice(rngs))
+		}
+
+		w := &SliceWriter{}
+		require.NoError(t, MergeSortedStreams(context.Background(), w, streams))
+		assert.Equal(t, trn.MergeOverlappingRanges(all), w.Ranges)
+	})
+
+	t.Run("touching ranges", func(t *testing.T) {
+		w := &SliceWriter{}
+		require.NoError(t, MergeSortedStreams(context.Background(), w, []Reader{
+			FromSlice([]trn.Range{trn.New(tm(9, 0), time.Hour), trn.New(tm(12, 0), time.Hour)}),
+			FromSlice([]trn.Range{trn.New(tm(10, 0), time.Hour), trn.New(tm(14, 0), time.Hour)}),
+			FromSlice(nil),
+		}))
+		assert.Equal(t, []trn.Range{
+			trn.New(tm(9, 0), 2*time.Hour),
+			trn.New(tm(12, 0), time.Hour),
+			trn.New(tm(14, 0), time.Hour),
+		}, w.Ranges)
+	})
+
+	t.Run("open-ended ranges", func(t *testing.T) {
+		w := &SliceWriter{}
+		require.NoError(t, MergeSortedStreams(context.Background(), w, []Reader{
+			FromSlice([]trn.Range{trn.OpenEnded(tm(9, 0))}),
+			FromSlice([]trn.Range{trn.OpenEnded(tm(10, 0))}),
+		}))
+		assert.Equal(t, []trn.Range{trn.OpenEnded(tm(9, 0))}, w.Ranges)
+	})
+
+	t.Run("no ranges", func(t *testing.T) {
+		w := &SliceWriter{}
+		require.NoError(t, MergeSortedStreams(context.Background(), w, []Reader{FromSlice(nil)}))
+		assert.Empty(t, w.Ranges)
+	})
+
+	t.Run("unsorted", func(t *testing.T) {
+		err := MergeSortedStreams(context.Background(), &SliceWriter{}, []Reader{
+			FromSlice([]trn.Range{trn.New(tm(10, 0), time.Hour), trn.New(tm(9, 0), time.Hour)}),
+		})
+		assert.ErrorIs(t, err, ErrUnsorted)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		w := &SliceWriter{}
+		err := MergeSortedStreams(ctx, w, []Reader{&cancelingReader{
+			Reader: FromSlice([]trn.Range{
+				trn.New(tm(9, 0), time.Hour), trn.New(tm(11, 0), time.Hour), trn.New(tm(13, 0), time.Hour),
+			}),
+			cancel: cancel,
+			left:   3,
+		}})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, []trn.Range{trn.New(tm(9, 0), time.Hour)}, w.Ranges)
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		err := MergeSortedStreams(context.Background(), &SliceWriter{}, []Reader{failingReader{}})
+		assert.EqualError(t, err, "failed")
+	})
+}
+
+func TestMerge(t *testing.T) {
+	rngs := randomRanges(rand.New(rand.NewSource(7)), 1000)
+	dir := t.TempDir()
+
+	w := &SliceWriter{}
+	require.NoError(t, Merge(context.Background(), w, FromSlice(rngs), RunSize(64), TempDir(dir)))
+	assert.Equal(t, trn.MergeOverlappingRanges(rngs), w.Ranges)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "temporary files must be removed")
+
+	w = &SliceWriter{}
+	require.NoError(t, Merge(context.Background(), w, FromSlice(rngs)))
+	assert.Equal(t, trn.MergeOverlappingRanges(rngs), w.Ranges)
+
+	assert.EqualError(t, Merge(context.Background(), &SliceWriter{}, failingReader{}), "failed")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, Merge(ctx, &SliceWriter{}, FromSlice(rngs), TempDir(dir)), context.Canceled)
+}
+
+func sortRanges(rngs []trn.Range) {
+	sort.Slice(rngs, func(i, j int) bool { return rngs[i].Start().Before(rngs[j].Start()) })
+}
+
+type failingReader struct{}
+
+func (failingReader) Read() (trn.Range, error) { return trn.Range{}, errors.New("failed") }
+
+// cancelingReader cancels the context after the given number of reads.
+type cancelingReader struct {
+	Reader
+	cancel context.CancelFunc
+	left   int
+}
+
+func (c *cancelingReader) Read() (trn.Range, error) {
+	if c.left--; c.left == 0 {
+		c.cancel()
+	}
+	return c.Reader.Read()
+}
+
+func TestProgress(t *testing.T) {
+	type report struct {
+		stage     Stage
+		processed int64
+	}
+
+	t.Run("sorted streams", func(t *testing.T) {
+		var reports []report
+		err := MergeSortedStreams(context.Background(), &SliceWriter{}, []Reader{
+			FromSlice([]trn.Range{trn.New(tm(9, 0), time.Hour), trn.New(tm(12, 0), time.Hour)}),
+			FromSlice([]trn.Range{trn.New(tm(10, 0), time.Hour)}),
+		}, Progress(2, func(stage Stage, processed int64) {
+			reports = append(reports, report{stage: stage, processed: processed})
+		}))
+		require.NoError(t, err)
+		assert.Equal(t, []report{{stage: StageMerge, processed: 2}, {stage: StageMerge, processed: 3}}, reports)
+	})
+
+	t.Run("external merge", func(t *testing.T) {
+		var reports []report
+		err := Merge(context.Background(), &SliceWriter{},
+			FromSlice(randomRanges(rand.New(rand.NewSource(7)), 1000)),
+			RunSize(64), TempDir(t.TempDir()),
+			Progress(100, func(stage Stage, processed int64) {
+				reports = append(reports, report{stage: stage, processed: processed})
+			}),
+		)
+		require.NoError(t, err)
+
+		require.True(t, len(reports) > 10)
+		for i := 0; i < 10; i++ {
+			assert.Equal(t, report{stage: StageRead, processed: int64(i+1) * 100}, reports[i])
+		}
+		for _, r := range reports[10:] {
+			assert.Equal(t, StageMerge, r.stage)
+		}
+	})
+
+	t.Run("fits into memory", func(t *testing.T) {
+		var reports []report
+		err := Merge(context.Background(), &SliceWriter{},
+			FromSlice([]trn.Range{trn.New(tm(9, 0), time.Hour), trn.New(tm(8, 0), time.Hour)}),
+			Progress(0, func(stage Stage, processed int64) {
+				reports = append(reports, report{stage: stage, processed: processed})
+			}),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, []report{
+			{stage: StageRead, processed: 1}, {stage: StageRead, processed: 2},
+			{stage: StageMerge, processed: 1}, {stage: StageMerge, processed: 2},
+		}, reports)
+	})
+}
@@ -0,0 +1,107 @@
+// Package stream provides the streaming operations over ranges, which
+// don't fit into memory, e.g. huge historical schedule datasets.
+package stream
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/cappuccinotm/trn"
+)
+
+// ErrUnsorted is returned if the ranges in the stream are not sorted by
+// their start.
+const ErrUnsorted = trn.Error("stream: ranges are not sorted")
+
+// Reader is a stream of ranges.
+type Reader interface {
+	// Read returns the next range in the stream, or io.EOF if there are no
+	// ranges left.
+	Read() (trn.Range, error)
+}
+
+// Writer is a sink of ranges.
+type Writer interface {
+	// Write writes the range to the sink.
+	Write(r trn.Range) error
+}
+
+// FromSlice returns the Reader of the ranges in the slice.
+func FromSlice(rngs []trn.Range) Reader { return &sliceReader{rngs: rngs} }
+
+type sliceReader struct{ rngs []trn.Range }
+
+func (s *sliceReader) Read() (trn.Range, error) {
+	if len(s.rngs) == 0 {
+		return trn.Range{}, io.EOF
+	}
+	r := s.rngs[0]
+	s.rngs = s.rngs[1:]
+	return r, nil
+}
+
+// SliceWriter is a Writer, which collects the ranges into the slice.
+type SliceWriter struct{ Ranges []trn.Range }
+
+// Write appends the range to the slice.
+func (s *SliceWriter) Write(r trn.Range) error {
+	s.Ranges = append(s.Ranges, r)
+	return nil
+}
+
+// NewLineReader returns the Reader of ranges, written one per line in the
+// format of trn.Range.MarshalText. Empty lines are skipped.
+func NewLineReader(r io.Reader) Reader {
+	return &lineReader{sc: bufio.NewScanner(r)}
+}
+
+type lineReader struct {
+	sc   *bufio.Scanner
+	line int
+}
+
+func (l *lineReader) Read() (trn.Range, error) {
+	for l.sc.Scan() {
+		l.line++
+		b := bytes.TrimSpace(l.sc.Bytes())
+		if len(b) == 0 {
+			continue
+		}
+
+		var r trn.Range
+		if err := r.UnmarshalText(b); err != nil {
+			return trn.Range{}, fmt.Errorf("line %d: %w", l.line, err)
+		}
+		return r, nil
+	}
+
+	if err := l.sc.Err(); err != nil {
+		return trn.Range{}, err
+	}
+	return trn.Range{}, io.EOF
+}
+
+// LineWriter is a Writer of ranges, one per line, in the format of
+// trn.Range.MarshalText. The output is buffered, Flush must be called after
+// the last range is written.
+type LineWriter struct{ w *bufio.Writer }
+
+// NewLineWriter returns the LineWriter, writing to w.
+func NewLineWriter(w io.Writer) *LineWriter { return &LineWriter{w: bufio.NewWriter(w)} }
+
+// Write writes the range on a separate line.
+func (l *LineWriter) Write(r trn.Range) error {
+	b, err := r.MarshalText()
+	if err != nil {
+		return err
+	}
+	if _, err = l.w.Write(b); err != nil {
+		return err
+	}
+	return l.w.WriteByte('\n')
+}
+
+// Flush writes the buffered data to the underlying writer.
+func (l *LineWriter) Flush() error { return l.w.Flush() }
@@ -0,0 +1,67 @@
+package stream
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/cappuccinotm/trn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tm(h, m int) time.Time { return time.Date(2021, 6, 12, h, m, 0, 0, time.UTC) }
+
+func TestLines(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	rngs := []trn.Range{
+		trn.MustRange(trn.Between(tm(9, 0), tm(10, 0))),
+		trn.New(tm(13, 0).In(berlin), 30*time.Minute),
+	}
+
+	buf := &bytes.Buffer{}
+	w := NewLineWriter(buf)
+	for _, r := range rngs {
+		require.NoError(t, w.Write(r))
+	}
+	require.NoError(t, w.Flush())
+	assert.Equal(t,
+		"2021-06-12T09:00:00Z/2021-06-12T10:00:00Z\n"+
+			"2021-06-12T15:00:00+02:00[Europe/Berlin]/2021-06-12T15:30:00+02:00[Europe/Berlin]\n",
+		buf.String())
+
+	buf.WriteString("\n")
+	r := NewLineReader(buf)
+	for _, want := range rngs {
+		got, err := r.Read()
+		require.NoError(t, err)
+		assert.True(t, want.Start().Equal(got.Start()))
+		assert.Equal(t, want.Duration(), got.Duration())
+		assert.Equal(t, want.Start().Location().String(), got.Start().Location().String())
+	}
+	_, err = r.Read()
+	assert.ErrorIs(t, err, io.EOF)
+
+	_, err = NewLineReader(bytes.NewBufferString("\nblah\n")).Read()
+	assert.ErrorIs(t, err, trn.ErrMalformedRange)
+	assert.Contains(t, err.Error(), "line 2")
+}
+
+func TestSlices(t *testing.T) {
+	rngs := []trn.Range{trn.New(tm(9, 0), time.Hour), trn.New(tm(11, 0), time.Hour)}
+	w := &SliceWriter{}
+	r := FromSlice(rngs)
+	for {
+		rng, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		require.NoError(t, w.Write(rng))
+	}
+	assert.Equal(t, rngs, w.Ranges)
+}
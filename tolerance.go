@@ -0,0 +1,78 @@
+package trn
+
+import (
+	"sort"
+	"time"
+)
+
+// EqualWithin returns true if the starts and the ends of the ranges differ
+// by no more than the tolerance, e.g. to reconcile the ranges coming from
+// systems with different rounding.
+func EqualWithin(a, b Range, tol time.Duration) bool {
+	return absDuration(a.st.Sub(b.st)) <= tol && absDuration(a.End().Sub(b.End())) <= tol
+}
+
+// OverlapsWithin returns true if the ranges share more time than the
+// tolerance, so that micro-overlaps caused by rounding are not reported.
+func OverlapsWithin(a, b Range, tol time.Duration) bool {
+	return a.Overlaps(b) && a.Truncate(b).dur > tol
+}
+
+// UpcomingWithin is Upcoming, tolerant to the clock skew: now within ±skew
+// from the start of the range is treated as equal to it, e.g. for booking
+// nodes with slightly drifting clocks. For any skew, exactly one of
+// UpcomingWithin, ActiveWithin and ExpiredWithin holds for the non-empty
+// range.
+func UpcomingWithin(r Range, now time.Time, skew time.Duration) bool {
+	return now.Before(r.st.Add(-absDuration(skew)))
+}
+
+// ActiveWithin is Active, tolerant to the clock skew, see UpcomingWithin.
+// The range is considered active since its start minus skew till its end
+// minus skew.
+func ActiveWithin(r Range, now time.Time, skew time.Duration) bool {
+	return !UpcomingWithin(r, now, skew) && !ExpiredWithin(r, now, skew)
+}
+
+// ExpiredWithin is Expired, tolerant to the clock skew, see UpcomingWithin.
+func ExpiredWithin(r Range, now time.Time, skew time.Duration) bool {
+	return !now.Before(r.End().Add(-absDuration(skew)))
+}
+
+// DedupWithin removes the ranges, which are equal within the tolerance to
+// some earlier range, e.g. the same events imported from two calendars
+// with different rounding. Ranges are compared in the order of their
+// starts, the first range of each group of duplicates is kept.
+// The resulting ranges are sorted as by SortRanges.
+func DedupWithin(ranges []Range, tol time.Duration) []Range {
+	sorted := make([]Range, len(ranges))
+	copy(sorted, ranges)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].st.Before(sorted[j].st) })
+
+	var res []Range
+	for _, rng := range sorted {
+		if !hasDuplicate(res, rng, tol) {
+			res = append(res, rng)
+		}
+	}
+	SortRanges(res)
+	return res
+}
+
+// hasDuplicate returns true if there is a range, equal to r within the
+// tolerance, among the ranges, sorted by start.
+func hasDuplicate(sorted []Range, r Range, tol time.Duration) bool {
+	for i := len(sorted) - 1; i >= 0 && r.st.Sub(sorted[i].st) <= tol; i-- {
+		if EqualWithin(sorted[i], r, tol) {
+			return true
+		}
+	}
+	return false
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
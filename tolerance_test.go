@@ -0,0 +1,110 @@
+package trn
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEqualWithin(t *testing.T) {
+	rng := MustRange(Between(tm(13, 0), tm(14, 0)))
+
+	tests := []struct {
+		name  string
+		other Range
+		want  bool
+	}{
+		{name: "equal", other: rng, want: true},
+		{name: "start differs within", other: MustRange(Between(tm(12, 59), tm(14, 0))), want: true},
+		{name: "end differs within", other: MustRange(Between(tm(13, 0), tm(14, 1))), want: true},
+		{name: "start differs beyond", other: MustRange(Between(tm(12, 58), tm(14, 0)))},
+		{name: "end differs beyond", other: MustRange(Between(tm(13, 0), tm(13, 58)))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, EqualWithin(rng, tt.other, time.Minute))
+			assert.Equal(t, tt.want, EqualWithin(tt.other, rng, time.Minute))
+		})
+	}
+}
+
+func TestOverlapsWithin(t *testing.T) {
+	rng := MustRange(Between(tm(13, 0), tm(14, 0)))
+
+	tests := []struct {
+		name  string
+		other Range
+		want  bool
+	}{
+		{name: "touching", other: MustRange(Between(tm(14, 0), tm(15, 0)))},
+		{name: "micro overlap", other: MustRange(Between(tm(13, 59), tm(15, 0)))},
+		{name: "overlap", other: MustRange(Between(tm(13, 58), tm(15, 0))), want: true},
+		{name: "disjoint", other: MustRange(Between(tm(15, 0), tm(16, 0)))},
+		{name: "within", other: MustRange(Between(tm(13, 10), tm(13, 20))), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, OverlapsWithin(rng, tt.other, time.Minute))
+			assert.Equal(t, tt.want, OverlapsWithin(tt.other, rng, time.Minute))
+		})
+	}
+}
+
+func TestDedupWithin(t *testing.T) {
+	ranges := []Range{
+		MustRange(Between(tm(15, 0), tm(16, 0))),
+		MustRange(Between(tm(13, 0), tm(14, 0))),
+		MustRange(Between(tm(12, 59), tm(14, 1))), // duplicate of the previous one
+		MustRange(Between(tm(13, 1), tm(15, 0))),  // same start, but different end
+		MustRange(Between(tm(15, 0), tm(15, 59))), // duplicate of the first one
+		MustRange(Between(tm(17, 0), tm(18, 0))),
+	}
+
+	assert.Equal(t, formattedRanges([]Range{
+		MustRange(Between(tm(12, 59), tm(14, 1))),
+		MustRange(Between(tm(13, 1), tm(15, 0))),
+		MustRange(Between(tm(15, 0), tm(16, 0))),
+		MustRange(Between(tm(17, 0), tm(18, 0))),
+	}, "15:04"), formattedRanges(DedupWithin(ranges, time.Minute), "15:04"))
+
+	assert.Empty(t, DedupWithin(nil, time.Minute))
+}
+
+func TestStatusWithin(t *testing.T) {
+	rng := MustRange(Between(tm(13, 0), tm(14, 0)))
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want string
+	}{
+		{name: "long before", now: tm(12, 0), want: "upcoming"},
+		{name: "just before start", now: tm(12, 59), want: "active"},
+		{name: "start", now: tm(13, 0), want: "active"},
+		{name: "middle", now: tm(13, 30), want: "active"},
+		{name: "just before end", now: tm(13, 59), want: "expired"},
+		{name: "end", now: tm(14, 0), want: "expired"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, skew := range []time.Duration{time.Minute, -time.Minute} {
+				var got []string
+				if UpcomingWithin(rng, tt.now, skew) {
+					got = append(got, "upcoming")
+				}
+				if ActiveWithin(rng, tt.now, skew) {
+					got = append(got, "active")
+				}
+				if ExpiredWithin(rng, tt.now, skew) {
+					got = append(got, "expired")
+				}
+				assert.Equal(t, []string{tt.want}, got)
+			}
+
+			assert.Equal(t, rng.Upcoming(tt.now), UpcomingWithin(rng, tt.now, 0))
+			assert.Equal(t, rng.Active(tt.now), ActiveWithin(rng, tt.now, 0))
+			assert.Equal(t, rng.Expired(tt.now), ExpiredWithin(rng, tt.now, 0))
+		})
+	}
+}
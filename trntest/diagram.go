@@ -0,0 +1,59 @@
+package trntest
+
+import (
+	"time"
+
+	"github.com/cappuccinotm/trn"
+)
+
+// Diagram parses the diagram like "-XXX--YYY-" into ranges, where each cell
+// lasts for the given duration and the first cell starts at base. Runs of
+// the same character, other than '-' and ' ', make a range, e.g. the
+// example above makes two ranges: [base+1c, base+4c] and [base+6c, base+9c].
+func Diagram(base time.Time, cell time.Duration, diagram string) []trn.Range {
+	var res []trn.Range
+	for _, r := range runs(base, cell, diagram) {
+		res = append(res, r.rng)
+	}
+	return res
+}
+
+// DiagramByLetter parses the diagram like Diagram does, but groups the
+// ranges by the character they are drawn with, e.g. "-XXX-YY-XX-" makes two
+// ranges for 'X' and one for 'Y'.
+func DiagramByLetter(base time.Time, cell time.Duration, diagram string) map[rune][]trn.Range {
+	res := map[rune][]trn.Range{}
+	for _, r := range runs(base, cell, diagram) {
+		res[r.letter] = append(res[r.letter], r.rng)
+	}
+	return res
+}
+
+type run struct {
+	letter rune
+	rng    trn.Range
+}
+
+// runs returns the runs of the same character in the diagram.
+func runs(base time.Time, cell time.Duration, diagram string) []run {
+	var res []run
+	cells := []rune(diagram)
+	for i := 0; i < len(cells); {
+		if cells[i] == '-' || cells[i] == ' ' {
+			i++
+			continue
+		}
+
+		j := i
+		for j < len(cells) && cells[j] == cells[i] {
+			j++
+		}
+
+		res = append(res, run{
+			letter: cells[i],
+			rng:    trn.New(base.Add(time.Duration(i)*cell), time.Duration(j-i)*cell),
+		})
+		i = j
+	}
+	return res
+}
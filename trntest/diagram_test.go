@@ -0,0 +1,32 @@
+package trntest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cappuccinotm/trn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiagram(t *testing.T) {
+	b := At(time.Date(2021, 6, 12, 0, 0, 0, 0, time.UTC))
+	base := b.Time("09:00")
+
+	assert.Equal(t,
+		b.Ranges("09:10-09:40", "10:00-10:30"),
+		Diagram(base, 10*time.Minute, "-XXX--YYY-"),
+	)
+	assert.Equal(t,
+		b.Ranges("09:00-09:20", "09:20-09:30", "09:40-10:00"),
+		Diagram(base, 10*time.Minute, "XXY XX"),
+	)
+	assert.Empty(t, Diagram(base, time.Minute, "----"))
+
+	assert.Equal(t,
+		map[rune][]trn.Range{
+			'X': b.Ranges("09:01-09:04", "09:08-09:10"),
+			'Y': b.Ranges("09:05-09:07"),
+		},
+		DiagramByLetter(base, time.Minute, "-XXX-YY-XX-"),
+	)
+}
@@ -0,0 +1,56 @@
+// Package trntest provides helpers to build trn ranges in tests.
+package trntest
+
+import (
+	"time"
+
+	"github.com/cappuccinotm/trn"
+	"github.com/cappuccinotm/trn/store"
+)
+
+// Builder makes ranges of clock strings relative to the base date.
+// All its methods panic on malformed input, as it is meant to be used only
+// in tests.
+type Builder struct {
+	date store.Date
+	loc  *time.Location
+}
+
+// At returns the builder with the date and location of the base time.
+func At(base time.Time) Builder { return Builder{date: store.DateOf(base), loc: base.Location()} }
+
+// Day returns the builder shifted by n days, n might be negative.
+func (b Builder) Day(n int) Builder { return Builder{date: b.date.AddDays(n), loc: b.loc} }
+
+// Time returns the time at the clock on the base date, the clock is in
+// format "15:04" or "15:04:05".
+func (b Builder) Time(clock string) time.Time {
+	c, err := store.ParseClock(clock)
+	if err != nil {
+		panic(err)
+	}
+	return b.date.At(c, b.loc)
+}
+
+// Range returns the range between the clocks on the base date. If the end
+// is not after the start, the range ends on the next day.
+func (b Builder) Range(start, end string) trn.Range {
+	tr, err := store.ParseTimeRange(start + "-" + end)
+	if err != nil {
+		panic(err)
+	}
+	return store.Between(b.date, tr, b.loc)
+}
+
+// Ranges returns the ranges of specs in format "13:00-14:30".
+func (b Builder) Ranges(specs ...string) []trn.Range {
+	res := make([]trn.Range, len(specs))
+	for i, spec := range specs {
+		tr, err := store.ParseTimeRange(spec)
+		if err != nil {
+			panic(err)
+		}
+		res[i] = store.Between(b.date, tr, b.loc)
+	}
+	return res
+}
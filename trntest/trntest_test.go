@@ -0,0 +1,34 @@
+package trntest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cappuccinotm/trn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilder(t *testing.T) {
+	loc := time.FixedZone("", 2*60*60)
+	b := At(time.Date(2021, 6, 12, 23, 15, 0, 0, loc))
+
+	assert.Equal(t, time.Date(2021, 6, 12, 9, 30, 5, 0, loc), b.Time("09:30:05"))
+	assert.Equal(t, time.Date(2021, 6, 14, 9, 30, 0, 0, loc), b.Day(2).Time("09:30"))
+
+	assert.Equal(t,
+		trn.MustRange(trn.Between(time.Date(2021, 6, 12, 13, 0, 0, 0, loc), time.Date(2021, 6, 12, 14, 30, 0, 0, loc))),
+		b.Range("13:00", "14:30"),
+	)
+	assert.Equal(t,
+		trn.MustRange(trn.Between(time.Date(2021, 6, 12, 22, 0, 0, 0, loc), time.Date(2021, 6, 13, 2, 0, 0, 0, loc))),
+		b.Range("22:00", "02:00"),
+	)
+
+	assert.Equal(t, []trn.Range{b.Range("09:00", "10:00"), b.Range("13:00", "14:30")},
+		b.Ranges("09:00-10:00", "13:00 - 14:30"))
+	assert.Empty(t, b.Ranges())
+
+	assert.Panics(t, func() { b.Time("9:30") })
+	assert.Panics(t, func() { b.Range("13:00", "25:00") })
+	assert.Panics(t, func() { b.Ranges("13:00") })
+}
@@ -0,0 +1,113 @@
+package trn
+
+import (
+	"io/fs"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// LocationLoader loads the location by its IANA name, like
+// time.LoadLocation does.
+type LocationLoader func(name string) (*time.Location, error)
+
+// zoneLoader is the loader along with the cache of the zone names, which
+// it resolves, so that the results of the replaced loader never get into
+// the cache of the new one.
+type zoneLoader struct {
+	load LocationLoader
+
+	mu       sync.RWMutex
+	loadable map[string]bool
+}
+
+// locationLoader is the package-wide *zoneLoader, accessed atomically.
+var locationLoader atomic.Value
+
+// SetLocationLoader sets the package-wide loader of the time zones, used by
+// the parsers and codecs, which restore the zone names, e.g. the one made by
+// ZoneinfoLoader of the zoneinfo files pinned to a certain version, so the
+// containers without tzdata behave deterministically. Nil loader restores
+// time.LoadLocation.
+func SetLocationLoader(l LocationLoader) {
+	if l == nil {
+		l = time.LoadLocation
+	}
+	locationLoader.Store(&zoneLoader{load: l})
+}
+
+// currentLoader returns the package-wide loader.
+func currentLoader() *zoneLoader {
+	if zl, ok := locationLoader.Load().(*zoneLoader); ok {
+		return zl
+	}
+
+	// the first caller sets the default one, so its cache is shared
+	locationLoader.CompareAndSwap(nil, &zoneLoader{load: time.LoadLocation})
+	return locationLoader.Load().(*zoneLoader)
+}
+
+// IsLoadable returns true if the package-wide loader resolves the zone
+// name, e.g. to find out whether the name of the location is enough to
+// restore it. The results are cached until the loader is replaced.
+func IsLoadable(name string) bool {
+	zl := currentLoader()
+
+	zl.mu.RLock()
+	ok, cached := zl.loadable[name]
+	zl.mu.RUnlock()
+	if cached {
+		return ok
+	}
+
+	_, err := zl.load(name)
+	ok = err == nil
+
+	zl.mu.Lock()
+	defer zl.mu.Unlock()
+	if zl.loadable == nil {
+		zl.loadable = map[string]bool{}
+	}
+	zl.loadable[name] = ok
+	return ok
+}
+
+// LoadLocation loads the location with the package-wide loader.
+func LoadLocation(name string) (*time.Location, error) { return currentLoader().load(name) }
+
+// ZoneinfoLoader returns the loader of the locations from the zoneinfo
+// files in the file system, e.g. embed.FS with the copy of the
+// /usr/share/zoneinfo directory, where the location "Europe/Berlin" is read
+// from the file "Europe/Berlin". "UTC" and "Local", as well as the empty
+// name, are resolved as by time.LoadLocation. The loaded locations are
+// cached.
+func ZoneinfoLoader(fsys fs.FS) LocationLoader {
+	var mu sync.Mutex
+	cache := map[string]*time.Location{}
+
+	return func(name string) (*time.Location, error) {
+		if name == "" || name == "UTC" || name == "Local" {
+			return time.LoadLocation(name)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if loc, ok := cache[name]; ok {
+			return loc, nil
+		}
+
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+
+		loc, err := time.LoadLocationFromTZData(name, data)
+		if err != nil {
+			return nil, err
+		}
+
+		cache[name] = loc
+		return loc, nil
+	}
+}
@@ -0,0 +1,99 @@
+package trn
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetLocationLoader(t *testing.T) {
+	var loaded []string
+	SetLocationLoader(func(name string) (*time.Location, error) {
+		loaded = append(loaded, name)
+		if name == "Europe/Berlin" {
+			return time.FixedZone(name, 2*60*60), nil
+		}
+		return nil, errors.New("unknown zone")
+	})
+	defer SetLocationLoader(nil)
+
+	var r Range
+	require.NoError(t, json.Unmarshal([]byte(`{"start":"2021-06-12T13:00:00+02:00[Europe/Berlin]",`+
+		`"end":"2021-06-12T14:00:00+02:00[Europe/Berlin]"}`), &r))
+	assert.Equal(t, "Europe/Berlin", r.Start().Location().String())
+	assert.True(t, r.Start().Equal(tm(11, 0)))
+
+	_, err := ParseRFC9557("2021-06-12T13:00:00+02:00[Europe/Paris]")
+	assert.ErrorIs(t, err, ErrMalformedTimestamp)
+	assert.Equal(t, []string{"Europe/Berlin", "Europe/Berlin", "Europe/Paris"}, loaded)
+
+	SetLocationLoader(nil)
+	loc, err := LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Paris", loc.String())
+}
+
+func TestIsLoadable(t *testing.T) {
+	defer SetLocationLoader(nil)
+
+	calls := 0
+	SetLocationLoader(func(name string) (*time.Location, error) {
+		calls++
+		return time.FixedZone(name, 0), nil
+	})
+	assert.True(t, IsLoadable("Mars/Olympus"))
+	assert.True(t, IsLoadable("Mars/Olympus"))
+	assert.Equal(t, 1, calls, "cached")
+
+	t.Run("replaced while loading", func(t *testing.T) {
+		started, release := make(chan struct{}), make(chan struct{})
+		SetLocationLoader(func(name string) (*time.Location, error) {
+			close(started)
+			<-release
+			return time.FixedZone(name, 0), nil
+		})
+
+		done := make(chan bool)
+		go func() { done <- IsLoadable("Mars/Olympus") }()
+		<-started
+
+		SetLocationLoader(func(string) (*time.Location, error) { return nil, errors.New("unknown zone") })
+		close(release)
+		assert.True(t, <-done)
+
+		// the result of the replaced loader is not cached for the new one
+		assert.False(t, IsLoadable("Mars/Olympus"))
+	})
+}
+
+func TestZoneinfoLoader(t *testing.T) {
+	data, err := os.ReadFile("/usr/share/zoneinfo/Europe/Berlin")
+	if err != nil {
+		t.Skipf("no system zoneinfo: %v", err)
+	}
+
+	load := ZoneinfoLoader(fstest.MapFS{"Europe/Berlin": &fstest.MapFile{Data: data}})
+
+	loc, err := load("Europe/Berlin")
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Berlin", loc.String())
+	_, off := time.Date(2021, time.June, 12, 12, 0, 0, 0, loc).Zone()
+	assert.Equal(t, 2*60*60, off)
+
+	again, err := load("Europe/Berlin")
+	require.NoError(t, err)
+	assert.Same(t, loc, again)
+
+	_, err = load("Europe/Paris")
+	assert.Error(t, err)
+
+	loc, err = load("UTC")
+	require.NoError(t, err)
+	assert.Equal(t, time.UTC, loc)
+}
@@ -0,0 +1,90 @@
+package trn
+
+import (
+	"sort"
+	"time"
+)
+
+// BookingPolicy defines the constraints checked by ValidateBookings.
+type BookingPolicy struct {
+	// Now is the time of booking, the current time if zero.
+	Now time.Time
+	// MinNotice is the minimal time between Now and the start of the
+	// booking.
+	MinNotice time.Duration
+	// Buffer is the minimal gap between the bookings.
+	Buffer time.Duration
+	// Skew is the tolerated clock skew of Now, the candidate is considered
+	// starting in time if it starts no sooner than MinNotice minus Skew
+	// after Now.
+	Skew time.Duration
+}
+
+// ValidateBookings checks the candidate bookings against the availability
+// and the policy, e.g. for the cart with multiple slots, and returns the
+// errors for each candidate in the order of candidates, nil for the valid
+// ones. The errors are BookingError, matching:
+//   - ErrShortNotice if the candidate starts sooner than MinNotice after Now;
+//   - ErrNotAvailable if the candidate is not fully within the availability;
+//   - ErrConflict if the candidate overlaps another one;
+//   - ErrTooClose if the gap between the candidate and another one is
+//     shorter than Buffer.
+//
+// Only the first failed check is reported for each candidate, in the order
+// above. Availability may be unsorted and overlapping.
+func ValidateBookings(availability, candidates []Range, policy BookingPolicy) []error {
+	now := policy.Now
+	if now.IsZero() {
+		now = timeNow()
+	}
+
+	free := MergeOverlappingRanges(availability)
+	errs := make([]error, len(candidates))
+	fail := func(i, other int, err error) {
+		// the sweep may find the conflict after the too close neighbour,
+		// the conflict is checked first and takes precedence
+		if errs[i] == nil || err == ErrConflict && errs[i].(BookingError).Err == ErrTooClose {
+			errs[i] = BookingError{Index: i, Range: candidates[i], Err: err, Other: other}
+		}
+	}
+
+	for i, c := range candidates {
+		if c.st.Before(now.Add(policy.MinNotice - absDuration(policy.Skew))) {
+			fail(i, -1, ErrShortNotice)
+			continue
+		}
+
+		// the first free range, which ends not before the candidate does
+		j := sort.Search(len(free), func(j int) bool { return !free[j].End().Before(c.End()) })
+		if j == len(free) || !free[j].Contains(c) {
+			fail(i, -1, ErrNotAvailable)
+		}
+	}
+
+	order := make([]int, len(candidates))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return lessRange(candidates[order[a]], candidates[order[b]]) })
+
+	// compare each candidate with the preceding one, which ends the latest
+	latest := -1
+	for _, i := range order {
+		if latest >= 0 {
+			gap := candidates[i].st.Sub(candidates[latest].End())
+			switch {
+			case gap < 0:
+				fail(i, latest, ErrConflict)
+				fail(latest, i, ErrConflict)
+			case gap < policy.Buffer:
+				fail(i, latest, ErrTooClose)
+				fail(latest, i, ErrTooClose)
+			}
+		}
+		if latest < 0 || candidates[i].End().After(candidates[latest].End()) {
+			latest = i
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,94 @@
+package trn
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateBookings(t *testing.T) {
+	availability := []Range{
+		MustRange(Between(tm(13, 0), tm(18, 0))),
+		MustRange(Between(tm(9, 0), tm(12, 0))),
+	}
+	candidates := []Range{
+		MustRange(Between(tm(9, 0), tm(9, 30))),    // 0: too soon
+		MustRange(Between(tm(10, 0), tm(11, 0))),   // 1: ok
+		MustRange(Between(tm(11, 30), tm(12, 30))), // 2: spans the lunch
+		MustRange(Between(tm(14, 0), tm(15, 0))),   // 3: overlaps 4
+		MustRange(Between(tm(14, 30), tm(15, 30))), // 4: overlaps 3
+		MustRange(Between(tm(15, 40), tm(16, 0))),  // 5: too close to 4
+		MustRange(Between(tm(17, 0), tm(18, 0))),   // 6: ok
+	}
+
+	errs := ValidateBookings(availability, candidates, BookingPolicy{
+		Now:       tm(8, 0),
+		MinNotice: 90 * time.Minute,
+		Buffer:    15 * time.Minute,
+	})
+	require.Len(t, errs, len(candidates))
+
+	assert.ErrorIs(t, errs[0], ErrShortNotice)
+	assert.NoError(t, errs[1])
+	assert.ErrorIs(t, errs[2], ErrNotAvailable)
+	assert.ErrorIs(t, errs[3], ErrConflict)
+	assert.ErrorIs(t, errs[4], ErrConflict)
+	assert.ErrorIs(t, errs[5], ErrTooClose)
+	assert.NoError(t, errs[6])
+
+	var be BookingError
+	require.True(t, errors.As(errs[3], &be))
+	assert.Equal(t, BookingError{Index: 3, Range: candidates[3], Err: ErrConflict, Other: 4}, be)
+	assert.Equal(t, "candidate 3 [2021-06-12T14:00:00Z, 2021-06-12T15:00:00Z]: "+
+		"trn: ranges overlap with candidate 4", be.Error())
+
+	require.True(t, errors.As(errs[0], &be))
+	assert.Equal(t, -1, be.Other)
+	assert.Equal(t, "candidate 0 [2021-06-12T09:00:00Z, 2021-06-12T09:30:00Z]: "+
+		"trn: range starts too soon", be.Error())
+
+	t.Run("nested", func(t *testing.T) {
+		errs := ValidateBookings(availability, []Range{
+			MustRange(Between(tm(13, 0), tm(17, 0))),
+			MustRange(Between(tm(14, 0), tm(15, 0))),
+			MustRange(Between(tm(16, 0), tm(16, 30))),
+		}, BookingPolicy{Now: tm(8, 0)})
+		for _, err := range errs {
+			assert.ErrorIs(t, err, ErrConflict)
+		}
+	})
+
+	t.Run("skew", func(t *testing.T) {
+		candidates := []Range{MustRange(Between(tm(9, 29), tm(10, 0)))}
+		policy := BookingPolicy{Now: tm(8, 0), MinNotice: 90 * time.Minute}
+		assert.ErrorIs(t, ValidateBookings(availability, candidates, policy)[0], ErrShortNotice)
+
+		policy.Skew = time.Minute
+		assert.NoError(t, ValidateBookings(availability, candidates, policy)[0])
+	})
+
+	t.Run("adjacent", func(t *testing.T) {
+		errs := ValidateBookings(availability, []Range{
+			MustRange(Between(tm(13, 0), tm(14, 0))),
+			MustRange(Between(tm(14, 0), tm(15, 0))),
+		}, BookingPolicy{Now: tm(8, 0)})
+		assert.Equal(t, []error{nil, nil}, errs)
+	})
+
+	t.Run("conflict after too close", func(t *testing.T) {
+		candidates := []Range{
+			MustRange(Between(tm(9, 0), tm(10, 0))),
+			MustRange(Between(tm(10, 5), tm(11, 0))),
+			MustRange(Between(tm(10, 30), tm(11, 30))),
+		}
+		errs := ValidateBookings(availability, candidates, BookingPolicy{Now: tm(8, 0), Buffer: 10 * time.Minute})
+		require.Len(t, errs, len(candidates))
+
+		assert.ErrorIs(t, errs[0], ErrTooClose)
+		assert.Equal(t, BookingError{Index: 1, Range: candidates[1], Err: ErrConflict, Other: 2}, errs[1])
+		assert.Equal(t, BookingError{Index: 2, Range: candidates[2], Err: ErrConflict, Other: 1}, errs[2])
+	})
+}
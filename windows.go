@@ -0,0 +1,73 @@
+package trn
+
+import "time"
+
+// RollingWindow returns the window of the given duration, which ends at now,
+// e.g. "the last hour" for rate limits.
+func RollingWindow(now time.Time, d time.Duration) Range {
+	return Range{st: now.Add(-d), dur: d}
+}
+
+// WindowAt returns the fixed window of the given duration, which contains
+// t, where the windows are aligned to the origin, i.e. each window starts
+// at origin + k*d for some integer k.
+// Returns empty range if the duration is not positive.
+func WindowAt(t time.Time, d time.Duration, origin time.Time) Range {
+	if d <= 0 {
+		return Range{}
+	}
+
+	offset := t.Sub(origin)
+	k := offset / d
+	if offset%d < 0 {
+		k-- // floor division for the times before the origin
+	}
+
+	return Range{st: origin.Add(k * d).In(t.Location()), dur: d}
+}
+
+// FixedWindows returns the consecutive fixed windows of the given duration,
+// aligned to the origin, which intersect the period, e.g. calendar-hour
+// quota windows. The first and the last windows may extend beyond the
+// period. Returns nil if the duration is not positive.
+// Of the split options, only MaxSlots is taken into account, returns
+// TooManySlotsError, matching ErrTooManySlots, if the number of the windows
+// exceeds it.
+func FixedWindows(period Range, d time.Duration, origin time.Time, opts ...SplitOption) ([]Range, error) {
+	if d <= 0 {
+		return nil, nil
+	}
+
+	o := newSplitOptions(opts)
+	first := WindowAt(period.st, d, origin)
+
+	// the window containing the start, and the ones up to the period end
+	span := period.End().Sub(first.st)
+	slots := int64(span / d)
+	if span%d != 0 || slots == 0 {
+		slots++
+	}
+
+	if o.maxSlots > 0 && slots > int64(o.maxSlots) {
+		return nil, TooManySlotsError{Slots: slots, Max: o.maxSlots}
+	}
+
+	res := make([]Range, 0, prealloc(slots))
+	res = append(res, first)
+	for end := period.End(); ; {
+		next := Range{st: res[len(res)-1].End(), dur: d}
+		if !next.st.Before(end) {
+			break
+		}
+		res = append(res, next)
+	}
+
+	return res, nil
+}
+
+// ContainsTime returns true if t is within the range. Unlike Contains, the
+// end of the range is exclusive, so that the adjacent ranges, e.g. fixed
+// windows, never contain the same instant.
+func (r Range) ContainsTime(t time.Time) bool {
+	return !t.Before(r.st) && t.Before(r.End())
+}
@@ -0,0 +1,78 @@
+package trn
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRollingWindow(t *testing.T) {
+	assert.Equal(t, "[12:00, 13:00]", RollingWindow(tm(13, 0), time.Hour).Format("15:04"))
+}
+
+func TestWindowAt(t *testing.T) {
+	origin := tm(12, 0)
+	tests := []struct {
+		name string
+		t    time.Time
+		want string
+	}{
+		{name: "at the origin", t: tm(12, 0), want: "[12:00, 12:15]"},
+		{name: "after the origin", t: tm(13, 20), want: "[13:15, 13:30]"},
+		{name: "at the window boundary", t: tm(13, 30), want: "[13:30, 13:45]"},
+		{name: "before the origin", t: tm(11, 50), want: "[11:45, 12:00]"},
+		{name: "before the origin at the boundary", t: tm(11, 45), want: "[11:45, 12:00]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, WindowAt(tt.t, 15*time.Minute, origin).Format("15:04"))
+		})
+	}
+
+	assert.True(t, WindowAt(tm(13, 0), 0, origin).Empty())
+}
+
+func TestFixedWindows(t *testing.T) {
+	origin := tm(0, 0)
+
+	got, err := FixedWindows(MustRange(Between(tm(13, 10), tm(14, 0))), 20*time.Minute, origin)
+	require.NoError(t, err)
+	assert.Equal(t, formattedRanges([]Range{
+		MustRange(Between(tm(13, 0), tm(13, 20))),
+		MustRange(Between(tm(13, 20), tm(13, 40))),
+		MustRange(Between(tm(13, 40), tm(14, 0))),
+	}, "15:04"), formattedRanges(got, "15:04"))
+
+	got, err = FixedWindows(New(tm(13, 10), 0), 20*time.Minute, origin)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "[13:00, 13:20]", got[0].Format("15:04"))
+
+	got, err = FixedWindows(New(tm(13, 10), time.Hour), 0, origin)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	t.Run("max slots", func(t *testing.T) {
+		period := MustRange(Between(tm(13, 10), tm(14, 0)))
+		got, err := FixedWindows(period, 20*time.Minute, origin, MaxSlots(3))
+		require.NoError(t, err)
+		assert.Len(t, got, 3)
+
+		_, err = FixedWindows(period, 20*time.Minute, origin, MaxSlots(2))
+		assert.Equal(t, TooManySlotsError{Slots: 3, Max: 2}, err)
+
+		_, err = FixedWindows(New(tm(0, 0), math.MaxInt64), time.Nanosecond, origin, MaxSlots(1000))
+		assert.ErrorIs(t, err, ErrTooManySlots)
+	})
+}
+
+func TestRange_ContainsTime(t *testing.T) {
+	rng := MustRange(Between(tm(13, 0), tm(14, 0)))
+	assert.True(t, rng.ContainsTime(tm(13, 0)))
+	assert.True(t, rng.ContainsTime(tm(13, 59)))
+	assert.False(t, rng.ContainsTime(tm(14, 0)))
+	assert.False(t, rng.ContainsTime(tm(12, 59)))
+}